@@ -26,21 +26,37 @@ func main() {
 	}
 	slog.SetDefault(logger)
 
-	executionRepository, err := cli.CreateExecutionRepositoryFromConfig(command.Store)
-	if err != nil {
+	if err := cli.ExportStoreConfig(command.Store); err != nil {
 		ctx.FatalIfErrorf(err)
 	}
-	ctx.BindTo(executionRepository, (*agent.ExecutionRepository)(nil))
+	ctx.Bind(command.Store)
 
-	configRepository, err := cli.CreateConfigRepositoryFromConfig(command.Store)
+	store, err := cli.OpenStoreFromConfig(command.Store)
 	if err != nil {
 		ctx.FatalIfErrorf(err)
 	}
-	ctx.BindTo(configRepository, (*agent.ConfigRepository)(nil))
+	ctx.BindTo(store.Executions, (*agent.ExecutionRepository)(nil))
+	ctx.BindTo(store.Configs, (*agent.ConfigRepository)(nil))
 
-	ctx.BindTo(runtime.NewRegistry(), (*agent.RuntimeRegistry)(nil))
+	sessionRepository, err := cli.CreateSessionRepositoryFromConfig(command.Store)
+	if err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+	ctx.BindTo(sessionRepository, (*agent.SessionRepository)(nil))
 
 	cliCtx := context.Background()
+
+	pluginDir, err := cli.ResolveRuntimePluginDir()
+	if err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+
+	runtimeRegistry := runtime.NewRegistry()
+	if err := runtimeRegistry.LoadPlugins(cliCtx, pluginDir); err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+	ctx.BindTo(runtimeRegistry, (*agent.RuntimeRegistry)(nil))
+
 	err = ctx.BindToProvider(func() (context.Context, error) {
 		return cliCtx, nil
 	})
@@ -49,5 +65,8 @@ func main() {
 	}
 
 	err = ctx.Run()
+	if closeErr := store.Close(); closeErr != nil {
+		slog.Warn("Failed to close the store.", slog.String("error", closeErr.Error()))
+	}
 	ctx.FatalIfErrorf(err)
 }
@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/alecthomas/kong"
 	briefkit_mcp "github.com/orbiqd/orbiqd-briefkit/internal/app/briefkit-mcp"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/runtime"
 )
 
 func main() {
@@ -26,18 +28,37 @@ func main() {
 		ctx.FatalIfErrorf(err)
 	}
 
-	executionRepository, err := cli.CreateExecutionRepositoryFromConfig(command.Store)
+	if err := cli.ExportStoreConfig(command.Store); err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+
+	store, err := cli.OpenStoreFromConfig(command.Store)
 	if err != nil {
 		ctx.FatalIfErrorf(err)
 	}
-	ctx.BindTo(executionRepository, (*agent.ExecutionRepository)(nil))
+	ctx.BindTo(store.Executions, (*agent.ExecutionRepository)(nil))
+	ctx.BindTo(store.Configs, (*agent.ConfigRepository)(nil))
 
-	configRepository, err := cli.CreateConfigRepositoryFromConfig(command.Store)
+	sessionRepository, err := cli.CreateSessionRepositoryFromConfig(command.Store)
 	if err != nil {
 		ctx.FatalIfErrorf(err)
 	}
-	ctx.BindTo(configRepository, (*agent.ConfigRepository)(nil))
+	ctx.BindTo(sessionRepository, (*agent.SessionRepository)(nil))
+
+	pluginDir, err := cli.ResolveRuntimePluginDir()
+	if err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+
+	runtimeRegistry := runtime.NewRegistry()
+	if err := runtimeRegistry.LoadPlugins(cliCtx, pluginDir); err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+	ctx.BindTo(runtimeRegistry, (*agent.RuntimeRegistry)(nil))
 
 	err = ctx.Run()
+	if closeErr := store.Close(); closeErr != nil {
+		slog.Warn("Failed to close the store.", slog.String("error", closeErr.Error()))
+	}
 	ctx.FatalIfErrorf(err)
 }
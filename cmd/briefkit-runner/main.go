@@ -26,15 +26,38 @@ func main() {
 	}
 	slog.SetDefault(logger)
 
-	executionRepository, err := cli.CreateExecutionRepositoryFromConfig(command.Store)
+	store, err := cli.OpenStoreFromConfig(command.Store)
 	if err != nil {
 		ctx.FatalIfErrorf(err)
 	}
-	ctx.BindTo(executionRepository, (*agent.ExecutionRepository)(nil))
+	ctx.BindTo(store.Executions, (*agent.ExecutionRepository)(nil))
+	ctx.BindTo(store.Configs, (*agent.ConfigRepository)(nil))
 
-	ctx.BindTo(runtime.NewRegistry(), (*agent.RuntimeRegistry)(nil))
+	sessionRepository, err := cli.CreateSessionRepositoryFromConfig(command.Store)
+	if err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+	ctx.BindTo(sessionRepository, (*agent.SessionRepository)(nil))
+
+	turnRepository, err := cli.CreateTurnRepositoryFromConfig(command.Store)
+	if err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+	ctx.BindTo(turnRepository, (*agent.TurnRepository)(nil))
 
 	cliCtx := context.Background()
+
+	pluginDir, err := cli.ResolveRuntimePluginDir()
+	if err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+
+	runtimeRegistry := runtime.NewRegistry()
+	if err := runtimeRegistry.LoadPlugins(cliCtx, pluginDir); err != nil {
+		ctx.FatalIfErrorf(err)
+	}
+	ctx.BindTo(runtimeRegistry, (*agent.RuntimeRegistry)(nil))
+
 	err = ctx.BindToProvider(func() (context.Context, error) {
 		return cliCtx, nil
 	})
@@ -43,5 +66,8 @@ func main() {
 	}
 
 	err = ctx.Run()
+	if closeErr := store.Close(); closeErr != nil {
+		slog.Warn("Failed to close the store.", slog.String("error", closeErr.Error()))
+	}
 	ctx.FatalIfErrorf(err)
 }
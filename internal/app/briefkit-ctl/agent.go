@@ -2,6 +2,8 @@ package briefkitctl
 
 type AgentCmd struct {
 	List      AgentListCmd      `cmd:"" help:"List configured agents"`
+	Models    AgentModelsCmd    `cmd:"" help:"Show allowed models and aliases of an agent"`
 	Add       AgentAddCmd       `cmd:"" help:"Add new agent"`
+	Remove    AgentRemoveCmd    `cmd:"" help:"Remove an agent"`
 	Discovery AgentDiscoveryCmd `cmd:"" name:"discovery" help:"Discover agents"`
 }
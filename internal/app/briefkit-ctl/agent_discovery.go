@@ -48,8 +48,18 @@ func (command *AgentDiscoveryCmd) Run(ctx context.Context, runtimeRegistry agent
 		slog.Info("Agent runtime discovered.",
 			slog.String("runtimeKind", string(runtimeKind)),
 			slog.String("runtimeVersion", runtimeInfo.Version),
+			slog.String("supportedVersions", runtimeInfo.SupportedVersions),
 		)
 
+		if err := runtimeInfo.CheckVersion(""); err != nil {
+			slog.Warn("Agent runtime version is not supported, output parsing may fail.",
+				slog.String("runtimeKind", string(runtimeKind)),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		command.warnIncompatibleAgents(ctx, configRepository, runtimeKind, runtimeInfo)
+
 		if command.WriteDefaultConfig {
 			agentConfig := agent.Config{}
 
@@ -78,3 +88,26 @@ func (command *AgentDiscoveryCmd) Run(ctx context.Context, runtimeRegistry agent
 
 	return nil
 }
+
+// warnIncompatibleAgents reports configured agents whose version pin does not match the discovered runtime.
+func (command *AgentDiscoveryCmd) warnIncompatibleAgents(ctx context.Context, configRepository agent.ConfigRepository, runtimeKind agent.RuntimeKind, runtimeInfo agent.RuntimeInfo) {
+	agentIds, err := configRepository.List(ctx)
+	if err != nil {
+		slog.Warn("Failed to list agents.", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, agentId := range agentIds {
+		agentConfig, err := configRepository.Get(ctx, agentId)
+		if err != nil || agentConfig.Runtime.Kind != runtimeKind || agentConfig.Runtime.Version == "" {
+			continue
+		}
+
+		if err := agentConfig.CheckRuntimeVersion(runtimeInfo); err != nil {
+			slog.Warn("Agent will refuse to run because its runtime version pin is not satisfied.",
+				slog.String("agentId", string(agentId)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
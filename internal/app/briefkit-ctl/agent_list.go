@@ -14,6 +14,9 @@ import (
 type AgentListOutputItem struct {
 	ID          agent.AgentID     `json:"id"`
 	RuntimeKind agent.RuntimeKind `json:"runtimeKind"`
+
+	// Capabilities reports the features supported by the agent runtime. Nil when the runtime is unavailable.
+	Capabilities *agent.RuntimeCapabilities `json:"capabilities"`
 }
 
 // AgentListOutput captures the list output payload for agents.
@@ -22,11 +25,11 @@ type AgentListOutput struct {
 	Count int                   `json:"count"`
 }
 
-// AgentListCmd lists configured agents and their runtime kinds.
+// AgentListCmd lists configured agents with their runtime kinds and capabilities.
 type AgentListCmd struct{}
 
 // Run executes the agent list command.
-func (a *AgentListCmd) Run(ctx context.Context, repository agent.ConfigRepository) error {
+func (a *AgentListCmd) Run(ctx context.Context, repository agent.ConfigRepository, runtimeRegistry agent.RuntimeRegistry) error {
 	ids, err := repository.List(ctx)
 	if err != nil {
 		return fmt.Errorf("list agents: %w", err)
@@ -45,8 +48,9 @@ func (a *AgentListCmd) Run(ctx context.Context, repository agent.ConfigRepositor
 		}
 
 		items = append(items, AgentListOutputItem{
-			ID:          id,
-			RuntimeKind: config.Runtime.Kind,
+			ID:           id,
+			RuntimeKind:  config.Runtime.Kind,
+			Capabilities: getRuntimeCapabilities(ctx, runtimeRegistry, config.Runtime.Kind),
 		})
 	}
 
@@ -63,3 +67,27 @@ func (a *AgentListCmd) Run(ctx context.Context, repository agent.ConfigRepositor
 
 	return nil
 }
+
+func getRuntimeCapabilities(ctx context.Context, runtimeRegistry agent.RuntimeRegistry, kind agent.RuntimeKind) *agent.RuntimeCapabilities {
+	runtime, err := runtimeRegistry.Get(ctx, kind)
+	if err != nil {
+		slog.Warn(
+			"Failed to get agent runtime",
+			slog.String("runtimeKind", string(kind)),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+
+	capabilities, err := runtime.GetCapabilities(ctx)
+	if err != nil {
+		slog.Warn(
+			"Failed to get runtime capabilities",
+			slog.String("runtimeKind", string(kind)),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+
+	return &capabilities
+}
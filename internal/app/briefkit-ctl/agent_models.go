@@ -0,0 +1,65 @@
+package briefkitctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// AgentModelsOutput captures the model catalog of a single agent.
+type AgentModelsOutput struct {
+	ID          agent.AgentID     `json:"id"`
+	RuntimeKind agent.RuntimeKind `json:"runtimeKind"`
+
+	// Allowed lists the models accepted by the agent. Empty allows any model.
+	Allowed []string `json:"allowed"`
+
+	// Aliases maps short names to model identifiers.
+	Aliases map[string]string `json:"aliases"`
+
+	// KnownModels lists the models the runtime knows of. Built-in runtimes cannot ask their CLI,
+	// so for them it is a static list of well-known models, not the CLI's catalog.
+	KnownModels []string `json:"knownModels"`
+}
+
+// AgentModelsCmd shows the allowed models, aliases and known models of an agent.
+type AgentModelsCmd struct {
+	ID agent.AgentID `arg:"" required:"" help:"Agent ID"`
+}
+
+// Run executes the agent models command.
+func (a *AgentModelsCmd) Run(ctx context.Context, repository agent.ConfigRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	config, err := repository.Get(ctx, a.ID)
+	if err != nil {
+		return fmt.Errorf("get agent config: %w", err)
+	}
+
+	runtime, err := runtimeRegistry.Get(ctx, config.Runtime.Kind)
+	if err != nil {
+		return fmt.Errorf("get runtime %s: %w", config.Runtime.Kind, err)
+	}
+
+	knownModels, err := runtime.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("list runtime models: %w", err)
+	}
+
+	output := AgentModelsOutput{
+		ID:          a.ID,
+		RuntimeKind: config.Runtime.Kind,
+		Allowed:     config.Models.Allowed,
+		Aliases:     config.Models.Aliases,
+		KnownModels: knownModels,
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("encode agent models output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package briefkitctl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// AgentRemoveCmd removes an agent config. Executions of the agent keep the config they were created with.
+type AgentRemoveCmd struct {
+	ID agent.AgentID `arg:"" required:"" help:"Agent ID"`
+}
+
+// Run executes the agent remove command.
+func (a *AgentRemoveCmd) Run(ctx context.Context, repository agent.ConfigRepository) error {
+	if err := repository.Delete(ctx, a.ID); err != nil {
+		return fmt.Errorf("remove agent %s: %w", a.ID, err)
+	}
+
+	slog.Info("Removed agent.", slog.String("agentID", string(a.ID)))
+
+	return nil
+}
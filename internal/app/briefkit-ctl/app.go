@@ -10,7 +10,10 @@ type Command struct {
 	Log   cli.LogConfig   `embed:"" prefix:"log-"`
 	Store cli.StoreConfig `embed:"" prefix:"store-"`
 
-	Agent AgentCmd `cmd:"" help:"Manage agents"`
-	State StateCmd `cmd:"" help:"Manage state"`
-	Exec  ExecCmd  `cmd:"" help:"Run a prompt with specified model"`
+	Agent   AgentCmd   `cmd:"" help:"Manage agents"`
+	State   StateCmd   `cmd:"" help:"Manage state"`
+	Exec    ExecCmd    `cmd:"" help:"Run a prompt with specified model"`
+	Handoff HandoffCmd `cmd:"" help:"Continue a session's conversation on another agent"`
+	Fork    ForkCmd    `cmd:"" help:"Branch a conversation off a past execution"`
+	Chat    ChatCmd    `cmd:"" help:"Chat with an agent over multiple turns"`
 }
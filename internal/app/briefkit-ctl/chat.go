@@ -0,0 +1,479 @@
+package briefkitctl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	briefkitrunner "github.com/orbiqd/orbiqd-briefkit/internal/app/briefkit-runner"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+)
+
+const chatHelp = `Commands:
+  /model [name|default]       Show or switch the model for the next turns.
+  /agent <id> [mode]          Hand the conversation off to another agent (mode: transcript or excerpt).
+  /attach [path]              List attachments or attach a file to the next turn.
+  /detach                     Drop the pending attachments.
+  /usage                      Show the tokens used in this chat.
+  /session                    Show the current session.
+  /help                       Show this help.
+  /exit                       Leave the chat.`
+
+// ChatCmd opens a line-oriented conversation with an agent.
+type ChatCmd struct {
+	AgentID      agent.AgentID    `help:"ID of the agent." required:"true"`
+	Timeout      time.Duration    `help:"Timeout of each turn." default:"5m"`
+	Model        *string          `help:"Select model for execution."`
+	SessionID    *agent.SessionID `help:"Session ID to continue. A new session is started with the first turn when omitted."`
+	SystemPrompt string           `help:"System prompt added after the agent instructions."`
+}
+
+// chat holds the state of a chat across turns.
+type chat struct {
+	agentID     agent.AgentID
+	agentConfig agent.Config
+	model       *string
+	sessionID   *agent.SessionID
+	attachments []agent.ExecutionInputAttachment
+
+	// handoff is the session whose turns seed the next turn after the agent was switched.
+	handoff     *agent.Session
+	handoffMode agent.HandoffMode
+
+	turns int
+	usage agent.RuntimeUsage
+}
+
+// Run reads prompts and slash commands from standard input until it is closed or /exit is entered.
+func (command *ChatCmd) Run(ctx context.Context, executionRepository agent.ExecutionRepository, agentConfigRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	state := &chat{
+		model:     command.Model,
+		sessionID: command.SessionID,
+	}
+
+	if err := command.switchAgent(ctx, state, agentConfigRepository, runtimeRegistry, command.AgentID); err != nil {
+		return err
+	}
+
+	if state.model != nil {
+		model, err := state.agentConfig.Models.Resolve(*state.model)
+		if err != nil {
+			return fmt.Errorf("resolve model: %w", err)
+		}
+		state.model = &model
+	}
+
+	if state.sessionID != nil {
+		session, err := sessionRepository.Get(ctx, *state.sessionID)
+		if err != nil {
+			return fmt.Errorf("get session: %w", err)
+		}
+
+		if session.AgentID != state.agentID {
+			return fmt.Errorf("%w: session %s belongs to agent %s", agent.ErrSessionAgentMismatch, session.ID, session.AgentID)
+		}
+	}
+
+	fmt.Printf("Chatting with %s. Type /help for commands.\n", state.agentID)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for {
+		fmt.Printf("%s> ", state.agentID)
+
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			done, err := command.runSlashCommand(ctx, state, line, agentConfigRepository, sessionRepository, runtimeRegistry)
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+			}
+			if done {
+				return nil
+			}
+			continue
+		}
+
+		if err := command.runTurn(ctx, state, line, executionRepository, sessionRepository, runtimeRegistry); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Printf("Error: %s\n", err)
+		}
+	}
+}
+
+// runSlashCommand applies a slash command to the chat. It reports true when the chat should end.
+func (command *ChatCmd) runSlashCommand(ctx context.Context, state *chat, line string, agentConfigRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) (bool, error) {
+	name, argument, _ := strings.Cut(line, " ")
+	argument = strings.TrimSpace(argument)
+
+	switch name {
+	case "/exit", "/quit":
+		return true, nil
+
+	case "/help":
+		fmt.Println(chatHelp)
+
+	case "/model":
+		switch argument {
+		case "":
+			model := "default"
+			if state.model != nil {
+				model = *state.model
+			}
+			fmt.Printf("Model: %s\n", model)
+			if choices := state.agentConfig.Models.Choices(); len(choices) > 0 {
+				fmt.Printf("Available: %s\n", strings.Join(choices, ", "))
+			}
+		case "default":
+			state.model = nil
+			fmt.Println("Using the agent's default model.")
+		default:
+			model, err := state.agentConfig.Models.Resolve(argument)
+			if err != nil {
+				return false, err
+			}
+			state.model = &model
+			fmt.Printf("Using model %s.\n", model)
+		}
+
+	case "/agent":
+		agentArgument, modeArgument, _ := strings.Cut(argument, " ")
+		if agentArgument == "" {
+			fmt.Printf("Agent: %s\n", state.agentID)
+			return false, nil
+		}
+
+		mode := agent.HandoffTranscript
+		if modeArgument = strings.TrimSpace(modeArgument); modeArgument != "" {
+			mode = agent.HandoffMode(modeArgument)
+		}
+		if err := mode.Validate(); err != nil {
+			return false, err
+		}
+
+		return false, command.handoff(ctx, state, agent.AgentID(agentArgument), mode, agentConfigRepository, sessionRepository, runtimeRegistry)
+
+	case "/attach":
+		if argument == "" {
+			if len(state.attachments) == 0 {
+				fmt.Println("No attachments.")
+			}
+			for _, attachment := range state.attachments {
+				fmt.Printf("%s (%s)\n", attachment.Path, attachment.MimeType)
+			}
+			return false, nil
+		}
+
+		attachment, err := newChatAttachment(argument)
+		if err != nil {
+			return false, err
+		}
+		state.attachments = append(state.attachments, attachment)
+		fmt.Printf("Attached %s (%s) to the next turn.\n", attachment.Path, attachment.MimeType)
+
+	case "/detach":
+		state.attachments = nil
+		fmt.Println("Attachments dropped.")
+
+	case "/usage":
+		fmt.Printf("Turns: %d\n", state.turns)
+		fmt.Printf("Input tokens: %d (%d cached)\n", state.usage.InputTokens, state.usage.CachedInputTokens)
+		fmt.Printf("Output tokens: %d\n", state.usage.OutputTokens)
+		if state.usage.CostUSD != nil {
+			fmt.Printf("Cost: $%.4f\n", *state.usage.CostUSD)
+		}
+
+	case "/session":
+		if state.sessionID == nil {
+			fmt.Println("No session yet; the first turn starts one.")
+			return false, nil
+		}
+		fmt.Printf("Session: %s\n", *state.sessionID)
+
+	default:
+		return false, fmt.Errorf("unknown command %s; type /help for commands", name)
+	}
+
+	return false, nil
+}
+
+// switchAgent loads and validates the agent config the next turns run on.
+func (command *ChatCmd) switchAgent(ctx context.Context, state *chat, agentConfigRepository agent.ConfigRepository, runtimeRegistry agent.RuntimeRegistry, agentID agent.AgentID) error {
+	agentConfig, err := agentConfigRepository.Get(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("get agent config %s: %w", agentID, err)
+	}
+
+	if err := agentConfig.Validate(ctx, runtimeRegistry); err != nil {
+		return fmt.Errorf("validate agent config %s: %w", agentID, err)
+	}
+
+	state.agentID = agentID
+	state.agentConfig = agentConfig
+
+	return nil
+}
+
+// handoff switches the chat to another agent. When the current session has turns,
+// the next turn starts a handoff session seeded with them.
+func (command *ChatCmd) handoff(ctx context.Context, state *chat, agentID agent.AgentID, mode agent.HandoffMode, agentConfigRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	if agentID == state.agentID {
+		return fmt.Errorf("already chatting with %s", agentID)
+	}
+
+	source := state.handoff
+	if state.sessionID != nil {
+		session, err := sessionRepository.Get(ctx, *state.sessionID)
+		if err != nil {
+			return fmt.Errorf("get session: %w", err)
+		}
+		source = &session
+	}
+
+	if err := command.switchAgent(ctx, state, agentConfigRepository, runtimeRegistry, agentID); err != nil {
+		return err
+	}
+
+	state.model = nil
+	state.sessionID = nil
+	state.handoff = source
+	state.handoffMode = mode
+
+	if source != nil {
+		fmt.Printf("Switched to %s; the next turn continues session %s (%s handoff).\n", agentID, source.ID, mode)
+	} else {
+		fmt.Printf("Switched to %s.\n", agentID)
+	}
+
+	return nil
+}
+
+// runTurn executes one prompt through the runner and streams the response.
+func (command *ChatCmd) runTurn(ctx context.Context, state *chat, prompt string, executionRepository agent.ExecutionRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	executionInput := agent.ExecutionInput{
+		Timeout:      utils.Duration(command.Timeout),
+		Prompt:       prompt,
+		Model:        state.model,
+		SystemPrompt: command.SystemPrompt,
+		Attachments:  state.attachments,
+	}
+
+	var err error
+	var instruction string
+	if state.sessionID != nil {
+		executionInput, err = agent.ContinueSession(ctx, sessionRepository, *state.sessionID, state.agentID, executionInput)
+		if err != nil {
+			return fmt.Errorf("continue session: %w", err)
+		}
+	} else if state.handoff != nil {
+		transcript, err := sessionRepository.GetTranscript(ctx, state.handoff.ID)
+		if err != nil {
+			return fmt.Errorf("get session transcript: %w", err)
+		}
+
+		// A source session without successful turns has nothing to hand off, so the prompt is sent as is.
+		handoffPrompt, err := agent.BuildHandoffPrompt(*state.handoff, transcript, state.handoffMode, prompt)
+		switch {
+		case err == nil:
+			executionInput.Prompt = handoffPrompt
+			instruction = prompt
+		case !errors.Is(err, agent.ErrHandoffTranscriptEmpty):
+			return fmt.Errorf("build handoff prompt: %w", err)
+		}
+		executionInput.WorkingDirectory = state.handoff.WorkingDirectory
+	}
+
+	if err := state.agentConfig.CheckCapabilities(ctx, runtimeRegistry, executionInput); err != nil {
+		return fmt.Errorf("check agent %s capabilities: %w", state.agentID, err)
+	}
+
+	var parentID agent.ExecutionID
+	switch {
+	case state.sessionID != nil:
+		parentID, err = agent.SessionHead(ctx, sessionRepository, *state.sessionID)
+	case state.handoff != nil:
+		parentID, err = agent.SessionHead(ctx, sessionRepository, state.handoff.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if state.sessionID == nil {
+		var sessionID agent.SessionID
+		if state.handoff != nil {
+			sessionID, err = agent.StartHandoffSession(ctx, sessionRepository, *state.handoff, state.agentID)
+		} else {
+			sessionID, err = agent.StartSession(ctx, sessionRepository, state.agentID, executionInput)
+		}
+		if err != nil {
+			return err
+		}
+
+		state.sessionID = &sessionID
+		state.handoff = nil
+	}
+
+	executionID, err := executionRepository.Create(ctx, executionInput, state.agentConfig, agent.WithAgentID(state.agentID), agent.WithSessionID(*state.sessionID), agent.WithParentID(parentID), agent.WithInstruction(instruction))
+	if err != nil {
+		return fmt.Errorf("create execution: %w", err)
+	}
+
+	slog.Debug("Created chat turn execution.", slog.String("executionId", string(executionID)), slog.String("sessionId", string(*state.sessionID)))
+
+	state.attachments = nil
+
+	if err := briefkitrunner.Spawn(ctx, executionID); err != nil {
+		return fmt.Errorf("spawn runner: %w", err)
+	}
+
+	result, err := streamExecution(ctx, executionRepository, executionID, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	state.turns++
+	if result.Usage != nil {
+		state.usage = state.usage.Add(*result.Usage)
+	}
+
+	return nil
+}
+
+// streamExecution writes the response text of an execution as the runtime produces it and follows fallback executions.
+// It returns a nil result when the execution was deferred by a rate limit.
+func streamExecution(ctx context.Context, repo agent.ExecutionRepository, id agent.ExecutionID, output io.Writer) (*agent.ExecutionResult, error) {
+	streamed := false
+	outcome, err := cli.WaitForExecution(ctx, repo, id,
+		cli.WithPollInterval(200*time.Millisecond),
+		cli.WithEventHandler(func(_ agent.ExecutionID, events []agent.RuntimeEventEnvelope) {
+			for _, envelope := range events {
+				event, err := envelope.Decode()
+				if err != nil {
+					slog.Debug("Skipping undecodable runtime event.", slog.String("error", err.Error()))
+					continue
+				}
+
+				if message, ok := event.(agent.RuntimeMessageEvent); ok && message.Text != "" {
+					fmt.Fprint(output, message.Text)
+					streamed = true
+				}
+			}
+		}),
+		cli.WithFallbackHandler(func(failed agent.ExecutionID, fallback agent.ExecutionID) {
+			if streamed {
+				fmt.Fprintln(output)
+			}
+			fmt.Fprintf(output, "Execution %s failed; continuing on fallback execution %s.\n", failed, fallback)
+			streamed = false
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	status := outcome.Status
+	switch status.State {
+	case agent.ExecutionDeferred:
+		if streamed {
+			fmt.Fprintln(output)
+		}
+		fmt.Fprintf(output, "Turn deferred by a rate limit until %s; the runner retries automatically.\n", utils.FormatClockTime(*status.ResetAt, time.Now()))
+		return nil, nil
+
+	case agent.ExecutionSucceeded:
+		result := outcome.Result
+		if len(result.Structured) > 0 {
+			fmt.Fprintln(output, string(result.Structured))
+		} else if !streamed {
+			fmt.Fprintln(output, result.Response)
+		} else {
+			fmt.Fprintln(output)
+		}
+
+		return result, nil
+	}
+
+	if streamed {
+		fmt.Fprintln(output)
+	}
+
+	errMsg := "unknown error"
+	if status.Error != nil {
+		errMsg = *status.Error
+	}
+	return nil, fmt.Errorf("execution failed: %s", errMsg)
+}
+
+// newChatAttachment describes a file attached to a chat turn, detecting its MIME type from the content or extension.
+func newChatAttachment(path string) (agent.ExecutionInputAttachment, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return agent.ExecutionInputAttachment{}, fmt.Errorf("resolve attachment path: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return agent.ExecutionInputAttachment{}, fmt.Errorf("stat attachment: %w", err)
+	}
+
+	if info.IsDir() {
+		return agent.ExecutionInputAttachment{}, fmt.Errorf("attachment %s is a directory", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return agent.ExecutionInputAttachment{}, fmt.Errorf("open attachment: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return agent.ExecutionInputAttachment{}, fmt.Errorf("read attachment: %w", err)
+	}
+
+	// Content sniffing recognizes text and common media; the extension only names otherwise unrecognized binary files.
+	mimeType := http.DetectContentType(head[:n])
+	if byExtension := mime.TypeByExtension(filepath.Ext(path)); mimeType == "application/octet-stream" && byExtension != "" {
+		mimeType = byExtension
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mediaType
+	}
+
+	return agent.ExecutionInputAttachment{
+		MimeType: mimeType,
+		Path:     path,
+	}, nil
+}
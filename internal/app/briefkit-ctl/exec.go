@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"time"
 
 	briefkitrunner "github.com/orbiqd/orbiqd-briefkit/internal/app/briefkit-runner"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
 )
 
@@ -18,11 +20,27 @@ type ExecCmd struct {
 	Timeout        time.Duration         `default:"5m"`
 	Model          *string               `help:"Select model for execution."`
 	ConversationID *agent.ConversationID `help:"Conversation ID for execution."`
+	SessionID      *agent.SessionID      `help:"Session ID to continue. A new session is started when omitted."`
+	OutputSchema   string                `help:"Path to a JSON Schema file the response must conform to." type:"existingfile"`
+	SystemPrompt   string                `help:"System prompt added after the agent instructions."`
+	Label          map[string]string     `help:"Label to record on the execution, as key=value. Repeat for several labels."`
 
 	Prompt string `arg:"" required:"" help:"Prompt to execute"`
+
+	// parentID links the execution to another session's turn, for handoffs.
+	parentID *agent.ExecutionID
+
+	// instruction is the user instruction when Prompt wraps it with replayed turns.
+	instruction string
+
+	// fork marks the execution as a branch off its parent turn.
+	fork bool
+
+	// forkConversation asks the runtime to fork ConversationID instead of continuing it.
+	forkConversation bool
 }
 
-func (command *ExecCmd) Run(ctx context.Context, executionRepository agent.ExecutionRepository, agentConfigRepository agent.ConfigRepository) error {
+func (command *ExecCmd) Run(ctx context.Context, executionRepository agent.ExecutionRepository, agentConfigRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
 	agentExists, err := agentConfigRepository.Exists(ctx, command.AgentID)
 	if err != nil {
 		return fmt.Errorf("agent config exists: %w", err)
@@ -38,82 +56,140 @@ func (command *ExecCmd) Run(ctx context.Context, executionRepository agent.Execu
 
 	slog.Debug("Found agent config.", slog.String("runtimeKind", string(agentConfig.Runtime.Kind)))
 
+	if err := agentConfig.Validate(ctx, runtimeRegistry); err != nil {
+		return fmt.Errorf("validate agent config %s: %w", command.AgentID, err)
+	}
+
 	executionInput := agent.ExecutionInput{
 		WorkingDirectory: nil,
 		Timeout:          utils.Duration(command.Timeout),
 		Prompt:           command.Prompt,
 		Model:            command.Model,
 		ConversationID:   command.ConversationID,
+		ForkConversation: command.forkConversation,
+		SystemPrompt:     command.SystemPrompt,
+	}
+
+	if command.OutputSchema != "" {
+		outputSchema, err := os.ReadFile(command.OutputSchema)
+		if err != nil {
+			return fmt.Errorf("read output schema: %w", err)
+		}
+		executionInput.OutputSchema = outputSchema
+	}
+
+	if command.SessionID != nil {
+		executionInput, err = agent.ContinueSession(ctx, sessionRepository, *command.SessionID, command.AgentID, executionInput)
+		if err != nil {
+			return fmt.Errorf("continue session: %w", err)
+		}
+	}
+
+	if executionInput.WorkingDirectory == nil {
+		// The runner inherits the current directory, record it so executions can be found by it.
+		workingDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		executionInput.WorkingDirectory = &workingDir
+	}
+
+	if err := agentConfig.CheckCapabilities(ctx, runtimeRegistry, executionInput); err != nil {
+		return fmt.Errorf("check agent %s capabilities: %w", command.AgentID, err)
 	}
 
-	executionID, err := executionRepository.Create(ctx, executionInput, agentConfig)
+	var sessionID agent.SessionID
+	if command.SessionID != nil {
+		sessionID = *command.SessionID
+	} else {
+		sessionID, err = agent.StartSession(ctx, sessionRepository, command.AgentID, executionInput)
+		if err != nil {
+			return fmt.Errorf("start session: %w", err)
+		}
+	}
+
+	options := []agent.ExecutionOption{agent.WithAgentID(command.AgentID), agent.WithSessionID(sessionID), agent.WithInstruction(command.instruction), agent.WithLabels(command.Label)}
+
+	parentID := agent.EmptyExecutionID
+	if command.parentID != nil {
+		parentID = *command.parentID
+	} else if command.SessionID != nil {
+		parentID, err = agent.SessionHead(ctx, sessionRepository, sessionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if command.fork {
+		options = append(options, agent.WithFork(parentID))
+	} else {
+		options = append(options, agent.WithParentID(parentID))
+	}
+
+	executionID, err := executionRepository.Create(ctx, executionInput, agentConfig, options...)
 	if err != nil {
 		return fmt.Errorf("create execution: %w", err)
 	}
 
-	slog.Info("Created execution.", slog.String("executionId", string(executionID)))
+	slog.Info("Created execution.", slog.String("executionId", string(executionID)), slog.String("sessionId", string(sessionID)))
 
 	if err := briefkitrunner.Spawn(ctx, executionID); err != nil {
 		return fmt.Errorf("spawn runner: %w", err)
 	}
 
-	// Use a separate context for the polling loop to respect the command timeout.
-	// We add a buffer to ensure we can retrieve the final status even if the runner
-	// uses the full execution timeout.
-	pollCtx, cancel := context.WithTimeout(ctx, command.Timeout+30*time.Second)
-	defer cancel()
-
-	return command.waitForCompletion(pollCtx, executionRepository, executionID)
+	return command.waitForCompletion(ctx, executionRepository, executionID)
 }
 
 func (command *ExecCmd) waitForCompletion(ctx context.Context, repo agent.ExecutionRepository, id agent.ExecutionID) error {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-
-	// Get the execution handle.
-	executionHandle, err := repo.Get(ctx, id)
+	var lastState agent.ExecutionState
+	outcome, err := cli.WaitForExecution(ctx, repo, id,
+		cli.WithStateHandler(func(id agent.ExecutionID, state agent.ExecutionState) {
+			slog.Info("Execution status changed.",
+				slog.String("executionId", string(id)),
+				slog.String("old", string(lastState)),
+				slog.String("new", string(state)))
+			lastState = state
+		}),
+		cli.WithFallbackHandler(func(failed agent.ExecutionID, fallback agent.ExecutionID) {
+			slog.Info("Execution failed, following the fallback execution.",
+				slog.String("executionId", string(failed)),
+				slog.String("fallbackExecutionId", string(fallback)))
+			lastState = ""
+		}),
+	)
 	if err != nil {
-		return fmt.Errorf("get execution handle: %w", err)
+		return err
 	}
 
-	var lastState agent.ExecutionState
-
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("wait for completion: %w", ctx.Err())
-		case <-ticker.C:
-			status, err := executionHandle.GetStatus(ctx)
-			if err != nil {
-				slog.Warn("Failed to get execution status", "error", err)
-				continue
-			}
-
-			if status.State != lastState {
-				slog.Info("Execution status changed.",
-					slog.String("old", string(lastState)),
-					slog.String("new", string(status.State)))
-				lastState = status.State
-			}
-
-			if status.State.IsFinished() {
-				if status.State == agent.ExecutionSucceeded {
-					result, err := executionHandle.GetResult(ctx)
-					if err != nil {
-						return fmt.Errorf("get result: %w", err)
-					}
-					slog.Info("Execution finished successfully.", slog.String("conversationId", string(result.ConversationID)))
-					fmt.Println()
-					fmt.Println(result.Response)
-					return nil
-				}
-
-				errMsg := "unknown error"
-				if status.Error != nil {
-					errMsg = *status.Error
-				}
-				return fmt.Errorf("execution failed: %s", errMsg)
-			}
+	status := outcome.Status
+	switch status.State {
+	case agent.ExecutionDeferred:
+		slog.Info("Execution deferred by a rate limit.", slog.String("executionId", string(outcome.ID)), slog.Time("resetAt", *status.ResetAt))
+		fmt.Println()
+		fmt.Printf("Execution %s deferred until %s; the runner retries automatically.\n", outcome.ID, utils.FormatClockTime(*status.ResetAt, time.Now()))
+		return nil
+
+	case agent.ExecutionSucceeded:
+		result := outcome.Result
+		slog.Info("Execution finished successfully.",
+			slog.String("executionId", string(outcome.ID)),
+			slog.String("agentId", string(result.AgentID)),
+			slog.String("conversationId", string(result.ConversationID)))
+		fmt.Println()
+		if len(result.Structured) > 0 {
+			fmt.Println(string(result.Structured))
+		} else {
+			fmt.Println(result.Response)
 		}
+		return nil
+	}
+
+	errMsg := "unknown error"
+	if status.Error != nil {
+		errMsg = *status.Error
+	}
+	if status.ResetAt != nil {
+		return fmt.Errorf("execution failed: %s (rate limit resets at %s)", errMsg, utils.FormatClockTime(*status.ResetAt, time.Now()))
 	}
+	return fmt.Errorf("execution failed: %s", errMsg)
 }
@@ -0,0 +1,79 @@
+package briefkitctl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// ForkCmd branches the conversation off a past execution with a different instruction.
+type ForkCmd struct {
+	ExecutionID agent.ExecutionID `help:"Execution whose turn the new branch starts after." required:"true"`
+	Timeout     time.Duration     `default:"5m"`
+	Model       *string           `help:"Select model for execution."`
+
+	Prompt string `arg:"" required:"" help:"Instruction for the new branch."`
+}
+
+// Run starts a session that branches off the execution's turn and executes the instruction in it.
+func (command *ForkCmd) Run(ctx context.Context, executionRepository agent.ExecutionRepository, agentConfigRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	point, err := agent.FindForkPoint(ctx, executionRepository, sessionRepository, command.ExecutionID)
+	if err != nil {
+		return fmt.Errorf("find fork point: %w", err)
+	}
+
+	agentID := point.AgentID()
+	agentConfig, err := agentConfigRepository.Get(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("get agent config: %w", err)
+	}
+
+	runtime, err := runtimeRegistry.Get(ctx, agentConfig.Runtime.Kind)
+	if err != nil {
+		return fmt.Errorf("get runtime: %w", err)
+	}
+
+	capabilities, err := runtime.GetCapabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("get runtime capabilities: %w", err)
+	}
+
+	input, err := point.Input(agent.ExecutionInput{Prompt: command.Prompt}, capabilities)
+	if err != nil {
+		return fmt.Errorf("prepare fork input: %w", err)
+	}
+
+	var instruction string
+	if !input.ForkConversation {
+		instruction = command.Prompt
+	}
+
+	sessionID, err := agent.StartForkSession(ctx, sessionRepository, point)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Forking conversation.",
+		slog.String("executionId", string(command.ExecutionID)),
+		slog.String("sourceSessionId", string(point.Session.ID)),
+		slog.String("sessionId", string(sessionID)),
+		slog.String("agentId", string(agentID)),
+		slog.Bool("runtimeFork", input.ForkConversation))
+
+	exec := ExecCmd{
+		AgentID:          agentID,
+		Timeout:          command.Timeout,
+		Model:            command.Model,
+		ConversationID:   input.ConversationID,
+		SessionID:        &sessionID,
+		Prompt:           input.Prompt,
+		instruction:      instruction,
+		fork:             true,
+		forkConversation: input.ForkConversation,
+	}
+
+	return exec.Run(ctx, executionRepository, agentConfigRepository, sessionRepository, runtimeRegistry)
+}
@@ -0,0 +1,68 @@
+package briefkitctl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// HandoffCmd continues the conversation of a session on another agent.
+type HandoffCmd struct {
+	SessionID agent.SessionID   `help:"Session ID to hand off." required:"true"`
+	AgentID   agent.AgentID     `help:"ID of the agent that takes over." required:"true"`
+	Mode      agent.HandoffMode `help:"How prior turns seed the new conversation (transcript or excerpt)." default:"transcript" enum:"transcript,excerpt"`
+	Timeout   time.Duration     `default:"5m"`
+	Model     *string           `help:"Select model for execution."`
+
+	Prompt string `arg:"" optional:"" help:"Next instruction for the agent that takes over."`
+}
+
+// Run seeds a new session on the target agent with the prior turns and executes the next instruction.
+func (command *HandoffCmd) Run(ctx context.Context, executionRepository agent.ExecutionRepository, agentConfigRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	source, err := sessionRepository.Get(ctx, command.SessionID)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	transcript, err := sessionRepository.GetTranscript(ctx, command.SessionID)
+	if err != nil {
+		return fmt.Errorf("get session transcript: %w", err)
+	}
+
+	prompt, err := agent.BuildHandoffPrompt(source, transcript, command.Mode, command.Prompt)
+	if err != nil {
+		return fmt.Errorf("build handoff prompt: %w", err)
+	}
+
+	parentID, err := agent.SessionHead(ctx, sessionRepository, source.ID)
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := agent.StartHandoffSession(ctx, sessionRepository, source, command.AgentID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Handing off session.",
+		slog.String("sourceSessionId", string(source.ID)),
+		slog.String("sourceAgentId", string(source.AgentID)),
+		slog.String("sessionId", string(sessionID)),
+		slog.String("agentId", string(command.AgentID)),
+		slog.String("mode", string(command.Mode)))
+
+	exec := ExecCmd{
+		AgentID:     command.AgentID,
+		Timeout:     command.Timeout,
+		Model:       command.Model,
+		SessionID:   &sessionID,
+		Prompt:      prompt,
+		parentID:    &parentID,
+		instruction: agent.HandoffInstruction(command.Prompt),
+	}
+
+	return exec.Run(ctx, executionRepository, agentConfigRepository, sessionRepository, runtimeRegistry)
+}
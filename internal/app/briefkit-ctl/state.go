@@ -2,4 +2,6 @@ package briefkitctl
 
 type StateCmd struct {
 	Execution StateExecutionCmd `cmd:"" help:"Manage execution state"`
+	Session   StateSessionCmd   `cmd:"" help:"Manage session state"`
+	Migrate   StateMigrateCmd   `cmd:"" help:"Copy executions and agent configs from the fs store into the SQLite database"`
 }
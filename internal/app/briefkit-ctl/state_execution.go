@@ -1,7 +1,12 @@
 package briefkitctl
 
 type StateExecutionCmd struct {
-	Create StateExecutionCreateCmd `cmd:"" help:"Create a new execution"`
-	List   StateExecutionListCmd   `cmd:"" help:"List executions"`
-	Show   StateExecutionShowCmd   `cmd:"" help:"Show execution details"`
+	Create  StateExecutionCreateCmd  `cmd:"" help:"Create a new execution"`
+	List    StateExecutionListCmd    `cmd:"" help:"List executions"`
+	Show    StateExecutionShowCmd    `cmd:"" help:"Show execution details"`
+	Tree    StateExecutionTreeCmd    `cmd:"" help:"Show the conversation branches around an execution"`
+	Export  StateExecutionExportCmd  `cmd:"" help:"Export an execution as a Markdown, HTML or JSON document"`
+	Reindex StateExecutionReindexCmd `cmd:"" help:"Rebuild the index used to list and filter executions"`
+	Prune   StateExecutionPruneCmd   `cmd:"" help:"Delete expired executions with their runtime logs"`
+	Delete  StateExecutionDeleteCmd  `cmd:"" help:"Delete executions with their runtime logs"`
 }
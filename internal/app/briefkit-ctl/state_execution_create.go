@@ -15,22 +15,29 @@ import (
 
 // StateExecutionCreateCmd creates a new execution.
 type StateExecutionCreateCmd struct {
-	AgentID    string `required:"" help:"Agent ID"`
-	Prompt     string `arg:"" required:"" help:"Question or prompt"`
-	WorkingDir string `short:"w" default:"." help:"Working directory"`
-	Timeout    string `short:"t" default:"5m" help:"Execution timeout"`
+	AgentID    string            `required:"" help:"Agent ID"`
+	Prompt     string            `arg:"" required:"" help:"Question or prompt"`
+	WorkingDir string            `short:"w" default:"." help:"Working directory"`
+	Timeout    string            `short:"t" default:"5m" help:"Execution timeout"`
+	SessionID  string            `help:"Session ID to continue. A new session is started when omitted."`
+	Label      map[string]string `help:"Label to record on the execution, as key=value. Repeat for several labels."`
 }
 
 type executionCreateOutput struct {
-	ID agent.ExecutionID `json:"id"`
+	ID        agent.ExecutionID `json:"id"`
+	SessionID agent.SessionID   `json:"sessionId"`
 }
 
-func (e *StateExecutionCreateCmd) Run(ctx context.Context, repository agent.ExecutionRepository, configRepository agent.ConfigRepository) error {
+func (e *StateExecutionCreateCmd) Run(ctx context.Context, repository agent.ExecutionRepository, configRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
 	config, err := configRepository.Get(ctx, agent.AgentID(e.AgentID))
 	if err != nil {
 		return fmt.Errorf("load agent config: %w", err)
 	}
 
+	if err := config.Validate(ctx, runtimeRegistry); err != nil {
+		return fmt.Errorf("validate agent config: %w", err)
+	}
+
 	timeout, err := time.ParseDuration(e.Timeout)
 	if err != nil {
 		return fmt.Errorf("parse timeout: %w", err)
@@ -52,12 +59,37 @@ func (e *StateExecutionCreateCmd) Run(ctx context.Context, repository agent.Exec
 		Prompt:           e.Prompt,
 	}
 
-	id, err := repository.Create(ctx, input, config)
+	sessionID := agent.SessionID(e.SessionID)
+	parentID := agent.EmptyExecutionID
+	if sessionID != "" {
+		input, err = agent.ContinueSession(ctx, sessionRepository, sessionID, agent.AgentID(e.AgentID), input)
+		if err != nil {
+			return fmt.Errorf("continue session: %w", err)
+		}
+
+		parentID, err = agent.SessionHead(ctx, sessionRepository, sessionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := config.CheckCapabilities(ctx, runtimeRegistry, input); err != nil {
+		return fmt.Errorf("check agent capabilities: %w", err)
+	}
+
+	if sessionID == "" {
+		sessionID, err = agent.StartSession(ctx, sessionRepository, agent.AgentID(e.AgentID), input)
+		if err != nil {
+			return fmt.Errorf("start session: %w", err)
+		}
+	}
+
+	id, err := repository.Create(ctx, input, config, agent.WithAgentID(agent.AgentID(e.AgentID)), agent.WithSessionID(sessionID), agent.WithParentID(parentID), agent.WithLabels(e.Label))
 	if err != nil {
 		return fmt.Errorf("create execution: %w", err)
 	}
 
-	output := executionCreateOutput{ID: id}
+	output := executionCreateOutput{ID: id, SessionID: sessionID}
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(output); err != nil {
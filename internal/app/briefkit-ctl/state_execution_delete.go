@@ -0,0 +1,39 @@
+package briefkitctl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+)
+
+// StateExecutionDeleteCmd deletes executions together with their runtime logs.
+type StateExecutionDeleteCmd struct {
+	IDs   []agent.ExecutionID `arg:"" name:"id" required:"" help:"Execution IDs to delete."`
+	Force bool                `help:"Delete executions even while they are created, started, running or deferred."`
+}
+
+// Run executes the execution delete command.
+func (e *StateExecutionDeleteCmd) Run(ctx context.Context, repository agent.ExecutionRepository) error {
+	var options []agent.ExecutionDeleteOption
+	if e.Force {
+		options = append(options, agent.ForceDelete())
+	}
+
+	var errs []error
+	for _, id := range e.IDs {
+		if err := cli.DeleteExecution(ctx, repository, id, options...); err != nil {
+			if errors.Is(err, agent.ErrExecutionActive) {
+				slog.Warn("Execution is active, use --force to delete it.", slog.String("executionID", string(id)))
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		slog.Info("Deleted execution.", slog.String("executionID", string(id)))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,395 @@
+package briefkitctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// ExecutionExport is a shareable record of one execution.
+type ExecutionExport struct {
+	ID          agent.ExecutionID                `json:"id"`
+	AgentID     agent.AgentID                    `json:"agentId,omitempty"`
+	SessionID   *agent.SessionID                 `json:"sessionId,omitempty"`
+	Relation    string                           `json:"relation"`
+	Prompt      string                           `json:"prompt"`
+	Attachments []agent.ExecutionInputAttachment `json:"attachments,omitempty"`
+	Model       *string                          `json:"model,omitempty"`
+	Status      agent.ExecutionStatus            `json:"status"`
+	Activity    []agent.RuntimeActivityEvent     `json:"activity,omitempty"`
+	Result      *agent.ExecutionResult           `json:"result,omitempty"`
+}
+
+// StateExecutionExportCmd renders an execution, or its whole conversation chain, as a shareable document.
+type StateExecutionExportCmd struct {
+	ID     string `arg:"" required:"" help:"Execution ID"`
+	Format string `help:"Output format (md, html or json)." default:"md" enum:"md,html,json"`
+	Chain  bool   `help:"Export the whole conversation chain leading to the execution and its fallbacks."`
+	Output string `help:"Write the document to this file instead of stdout." short:"o"`
+}
+
+// Run executes the execution export command.
+func (e *StateExecutionExportCmd) Run(ctx context.Context, repository agent.ExecutionRepository) error {
+	id := agent.ExecutionID(e.ID)
+	if err := id.Validate(); err != nil {
+		return fmt.Errorf("validate execution id: %w", err)
+	}
+
+	ids := []agent.ExecutionID{id}
+	if e.Chain {
+		chain, err := executionChain(ctx, repository, id)
+		if err != nil {
+			return err
+		}
+		ids = chain
+	}
+
+	exports := make([]ExecutionExport, 0, len(ids))
+	for _, executionID := range ids {
+		export, err := loadExecutionExport(ctx, repository, executionID)
+		if err != nil {
+			return fmt.Errorf("export execution %s: %w", executionID, err)
+		}
+		exports = append(exports, export)
+	}
+
+	var w io.Writer = os.Stdout
+	if e.Output != "" {
+		file, err := os.Create(e.Output)
+		if err != nil {
+			return fmt.Errorf("create export file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch e.Format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(exports); err != nil {
+			return fmt.Errorf("encode execution export: %w", err)
+		}
+	case "html":
+		if err := exportHTMLTemplate.Execute(w, exports); err != nil {
+			return fmt.Errorf("render execution export: %w", err)
+		}
+	default:
+		if _, err := io.WriteString(w, renderExecutionMarkdown(exports)); err != nil {
+			return fmt.Errorf("write execution export: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// executionChain lists the ancestors of the execution from the first turn, the execution itself and the
+// fallbacks that replaced it.
+func executionChain(ctx context.Context, repository agent.ExecutionRepository, id agent.ExecutionID) ([]agent.ExecutionID, error) {
+	visited := map[agent.ExecutionID]bool{id: true}
+	chain := []agent.ExecutionID{id}
+
+	current := id
+	for {
+		execution, err := repository.Get(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("load execution: %w", err)
+		}
+
+		metadata, err := execution.GetMetadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load execution metadata: %w", err)
+		}
+
+		parentID := metadata.Parent()
+		if parentID == nil || visited[*parentID] {
+			break
+		}
+
+		if exists, err := repository.Exists(ctx, *parentID); err != nil || !exists {
+			slog.Warn("Execution parent is missing, chain starts at the execution.", slog.String("id", string(current)), slog.String("parentId", string(*parentID)))
+			break
+		}
+
+		visited[*parentID] = true
+		chain = append([]agent.ExecutionID{*parentID}, chain...)
+		current = *parentID
+	}
+
+	current = id
+	for {
+		execution, err := repository.Get(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("load execution: %w", err)
+		}
+
+		status, err := execution.GetStatus(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load execution status: %w", err)
+		}
+
+		fallbackID := status.FallbackExecutionID
+		if fallbackID == nil || visited[*fallbackID] {
+			break
+		}
+
+		if exists, err := repository.Exists(ctx, *fallbackID); err != nil || !exists {
+			slog.Warn("Fallback execution is missing, chain ends at the execution.", slog.String("id", string(current)), slog.String("fallbackId", string(*fallbackID)))
+			break
+		}
+
+		visited[*fallbackID] = true
+		chain = append(chain, *fallbackID)
+		current = *fallbackID
+	}
+
+	return chain, nil
+}
+
+func loadExecutionExport(ctx context.Context, repository agent.ExecutionRepository, id agent.ExecutionID) (ExecutionExport, error) {
+	execution, err := repository.Get(ctx, id)
+	if err != nil {
+		return ExecutionExport{}, fmt.Errorf("load execution: %w", err)
+	}
+
+	metadata, err := execution.GetMetadata(ctx)
+	if err != nil {
+		return ExecutionExport{}, fmt.Errorf("load execution metadata: %w", err)
+	}
+
+	input, err := execution.GetInput(ctx)
+	if err != nil {
+		return ExecutionExport{}, fmt.Errorf("load execution input: %w", err)
+	}
+
+	status, err := execution.GetStatus(ctx)
+	if err != nil {
+		return ExecutionExport{}, fmt.Errorf("load execution status: %w", err)
+	}
+
+	export := ExecutionExport{
+		ID:          id,
+		AgentID:     metadata.AgentID,
+		SessionID:   metadata.SessionID,
+		Relation:    executionRelation(metadata),
+		Prompt:      metadata.Prompt(input),
+		Attachments: input.Attachments,
+		Model:       input.Model,
+		Status:      status,
+	}
+
+	result, err := execution.GetResult(ctx)
+	if err != nil {
+		if !errors.Is(err, agent.ErrExecutionNoResult) {
+			return ExecutionExport{}, fmt.Errorf("load execution result: %w", err)
+		}
+	} else {
+		export.Result = &result
+	}
+
+	envelopes, err := execution.GetEvents(ctx, 0)
+	if err != nil {
+		return ExecutionExport{}, fmt.Errorf("load execution events: %w", err)
+	}
+
+	for _, envelope := range envelopes {
+		if envelope.Kind != agent.RuntimeEventActivity {
+			continue
+		}
+
+		event, err := envelope.Decode()
+		if err != nil {
+			slog.Warn("Failed to decode execution event.", slog.String("id", string(id)), slog.String("error", err.Error()))
+			continue
+		}
+
+		if activity, ok := event.(agent.RuntimeActivityEvent); ok {
+			export.Activity = append(export.Activity, activity)
+		}
+	}
+
+	return export, nil
+}
+
+// ResultText returns the response of the execution, or its structured output when it has one.
+func (export ExecutionExport) ResultText() string {
+	if export.Result == nil {
+		return ""
+	}
+
+	if len(export.Result.Structured) > 0 {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, export.Result.Structured, "", "  "); err == nil {
+			return indented.String()
+		}
+
+		return string(export.Result.Structured)
+	}
+
+	return export.Result.Response
+}
+
+// Duration returns how long the execution ran, or zero while it has not finished.
+func (export ExecutionExport) Duration() time.Duration {
+	if export.Status.FinishedAt == nil {
+		return 0
+	}
+
+	return export.Status.FinishedAt.Sub(export.Status.CreatedAt).Round(time.Second)
+}
+
+func renderExecutionMarkdown(exports []ExecutionExport) string {
+	var b strings.Builder
+
+	for i, export := range exports {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+
+		fmt.Fprintf(&b, "# Execution %s\n\n", export.ID)
+
+		fmt.Fprintf(&b, "- **State:** %s\n", export.Status.State)
+		if export.AgentID != "" {
+			fmt.Fprintf(&b, "- **Agent:** %s\n", export.AgentID)
+		}
+		if export.Model != nil {
+			fmt.Fprintf(&b, "- **Model:** %s\n", *export.Model)
+		}
+		fmt.Fprintf(&b, "- **Relation:** %s\n", export.Relation)
+		if export.SessionID != nil {
+			fmt.Fprintf(&b, "- **Session:** %s\n", *export.SessionID)
+		}
+		if export.Result != nil && export.Result.ConversationID != "" {
+			fmt.Fprintf(&b, "- **Conversation:** %s\n", export.Result.ConversationID)
+		}
+		fmt.Fprintf(&b, "- **Created:** %s\n", export.Status.CreatedAt.Format(time.RFC3339))
+		if duration := export.Duration(); duration > 0 {
+			fmt.Fprintf(&b, "- **Duration:** %s\n", duration)
+		}
+		if export.Status.Attempts > 1 {
+			fmt.Fprintf(&b, "- **Attempts:** %d\n", export.Status.Attempts)
+		}
+		if export.Status.ExitCode != nil {
+			fmt.Fprintf(&b, "- **Exit code:** %d\n", *export.Status.ExitCode)
+		}
+		if export.Status.Error != nil {
+			fmt.Fprintf(&b, "- **Error:** %s\n", *export.Status.Error)
+		}
+
+		b.WriteString("\n## Prompt\n\n")
+		writeMarkdownBlock(&b, export.Prompt)
+
+		if len(export.Attachments) > 0 {
+			b.WriteString("\n## Attachments\n\n")
+			for _, attachment := range export.Attachments {
+				fmt.Fprintf(&b, "- `%s` (%s)\n", attachment.Path, attachment.MimeType)
+			}
+		}
+
+		if len(export.Activity) > 0 {
+			b.WriteString("\n## Activity\n\n")
+			for _, activity := range export.Activity {
+				fmt.Fprintf(&b, "- %s **%s** %s", activity.Timestamp.Format(time.TimeOnly), activity.Activity, activity.Name)
+				if activity.Detail != "" {
+					fmt.Fprintf(&b, ": `%s`", strings.ReplaceAll(activity.Detail, "\n", " "))
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		if text := export.ResultText(); text != "" {
+			b.WriteString("\n## Response\n\n")
+			writeMarkdownBlock(&b, text)
+		}
+
+		if export.Result != nil && export.Result.Usage != nil {
+			usage := export.Result.Usage
+			b.WriteString("\n## Usage\n\n")
+			fmt.Fprintf(&b, "- **Input tokens:** %d\n", usage.InputTokens)
+			fmt.Fprintf(&b, "- **Cached input tokens:** %d\n", usage.CachedInputTokens)
+			fmt.Fprintf(&b, "- **Output tokens:** %d\n", usage.OutputTokens)
+		}
+	}
+
+	return b.String()
+}
+
+// writeMarkdownBlock writes text as a fenced block that is longer than any fence inside the text.
+func writeMarkdownBlock(b *strings.Builder, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	fmt.Fprintf(b, "%s\n%s\n%s\n", fence, strings.TrimRight(text, "\n"), fence)
+}
+
+var exportHTMLTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"time":  func(t time.Time) string { return t.Format(time.RFC3339) },
+	"clock": func(t time.Time) string { return t.Format(time.TimeOnly) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Briefkit execution {{(index . 0).ID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+section { border-bottom: 1px solid #d0d7de; padding-bottom: 1.5rem; margin-bottom: 1.5rem; }
+pre { background: #f6f8fa; padding: 1rem; border-radius: 6px; white-space: pre-wrap; word-wrap: break-word; }
+dl { display: grid; grid-template-columns: max-content auto; gap: .25rem 1rem; }
+dt { font-weight: 600; }
+dd { margin: 0; }
+code { background: #f6f8fa; padding: .1rem .3rem; border-radius: 4px; }
+.state-succeeded { color: #1a7f37; }
+.state-failed { color: #cf222e; }
+.activity { color: #59636e; }
+</style>
+</head>
+<body>
+{{range .}}<section>
+<h1>Execution {{.ID}}</h1>
+<dl>
+<dt>State</dt><dd class="state-{{.Status.State}}">{{.Status.State}}</dd>
+{{if .AgentID}}<dt>Agent</dt><dd>{{.AgentID}}</dd>
+{{end}}{{if .Model}}<dt>Model</dt><dd>{{.Model}}</dd>
+{{end}}<dt>Relation</dt><dd>{{.Relation}}</dd>
+{{if .SessionID}}<dt>Session</dt><dd>{{.SessionID}}</dd>
+{{end}}{{if and .Result .Result.ConversationID}}<dt>Conversation</dt><dd>{{.Result.ConversationID}}</dd>
+{{end}}<dt>Created</dt><dd>{{time .Status.CreatedAt}}</dd>
+{{if .Duration}}<dt>Duration</dt><dd>{{.Duration}}</dd>
+{{end}}{{if gt .Status.Attempts 1}}<dt>Attempts</dt><dd>{{.Status.Attempts}}</dd>
+{{end}}{{if .Status.ExitCode}}<dt>Exit code</dt><dd>{{.Status.ExitCode}}</dd>
+{{end}}{{if .Status.Error}}<dt>Error</dt><dd>{{.Status.Error}}</dd>
+{{end}}</dl>
+<h2>Prompt</h2>
+<pre>{{.Prompt}}</pre>
+{{if .Attachments}}<h2>Attachments</h2>
+<ul>
+{{range .Attachments}}<li><code>{{.Path}}</code> ({{.MimeType}})</li>
+{{end}}</ul>
+{{end}}{{if .Activity}}<h2>Activity</h2>
+<ul class="activity">
+{{range .Activity}}<li>{{clock .Timestamp}} <strong>{{.Activity}}</strong> {{.Name}}{{if .Detail}}: <code>{{.Detail}}</code>{{end}}</li>
+{{end}}</ul>
+{{end}}{{with .ResultText}}<h2>Response</h2>
+<pre>{{.}}</pre>
+{{end}}{{if and .Result .Result.Usage}}<h2>Usage</h2>
+<dl>
+<dt>Input tokens</dt><dd>{{.Result.Usage.InputTokens}}</dd>
+<dt>Cached input tokens</dt><dd>{{.Result.Usage.CachedInputTokens}}</dd>
+<dt>Output tokens</dt><dd>{{.Result.Usage.OutputTokens}}</dd>
+</dl>
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/mitchellh/go-homedir"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 )
 
@@ -18,19 +22,49 @@ type ExecutionListOutputItem struct {
 
 // ExecutionListOutput captures the list output payload for executions.
 type ExecutionListOutput struct {
-	Items []ExecutionListOutputItem `json:"items"`
-	Count int                       `json:"count"`
+	Items      []ExecutionListOutputItem `json:"items"`
+	Count      int                       `json:"count"`
+	NextCursor *agent.ExecutionCursor    `json:"nextCursor,omitempty"`
 }
 
 // StateExecutionListCmd lists stored executions and their status.
-type StateExecutionListCmd struct{}
+type StateExecutionListCmd struct {
+	AgentID        string   `help:"Only list executions of the agent."`
+	Runtime        string   `help:"Only list executions on the runtime kind."`
+	State          []string `help:"Only list executions in the state. Repeat for any of several states." enum:"created,started,running,succeeded,failed,deferred"`
+	Since          string   `help:"Only list executions created at or after the time (RFC 3339 or a duration ago, e.g. 24h)."`
+	Until          string   `help:"Only list executions created before the time (RFC 3339 or a duration ago, e.g. 24h)."`
+	WorkingDir     string   `help:"Only list executions run in the directory."`
+	ConversationID string   `help:"Only list executions that continued or produced the conversation."`
+	Label          []string `help:"Only list executions with the label, as key=value or key for any value. Repeat to require several labels."`
+	Sort           string   `help:"Sort order (id, created-asc or created-desc)." default:"created-desc" enum:"id,created-asc,created-desc"`
+	Limit          int      `help:"Maximum number of executions to list. Zero lists all." default:"50"`
+	Cursor         string   `help:"Continue after the position returned in nextCursor."`
+}
 
 func (e *StateExecutionListCmd) Run(ctx context.Context, repository agent.ExecutionRepository) error {
-	ids, err := repository.Find(ctx)
+	filters, err := e.filters(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if e.Limit > 0 {
+		// One extra execution tells whether another page follows.
+		filters = append(filters, agent.LimitExecutions(e.Limit+1))
+	}
+
+	ids, err := repository.Find(ctx, filters...)
 	if err != nil {
 		return fmt.Errorf("list executions: %w", err)
 	}
 
+	hasNextPage := e.Limit > 0 && len(ids) > e.Limit
+	if hasNextPage {
+		ids = ids[:e.Limit]
+	}
+
+	var nextCursor *agent.ExecutionCursor
+
 	items := make([]ExecutionListOutputItem, 0, len(ids))
 	for _, id := range ids {
 		execution, err := repository.Get(ctx, id)
@@ -57,11 +91,20 @@ func (e *StateExecutionListCmd) Run(ctx context.Context, repository agent.Execut
 			Id:     id,
 			Status: status,
 		})
+
+		if hasNextPage && id == ids[len(ids)-1] {
+			nextCursor = &agent.ExecutionCursor{CreatedAt: status.CreatedAt, ID: id}
+		}
+	}
+
+	if hasNextPage && nextCursor == nil {
+		return fmt.Errorf("list executions: load last execution %s of the page", ids[len(ids)-1])
 	}
 
 	output := ExecutionListOutput{
-		Items: items,
-		Count: len(items),
+		Items:      items,
+		Count:      len(items),
+		NextCursor: nextCursor,
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -72,3 +115,79 @@ func (e *StateExecutionListCmd) Run(ctx context.Context, repository agent.Execut
 
 	return nil
 }
+
+func (e *StateExecutionListCmd) filters(now time.Time) ([]agent.ExecutionFilter, error) {
+	filters := []agent.ExecutionFilter{agent.SortExecutions(agent.ExecutionSort(e.Sort))}
+
+	if e.AgentID != "" {
+		filters = append(filters, agent.FilterByAgentID(agent.AgentID(e.AgentID)))
+	}
+
+	if e.Runtime != "" {
+		filters = append(filters, agent.FilterByRuntimeKind(agent.RuntimeKind(e.Runtime)))
+	}
+
+	for _, state := range e.State {
+		filters = append(filters, agent.FilterByState(agent.ExecutionState(state)))
+	}
+
+	if e.Since != "" {
+		since, err := parseListTime(e.Since, now)
+		if err != nil {
+			return nil, fmt.Errorf("parse since: %w", err)
+		}
+		filters = append(filters, agent.FilterCreatedAfter(since))
+	}
+
+	if e.Until != "" {
+		until, err := parseListTime(e.Until, now)
+		if err != nil {
+			return nil, fmt.Errorf("parse until: %w", err)
+		}
+		filters = append(filters, agent.FilterCreatedBefore(until))
+	}
+
+	if e.WorkingDir != "" {
+		expandedWorkingDir, err := homedir.Expand(e.WorkingDir)
+		if err != nil {
+			return nil, fmt.Errorf("expand working directory: %w", err)
+		}
+
+		workingDir, err := filepath.Abs(expandedWorkingDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolve working directory: %w", err)
+		}
+		filters = append(filters, agent.FilterByWorkingDirectory(workingDir))
+	}
+
+	if e.ConversationID != "" {
+		filters = append(filters, agent.FilterByConversationID(agent.ConversationID(e.ConversationID)))
+	}
+
+	for _, label := range e.Label {
+		key, value, _ := strings.Cut(label, "=")
+		if key == "" {
+			return nil, fmt.Errorf("parse label %q: key required", label)
+		}
+		filters = append(filters, agent.FilterByLabel(key, value))
+	}
+
+	if e.Cursor != "" {
+		cursor, err := agent.ParseExecutionCursor(e.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("parse cursor: %w", err)
+		}
+		filters = append(filters, agent.ExecutionsAfter(cursor))
+	}
+
+	return filters, nil
+}
+
+// parseListTime reads an RFC 3339 time or a duration before now.
+func parseListTime(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,122 @@
+package briefkitctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+)
+
+// ExecutionPruneOutput captures the prune output payload for executions.
+type ExecutionPruneOutput struct {
+	Items  []ExecutionListOutputItem `json:"items"`
+	Count  int                       `json:"count"`
+	DryRun bool                      `json:"dryRun"`
+}
+
+// StateExecutionPruneCmd deletes expired executions together with their runtime logs.
+type StateExecutionPruneCmd struct {
+	OlderThan time.Duration `help:"Only prune executions created longer ago than the duration, e.g. 720h."`
+	State     []string      `help:"Only prune executions in the state. Repeat for any of several states. Defaults to succeeded and failed." enum:"succeeded,failed"`
+	AgentID   string        `help:"Only prune executions of the agent."`
+	KeepLast  int           `help:"Keep the number of most recent matching executions whatever their age."`
+	All       bool          `help:"Prune every finished execution when no other limit is given."`
+	DryRun    bool          `help:"List the executions that would be pruned without deleting them."`
+}
+
+// Validate requires a limit or --all, so that a bare prune does not delete every finished execution.
+func (e *StateExecutionPruneCmd) Validate() error {
+	if e.OlderThan == 0 && e.KeepLast == 0 && e.AgentID == "" && len(e.State) == 0 && !e.All {
+		return errors.New("at least one of --older-than, --keep-last, --agent-id, --state or --all is required")
+	}
+
+	return nil
+}
+
+// Run executes the execution prune command.
+func (e *StateExecutionPruneCmd) Run(ctx context.Context, repository agent.ExecutionRepository) error {
+	policy := agent.RetentionPolicy{
+		MaxAge:   e.OlderThan,
+		KeepLast: e.KeepLast,
+	}
+
+	for _, state := range e.State {
+		policy.States = append(policy.States, agent.ExecutionState(state))
+	}
+
+	if e.AgentID != "" {
+		agentID := agent.AgentID(e.AgentID)
+		policy.AgentID = &agentID
+	}
+
+	ids, err := policy.SelectExpired(ctx, repository, time.Now())
+	if err != nil {
+		return fmt.Errorf("select expired executions: %w", err)
+	}
+
+	items := make([]ExecutionListOutputItem, 0, len(ids))
+	for _, id := range ids {
+		execution, err := repository.Get(ctx, id)
+		if err != nil {
+			slog.Warn(
+				"Failed to load execution",
+				slog.String("id", string(id)),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		status, err := execution.GetStatus(ctx)
+		if err != nil {
+			slog.Warn(
+				"Failed to load execution status",
+				slog.String("id", string(id)),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		items = append(items, ExecutionListOutputItem{
+			Id:     id,
+			Status: status,
+		})
+	}
+
+	if !e.DryRun {
+		pruneIDs := make([]agent.ExecutionID, 0, len(items))
+		for _, item := range items {
+			pruneIDs = append(pruneIDs, item.Id)
+		}
+
+		deleted, err := cli.PruneExecutions(ctx, repository, pruneIDs)
+		if err != nil {
+			return fmt.Errorf("prune executions: %w", err)
+		}
+
+		// Executions deleted concurrently are not reported as pruned by this run.
+		items = slices.DeleteFunc(items, func(item ExecutionListOutputItem) bool {
+			return !slices.Contains(deleted, item.Id)
+		})
+	}
+
+	output := ExecutionPruneOutput{
+		Items:  items,
+		Count:  len(items),
+		DryRun: e.DryRun,
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("encode execution prune output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package briefkitctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// StateExecutionReindexCmd rebuilds the execution index used to list and filter executions.
+type StateExecutionReindexCmd struct{}
+
+// Run executes the execution reindex command.
+func (e *StateExecutionReindexCmd) Run(ctx context.Context, repository agent.ExecutionRepository) error {
+	indexer, ok := repository.(agent.ExecutionIndexer)
+	if !ok {
+		return errors.New("execution store does not keep an index")
+	}
+
+	if err := indexer.RebuildIndex(ctx); err != nil {
+		return fmt.Errorf("rebuild execution index: %w", err)
+	}
+
+	slog.Info("Rebuilt execution index.")
+
+	return nil
+}
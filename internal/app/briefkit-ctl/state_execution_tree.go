@@ -0,0 +1,212 @@
+package briefkitctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+const executionTreePromptExcerpt = 60
+
+// ExecutionTreeNode is an execution in the tree output.
+type ExecutionTreeNode struct {
+	ID             agent.ExecutionID    `json:"id"`
+	AgentID        agent.AgentID        `json:"agentId,omitempty"`
+	SessionID      *agent.SessionID     `json:"sessionId,omitempty"`
+	Relation       string               `json:"relation"`
+	State          agent.ExecutionState `json:"state"`
+	ConversationID agent.ConversationID `json:"conversationId,omitempty"`
+	Prompt         string               `json:"prompt"`
+	CreatedAt      time.Time            `json:"createdAt"`
+	Children       []*ExecutionTreeNode `json:"children,omitempty"`
+	metadata       agent.ExecutionMetadata
+}
+
+// StateExecutionTreeCmd renders the tree of turns, forks and fallbacks that contains an execution.
+type StateExecutionTreeCmd struct {
+	ID     string `arg:"" required:"" help:"Execution ID"`
+	Format string `help:"Output format (text or json)." default:"text" enum:"text,json"`
+}
+
+// Run executes the execution tree command.
+func (e *StateExecutionTreeCmd) Run(ctx context.Context, repository agent.ExecutionRepository) error {
+	id := agent.ExecutionID(e.ID)
+	if err := id.Validate(); err != nil {
+		return fmt.Errorf("validate execution id: %w", err)
+	}
+
+	if _, err := repository.Get(ctx, id); err != nil {
+		return fmt.Errorf("load execution: %w", err)
+	}
+
+	ids, err := repository.Find(ctx)
+	if err != nil {
+		return fmt.Errorf("list executions: %w", err)
+	}
+
+	nodes := make(map[agent.ExecutionID]*ExecutionTreeNode, len(ids))
+	for _, executionID := range ids {
+		node, err := loadExecutionTreeNode(ctx, repository, executionID)
+		if err != nil {
+			slog.Warn("Failed to load execution.", slog.String("id", string(executionID)), slog.String("error", err.Error()))
+			continue
+		}
+		nodes[executionID] = node
+	}
+
+	for _, executionID := range ids {
+		node, ok := nodes[executionID]
+		if !ok {
+			continue
+		}
+
+		if parentID := node.metadata.Parent(); parentID != nil {
+			if parent, ok := nodes[*parentID]; ok {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].CreatedAt.Before(node.Children[j].CreatedAt)
+		})
+	}
+
+	root := executionTreeRoot(nodes, id)
+
+	if e.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(root); err != nil {
+			return fmt.Errorf("encode execution tree output: %w", err)
+		}
+
+		return nil
+	}
+
+	writeExecutionTree(os.Stdout, root, id, "", "")
+
+	return nil
+}
+
+func loadExecutionTreeNode(ctx context.Context, repository agent.ExecutionRepository, id agent.ExecutionID) (*ExecutionTreeNode, error) {
+	execution, err := repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := execution.GetMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load execution metadata: %w", err)
+	}
+
+	status, err := execution.GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load execution status: %w", err)
+	}
+
+	input, err := execution.GetInput(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load execution input: %w", err)
+	}
+
+	node := &ExecutionTreeNode{
+		ID:        id,
+		AgentID:   metadata.AgentID,
+		SessionID: metadata.SessionID,
+		Relation:  executionRelation(metadata),
+		State:     status.State,
+		Prompt:    metadata.Prompt(input),
+		CreatedAt: status.CreatedAt,
+		metadata:  metadata,
+	}
+
+	if input.ConversationID != nil {
+		node.ConversationID = *input.ConversationID
+	}
+
+	if status.State == agent.ExecutionSucceeded {
+		if result, err := execution.GetResult(ctx); err == nil && result.ConversationID != "" {
+			node.ConversationID = result.ConversationID
+		}
+	}
+
+	return node, nil
+}
+
+func executionRelation(metadata agent.ExecutionMetadata) string {
+	switch {
+	case metadata.Fork:
+		return "fork"
+	case metadata.FallbackOf != nil:
+		return "fallback"
+	case metadata.ParentID != nil:
+		return "turn"
+	default:
+		return "root"
+	}
+}
+
+// executionTreeRoot walks up the parents of the execution to the first turn of its conversation.
+func executionTreeRoot(nodes map[agent.ExecutionID]*ExecutionTreeNode, id agent.ExecutionID) *ExecutionTreeNode {
+	root := nodes[id]
+	visited := map[agent.ExecutionID]bool{id: true}
+
+	for {
+		parentID := root.metadata.Parent()
+		if parentID == nil || visited[*parentID] {
+			return root
+		}
+
+		parent, ok := nodes[*parentID]
+		if !ok {
+			return root
+		}
+
+		visited[*parentID] = true
+		root = parent
+	}
+}
+
+func writeExecutionTree(w io.Writer, node *ExecutionTreeNode, selected agent.ExecutionID, prefix string, childPrefix string) {
+	line := fmt.Sprintf("%s  %s  %s", node.ID, node.AgentID, node.State)
+	if node.Relation == "fork" || node.Relation == "fallback" {
+		line = node.Relation + " " + line
+	}
+	if node.ConversationID != "" {
+		line += "  " + string(node.ConversationID)
+	}
+	line += "  " + promptExcerpt(node.Prompt)
+	if node.ID == selected {
+		line += "  <"
+	}
+
+	fmt.Fprintln(w, prefix+line)
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			writeExecutionTree(w, child, selected, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			writeExecutionTree(w, child, selected, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func promptExcerpt(prompt string) string {
+	prompt = strings.Join(strings.Fields(prompt), " ")
+	runes := []rune(prompt)
+	if len(runes) <= executionTreePromptExcerpt {
+		return fmt.Sprintf("%q", prompt)
+	}
+
+	return fmt.Sprintf("%q", string(runes[:executionTreePromptExcerpt])+"…")
+}
@@ -0,0 +1,118 @@
+package briefkitctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+	sqlitestore "github.com/orbiqd/orbiqd-briefkit/internal/pkg/store/sqlite"
+)
+
+// StateMigrateOutput summarizes what a migration copied.
+type StateMigrateOutput struct {
+	Executions StateMigrateCount `json:"executions"`
+	Agents     StateMigrateCount `json:"agents"`
+}
+
+// StateMigrateCount counts the migrated, already present and failed items of one kind.
+type StateMigrateCount struct {
+	Migrated int `json:"migrated"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+// StateMigrateCmd copies executions and agent configs from the fs store into the SQLite database.
+type StateMigrateCmd struct {
+	Overwrite bool `help:"Replace agent configs that already exist in the database."`
+}
+
+// Run executes the state migrate command.
+func (e *StateMigrateCmd) Run(ctx context.Context, store cli.StoreConfig) error {
+	sourceConfig := store
+	sourceConfig.Driver = cli.StoreDriverFS
+
+	targetConfig := store
+	targetConfig.Driver = cli.StoreDriverSQLite
+
+	source, err := cli.OpenStoreFromConfig(sourceConfig)
+	if err != nil {
+		return fmt.Errorf("open fs store: %w", err)
+	}
+	defer source.Close()
+
+	target, err := cli.OpenStoreFromConfig(targetConfig)
+	if err != nil {
+		return fmt.Errorf("open sqlite store: %w", err)
+	}
+	defer target.Close()
+
+	targetExecutions, ok := target.Executions.(*sqlitestore.Repository)
+	if !ok {
+		return errors.New("sqlite execution store does not support imports")
+	}
+
+	var output StateMigrateOutput
+
+	agentIDs, err := source.Configs.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list agent configs: %w", err)
+	}
+
+	for _, id := range agentIDs {
+		if !e.Overwrite {
+			exists, err := target.Configs.Exists(ctx, id)
+			if err != nil {
+				return fmt.Errorf("check agent config %s: %w", id, err)
+			}
+			if exists {
+				output.Agents.Skipped++
+				continue
+			}
+		}
+
+		config, err := source.Configs.Get(ctx, id)
+		if err == nil {
+			err = target.Configs.Update(ctx, id, config)
+		}
+		if err != nil {
+			slog.Warn("Failed to migrate agent config.", slog.String("agentId", string(id)), slog.String("error", err.Error()))
+			output.Agents.Failed++
+			continue
+		}
+		output.Agents.Migrated++
+	}
+
+	executionIDs, err := source.Executions.Find(ctx)
+	if err != nil {
+		return fmt.Errorf("list executions: %w", err)
+	}
+
+	for _, id := range executionIDs {
+		execution, err := source.Executions.Get(ctx, id)
+		if err == nil {
+			err = targetExecutions.Import(ctx, id, execution)
+		}
+
+		switch {
+		case errors.Is(err, sqlitestore.ErrExecutionExists):
+			output.Executions.Skipped++
+		case err != nil:
+			slog.Warn("Failed to migrate execution.", slog.String("id", string(id)), slog.String("error", err.Error()))
+			output.Executions.Failed++
+		default:
+			output.Executions.Migrated++
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("encode state migrate output: %w", err)
+	}
+
+	return nil
+}
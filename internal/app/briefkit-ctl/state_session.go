@@ -0,0 +1,6 @@
+package briefkitctl
+
+type StateSessionCmd struct {
+	List StateSessionListCmd `cmd:"" help:"List sessions"`
+	Show StateSessionShowCmd `cmd:"" help:"Show a session and its transcript"`
+}
@@ -0,0 +1,56 @@
+package briefkitctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// SessionListOutput captures the list output payload for sessions.
+type SessionListOutput struct {
+	Items []agent.Session `json:"items"`
+	Count int             `json:"count"`
+}
+
+// StateSessionListCmd lists stored sessions.
+type StateSessionListCmd struct{}
+
+// Run executes the session list command.
+func (e *StateSessionListCmd) Run(ctx context.Context, repository agent.SessionRepository) error {
+	ids, err := repository.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+
+	items := make([]agent.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := repository.Get(ctx, id)
+		if err != nil {
+			slog.Warn(
+				"Failed to load session.",
+				slog.String("id", string(id)),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		items = append(items, session)
+	}
+
+	output := SessionListOutput{
+		Items: items,
+		Count: len(items),
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("encode session list output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package briefkitctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// SessionShowOutput captures the output payload for session show.
+type SessionShowOutput struct {
+	Session    agent.Session           `json:"session"`
+	Transcript []agent.TranscriptEntry `json:"transcript"`
+}
+
+// StateSessionShowCmd shows a single session and its transcript.
+type StateSessionShowCmd struct {
+	ID string `arg:"" required:"" help:"Session ID"`
+}
+
+// Run executes the session show command.
+func (e *StateSessionShowCmd) Run(ctx context.Context, repository agent.SessionRepository) error {
+	id := agent.SessionID(e.ID)
+	if err := id.Validate(); err != nil {
+		return fmt.Errorf("validate session id: %w", err)
+	}
+
+	session, err := repository.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("load session: %w", err)
+	}
+
+	transcript, err := repository.GetTranscript(ctx, id)
+	if err != nil {
+		return fmt.Errorf("load session transcript: %w", err)
+	}
+
+	output := SessionShowOutput{
+		Session:    session,
+		Transcript: transcript,
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("encode session show output: %w", err)
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package briefkit_mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
@@ -16,13 +18,14 @@ type Command struct {
 	Store cli.StoreConfig `embed:"" prefix:"store-"`
 }
 
-func (command *Command) Run(ctx context.Context, agentConfigRepository agent.ConfigRepository, executionRepository agent.ExecutionRepository) error {
+func (command *Command) Run(ctx context.Context, agentConfigRepository agent.ConfigRepository, executionRepository agent.ExecutionRepository, sessionRepository agent.SessionRepository, runtimeRegistry agent.RuntimeRegistry) error {
 	agentIds, err := agentConfigRepository.List(ctx)
 	if err != nil {
 		return fmt.Errorf("list agent ids: %w", err)
 	}
 
 	var agentExecTools []mcpserver.ServerTool
+	agentConfigs := make(map[agent.AgentID]agent.Config)
 
 	for _, agentId := range agentIds {
 		agentConfig, err := agentConfigRepository.Get(ctx, agentId)
@@ -30,12 +33,36 @@ func (command *Command) Run(ctx context.Context, agentConfigRepository agent.Con
 			return fmt.Errorf("get agent config: %s: %w", agentId, err)
 		}
 
-		agentExecTool, err := createExecTool(agentId, agentConfig, executionRepository)
+		if err := agentConfig.Validate(ctx, runtimeRegistry); err != nil {
+			slog.Warn("Agent skipped because its config is invalid.", slog.String("agentId", string(agentId)), slog.String("error", err.Error()))
+			continue
+		}
+
+		if err := checkRuntimeVersion(ctx, runtimeRegistry, agentConfig); err != nil {
+			if !errors.Is(err, agent.ErrRuntimeVersionUnsupported) {
+				slog.Warn("Agent skipped because its runtime version is incompatible.", slog.String("agentId", string(agentId)), slog.String("error", err.Error()))
+				continue
+			}
+
+			slog.Warn("Agent runtime version is not supported, output parsing may fail.", slog.String("agentId", string(agentId)), slog.String("error", err.Error()))
+		}
+
+		agentExecTool, err := createExecTool(agentId, agentConfig, executionRepository, sessionRepository)
 		if err != nil {
 			return fmt.Errorf("create agent exec tool: %s: %w", agentId, err)
 		}
 
 		agentExecTools = append(agentExecTools, agentExecTool)
+		agentConfigs[agentId] = agentConfig
+	}
+
+	if len(agentConfigs) > 0 {
+		handoffTool, err := createHandoffTool(agentConfigs, executionRepository, sessionRepository)
+		if err != nil {
+			return fmt.Errorf("create handoff tool: %w", err)
+		}
+
+		agentExecTools = append(agentExecTools, handoffTool)
 	}
 
 	server := mcpserver.NewMCPServer(
@@ -53,3 +80,17 @@ func (command *Command) Run(ctx context.Context, agentConfigRepository agent.Con
 
 	return nil
 }
+
+func checkRuntimeVersion(ctx context.Context, runtimeRegistry agent.RuntimeRegistry, agentConfig agent.Config) error {
+	runtime, err := runtimeRegistry.Get(ctx, agentConfig.Runtime.Kind)
+	if err != nil {
+		return fmt.Errorf("get runtime %s: %w", agentConfig.Runtime.Kind, err)
+	}
+
+	runtimeInfo, err := runtime.GetInfo(ctx)
+	if err != nil {
+		slog.Warn("Failed to detect runtime version.", slog.String("runtimeKind", string(agentConfig.Runtime.Kind)), slog.String("error", err.Error()))
+	}
+
+	return agentConfig.CheckRuntimeVersion(runtimeInfo)
+}
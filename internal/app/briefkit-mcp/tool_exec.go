@@ -2,7 +2,10 @@ package briefkit_mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"os"
 	"strings"
 	"time"
 
@@ -13,23 +16,42 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
 )
 
-func createExecTool(agentId agent.AgentID, agentConfig agent.Config, executionRepository agent.ExecutionRepository) (mcpserver.ServerTool, error) {
+func createExecTool(agentId agent.AgentID, agentConfig agent.Config, executionRepository agent.ExecutionRepository, sessionRepository agent.SessionRepository) (mcpserver.ServerTool, error) {
 	toolName := fmt.Sprintf("exec_%s", strcase.ToSnake(string(agentId)))
 
+	modelOptions := []mcp.PropertyOption{
+		mcp.Description("Optional model override for the execution."),
+	}
+	if choices := agentConfig.Models.Choices(); len(choices) > 0 {
+		modelOptions = append(modelOptions, mcp.Enum(choices...))
+	}
+
 	tool := mcp.NewTool(toolName,
 		mcp.WithDescription("Runs a prompt on agent, optionally continuing a conversation or overriding the model."),
 		mcp.WithString("prompt",
 			mcp.Description("Prompt to send to the agent."),
 			mcp.Required(),
 		),
-		mcp.WithString("model",
-			mcp.Description("Optional model override for the execution."),
-		),
+		mcp.WithString("model", modelOptions...),
 		mcp.WithString("conversationId",
 			mcp.Description("Conversation ID to continue an existing agent session."),
 		),
+		mcp.WithString("sessionId",
+			mcp.Description("BriefKit session ID to continue. Each result returns the session ID; a new session is started when omitted."),
+		),
+		mcp.WithString("systemPrompt",
+			mcp.Description("Optional system prompt added after the agent instructions."),
+		),
+		mcp.WithObject("outputSchema",
+			mcp.Description("Optional JSON Schema the response must conform to. The validated JSON value is returned in the structured field."),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("Optional string labels recorded on the execution, used to find it later."),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
 	)
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -54,56 +76,58 @@ func createExecTool(agentId agent.AgentID, agentConfig agent.Config, executionRe
 			executionInput.ConversationID = (*agent.ConversationID)(&conversationId)
 		}
 
-		executionId, err := executionRepository.Create(ctx, executionInput, agentConfig)
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+		executionInput.SystemPrompt = request.GetString("systemPrompt", "")
+
+		if outputSchema, ok := request.GetArguments()["outputSchema"]; ok && outputSchema != nil {
+			payload, err := json.Marshal(outputSchema)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Errorf("encode output schema: %w", err).Error()), nil
+			}
+			executionInput.OutputSchema = payload
 		}
 
-		if err := briefkitrunner.Spawn(ctx, executionId); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+		labels := make(map[string]string)
+		if values, ok := request.GetArguments()["labels"].(map[string]any); ok {
+			for key, value := range values {
+				label, ok := value.(string)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("label %s must be a string", key)), nil
+				}
+				labels[key] = label
+			}
 		}
 
-		ticker := time.NewTicker(500 * time.Millisecond)
-		defer ticker.Stop()
+		sessionId := agent.SessionID(request.GetString("sessionId", ""))
+		parentId := agent.EmptyExecutionID
+		if sessionId != "" {
+			executionInput, err = agent.ContinueSession(ctx, sessionRepository, sessionId, agentId, executionInput)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 
-		execution, err := executionRepository.Get(ctx, executionId)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Errorf("get execution: %w", err).Error()), nil
+			parentId, err = agent.SessionHead(ctx, sessionRepository, sessionId)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 		}
 
-		for {
-			select {
-			case <-ctx.Done():
-				return mcp.NewToolResultError(fmt.Errorf("wait for completion: %w", err).Error()), nil
-			case <-ticker.C:
-				status, err := execution.GetStatus(ctx)
-				if err != nil {
-					return mcp.NewToolResultError(fmt.Errorf("failed to get execution status: %w", err).Error()), nil
-				}
-
-				switch status.State {
-				case agent.ExecutionSucceeded:
-					executionResult, err := execution.GetResult(ctx)
-					if err != nil {
-						return mcp.NewToolResultError(fmt.Errorf("failed to get execution result: %w", err).Error()), nil
-					}
-
-					return mcp.NewToolResultStructured(executionResult, executionResult.Response), nil
-				case agent.ExecutionFailed:
-					var errors []string
-					if status.Error != nil {
-						errors = append(errors, fmt.Sprintf("%s", *status.Error))
-					}
-
-					if status.ExitCode != nil {
-						errors = append(errors, fmt.Sprintf("Exit code is %d.", *status.ExitCode))
-					}
-
-					return mcp.NewToolResultErrorf("Execution failed. %s", strings.Join(errors, " ")), nil
-				}
+		if executionInput.WorkingDirectory == nil {
+			// The runner inherits the server directory, record it so executions can be found by it.
+			workingDir, err := os.Getwd()
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Errorf("get working directory: %w", err).Error()), nil
+			}
+			executionInput.WorkingDirectory = &workingDir
+		}
 
+		if sessionId == "" {
+			sessionId, err = agent.StartSession(ctx, sessionRepository, agentId, executionInput)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
 		}
+
+		return runExecution(ctx, executionRepository, agentId, agentConfig, sessionId, executionInput, agent.WithParentID(parentId), agent.WithLabels(labels)), nil
 	}
 
 	return mcpserver.ServerTool{
@@ -111,3 +135,57 @@ func createExecTool(agentId agent.AgentID, agentConfig agent.Config, executionRe
 		Handler: handler,
 	}, nil
 }
+
+// runExecution creates the execution for a session turn, spawns the runner and waits for the outcome.
+// Failed, fallback and deferred executions are reported as tool results rather than errors.
+func runExecution(ctx context.Context, executionRepository agent.ExecutionRepository, agentId agent.AgentID, agentConfig agent.Config, sessionId agent.SessionID, executionInput agent.ExecutionInput, options ...agent.ExecutionOption) *mcp.CallToolResult {
+	options = append([]agent.ExecutionOption{agent.WithAgentID(agentId), agent.WithSessionID(sessionId)}, options...)
+
+	executionId, err := executionRepository.Create(ctx, executionInput, agentConfig, options...)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+
+	if err := briefkitrunner.Spawn(ctx, executionId); err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+
+	outcome, err := cli.WaitForExecution(ctx, executionRepository, executionId,
+		cli.WithFallbackHandler(func(failed agent.ExecutionID, fallback agent.ExecutionID) {
+			slog.Info("Execution failed, following the fallback execution.",
+				slog.String("executionId", string(failed)),
+				slog.String("fallbackExecutionId", string(fallback)))
+		}),
+	)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+
+	status := outcome.Status
+	switch status.State {
+	case agent.ExecutionSucceeded:
+		text := outcome.Result.Response
+		if len(outcome.Result.Structured) > 0 {
+			text = string(outcome.Result.Structured)
+		}
+
+		return mcp.NewToolResultStructured(*outcome.Result, text)
+	case agent.ExecutionDeferred:
+		return mcp.NewToolResultText(fmt.Sprintf("Execution %s deferred until %s after hitting a rate limit. The runner retries automatically.", outcome.ID, utils.FormatClockTime(*status.ResetAt, time.Now())))
+	}
+
+	var errors []string
+	if status.Error != nil {
+		errors = append(errors, fmt.Sprintf("%s", *status.Error))
+	}
+
+	if status.ExitCode != nil {
+		errors = append(errors, fmt.Sprintf("Exit code is %d.", *status.ExitCode))
+	}
+
+	if status.ResetAt != nil {
+		errors = append(errors, fmt.Sprintf("Rate limit resets at %s.", utils.FormatClockTime(*status.ResetAt, time.Now())))
+	}
+
+	return mcp.NewToolResultErrorf("Execution failed. %s", strings.Join(errors, " "))
+}
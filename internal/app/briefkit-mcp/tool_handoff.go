@@ -0,0 +1,110 @@
+package briefkit_mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+)
+
+func createHandoffTool(agentConfigs map[agent.AgentID]agent.Config, executionRepository agent.ExecutionRepository, sessionRepository agent.SessionRepository) (mcpserver.ServerTool, error) {
+	agentIds := make([]string, 0, len(agentConfigs))
+	for agentId := range agentConfigs {
+		agentIds = append(agentIds, string(agentId))
+	}
+	slices.Sort(agentIds)
+
+	tool := mcp.NewTool("handoff",
+		mcp.WithDescription("Continues a BriefKit session on another agent, seeding it with the prior turns. Returns the response and the new session ID."),
+		mcp.WithString("sessionId",
+			mcp.Description("BriefKit session ID to hand off."),
+			mcp.Required(),
+		),
+		mcp.WithString("agentId",
+			mcp.Description("Agent that takes over the conversation."),
+			mcp.Required(),
+			mcp.Enum(agentIds...),
+		),
+		mcp.WithString("mode",
+			mcp.Description("How prior turns seed the new conversation: the full transcript or short excerpts of older turns."),
+			mcp.Enum(string(agent.HandoffTranscript), string(agent.HandoffExcerpt)),
+			mcp.DefaultString(string(agent.HandoffTranscript)),
+		),
+		mcp.WithString("prompt",
+			mcp.Description("Next instruction for the agent that takes over."),
+		),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sourceId, err := request.RequireString("sessionId")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		agentId, err := request.RequireString("agentId")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		agentConfig, ok := agentConfigs[agent.AgentID(agentId)]
+		if !ok {
+			return mcp.NewToolResultErrorf("Agent %s is not available.", agentId), nil
+		}
+
+		source, err := sessionRepository.Get(ctx, agent.SessionID(sourceId))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("get session: %w", err).Error()), nil
+		}
+
+		transcript, err := sessionRepository.GetTranscript(ctx, source.ID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("get session transcript: %w", err).Error()), nil
+		}
+
+		mode := agent.HandoffMode(request.GetString("mode", string(agent.HandoffTranscript)))
+		instruction := agent.HandoffInstruction(request.GetString("prompt", ""))
+		prompt, err := agent.BuildHandoffPrompt(source, transcript, mode, instruction)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		parentId, err := agent.SessionHead(ctx, sessionRepository, source.ID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		sessionId, err := agent.StartHandoffSession(ctx, sessionRepository, source, agent.AgentID(agentId))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		slog.Info("Handing off session.",
+			slog.String("sourceSessionId", string(source.ID)),
+			slog.String("sessionId", string(sessionId)),
+			slog.String("agentId", agentId),
+			slog.String("mode", string(mode)))
+
+		executionInput := agent.ExecutionInput{
+			Timeout: utils.Duration(time.Minute * 5),
+			Prompt:  prompt,
+		}
+
+		executionInput, err = agent.ContinueSession(ctx, sessionRepository, sessionId, agent.AgentID(agentId), executionInput)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return runExecution(ctx, executionRepository, agent.AgentID(agentId), agentConfig, sessionId, executionInput, agent.WithParentID(parentId), agent.WithInstruction(instruction)), nil
+	}
+
+	return mcpserver.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}, nil
+}
@@ -2,9 +2,11 @@ package briefkit_runner
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
@@ -12,14 +14,26 @@ import (
 )
 
 type RunnerCommand struct {
-	Log   cli.LogConfig   `embed:"" prefix:"log-"`
-	Store cli.StoreConfig `embed:"" prefix:"store-"`
+	Log       cli.LogConfig       `embed:"" prefix:"log-"`
+	Store     cli.StoreConfig     `embed:"" prefix:"store-"`
+	Retention cli.RetentionConfig `embed:"" prefix:"retention-"`
 
 	ExecutionID agent.ExecutionID `arg:"" required:"" help:"Execution ID to run."`
 	Retry       bool              `help:"Allow rerunning finished executions."`
 }
 
-func (command *RunnerCommand) Run(ctx context.Context, executionRepository agent.ExecutionRepository, runtimeRegistry agent.RuntimeRegistry) error {
+func (command *RunnerCommand) Run(ctx context.Context, executionRepository agent.ExecutionRepository, configRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, turnRepository agent.TurnRepository, runtimeRegistry agent.RuntimeRegistry) error {
+	executionID := command.ExecutionID
+
+	err := command.run(ctx, executionRepository, configRepository, sessionRepository, turnRepository, runtimeRegistry)
+
+	command.applyRetention(ctx, executionRepository, executionID, command.ExecutionID)
+
+	return err
+}
+
+// run executes the execution and continues on its fallback executions when it fails.
+func (command *RunnerCommand) run(ctx context.Context, executionRepository agent.ExecutionRepository, configRepository agent.ConfigRepository, sessionRepository agent.SessionRepository, turnRepository agent.TurnRepository, runtimeRegistry agent.RuntimeRegistry) error {
 	slog.Info("Starting BriefKIT agent runner.", slog.String("executionID", string(command.ExecutionID)))
 
 	execution, err := executionRepository.Get(ctx, command.ExecutionID)
@@ -33,6 +47,11 @@ func (command *RunnerCommand) Run(ctx context.Context, executionRepository agent
 		return fmt.Errorf("get execution input: %w", err)
 	}
 
+	executionMetadata, err := execution.GetMetadata(ctx)
+	if err != nil {
+		return fmt.Errorf("get execution metadata: %w", err)
+	}
+
 	executionStatus, err := execution.GetStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("get execution status: %w", err)
@@ -44,8 +63,8 @@ func (command *RunnerCommand) Run(ctx context.Context, executionRepository agent
 			return fmt.Errorf("execution state is %s", executionStatus.State)
 		}
 
-		if executionStatus.State != agent.ExecutionFailed && executionStatus.State != agent.ExecutionSucceeded {
-			return fmt.Errorf("execution state must be created, failed, or succeeded to retry")
+		if executionStatus.State != agent.ExecutionFailed && executionStatus.State != agent.ExecutionSucceeded && executionStatus.State != agent.ExecutionDeferred {
+			return fmt.Errorf("execution state must be created, failed, succeeded, or deferred to retry")
 		}
 
 		slog.Info("Retrying execution.", slog.String("executionID", string(command.ExecutionID)), slog.String("executionState", string(executionStatus.State)))
@@ -63,55 +82,512 @@ func (command *RunnerCommand) Run(ctx context.Context, executionRepository agent
 	}
 	slog.Debug("Got runtime.", slog.String("executionID", string(command.ExecutionID)), slog.String("runtimeKind", string(agentConfig.Runtime.Kind)))
 
-	runCtx, cancel := context.WithTimeout(ctx, time.Duration(executionInput.Timeout))
-	defer cancel()
+	if err := agentConfig.Validate(ctx, runtimeRegistry); err != nil {
+		if updateErr := command.finishExecutionWithError(ctx, execution, executionStatus, err); updateErr != nil {
+			return updateErr
+		}
+		return fmt.Errorf("validate agent config: %w", err)
+	}
 
-	executionStatus.State = agent.ExecutionStarted
-	executionStatus.Attempts++
-	executionStatus.Error = nil
-	executionStatus.ExitCode = nil
-	if err := execution.UpdateStatus(ctx, executionStatus); err != nil {
-		return fmt.Errorf("update execution status: %w", err)
+	runtimeInfo, err := runtime.GetInfo(ctx)
+	if err != nil {
+		slog.Warn("Failed to detect runtime version.", slog.String("executionID", string(command.ExecutionID)), slog.String("error", err.Error()))
 	}
+	executionStatus.RuntimeVersion = runtimeInfo.Version
+
+	if err := agentConfig.CheckRuntimeVersion(runtimeInfo); err != nil {
+		if !errors.Is(err, agent.ErrRuntimeVersionUnsupported) {
+			if updateErr := command.finishExecutionWithError(ctx, execution, executionStatus, err); updateErr != nil {
+				return updateErr
+			}
+			return fmt.Errorf("check runtime version: %w", err)
+		}
 
-	instance, err := runtime.Execute(runCtx, command.ExecutionID, executionInput, agentConfig)
+		slog.Warn("Runtime version is not supported, output parsing may fail.", slog.String("executionID", string(command.ExecutionID)), slog.String("error", err.Error()))
+	}
+
+	if err := agentConfig.CheckCapabilities(ctx, runtimeRegistry, executionInput); err != nil {
+		if updateErr := command.finishExecutionWithError(ctx, execution, executionStatus, err); updateErr != nil {
+			return updateErr
+		}
+		return fmt.Errorf("check runtime capabilities: %w", err)
+	}
+
+	capabilities, err := runtime.GetCapabilities(ctx)
 	if err != nil {
+		return fmt.Errorf("get runtime capabilities: %w", err)
+	}
+
+	runtimeInput := executionInput
+	var outputSchema *agent.OutputSchema
+	if len(executionInput.OutputSchema) > 0 {
+		outputSchema, err = agent.CompileOutputSchema(executionInput.OutputSchema)
+		if err != nil {
+			if updateErr := command.finishExecutionWithError(ctx, execution, executionStatus, err); updateErr != nil {
+				return updateErr
+			}
+			return fmt.Errorf("compile output schema: %w", err)
+		}
+
+		if !capabilities.StructuredOutput {
+			runtimeInput.Prompt += agent.StructuredOutputInstructions(executionInput.OutputSchema)
+		}
+	}
+
+	// Deferred attempts count against the rate limit policy only, so they neither use up retries nor grow the backoff.
+	var failures, deferrals int
+	for {
+		executionStatus.State = agent.ExecutionStarted
+		executionStatus.Attempts++
+		executionStatus.Error = nil
+		executionStatus.ExitCode = nil
+		executionStatus.ResetAt = nil
+		if err := updateStatus(ctx, execution, &executionStatus); err != nil {
+			return fmt.Errorf("update execution status: %w", err)
+		}
+
+		startedAt := time.Now()
+		result, structured, err := command.runAttempt(ctx, execution, &executionStatus, runtime, runtimeInput, agentConfig, outputSchema, capabilities)
+		executionStatus.History = append(executionStatus.History, newExecutionAttempt(executionStatus.Attempts, startedAt, result, err))
+
+		if err == nil {
+			// The attempt history has to be stored before the result marks the execution as succeeded.
+			if err := updateStatus(ctx, execution, &executionStatus); err != nil {
+				return fmt.Errorf("update execution status: %w", err)
+			}
+
+			executionResult := agent.ExecutionResult{
+				Response:       result.Response,
+				ConversationID: result.ConversationID,
+				Structured:     structured,
+				AgentID:        executionMetadata.AgentID,
+				SessionID:      executionMetadata.SessionID,
+				Usage:          result.Usage,
+			}
+			if err := execution.SetResult(ctx, executionResult); err != nil {
+				return fmt.Errorf("set execution result: %w", err)
+			}
+
+			command.recordTurn(ctx, sessionRepository, turnRepository, executionMetadata, executionInput, executionStatus, &executionResult, nil)
+
+			slog.Info("Execution succeeded.")
+
+			return nil
+		}
+
+		if retryAt, retry := agentConfig.RateLimit.RetryTime(err, deferrals, time.Now()); retry {
+			deferrals++
+			if err := command.deferExecution(ctx, execution, &executionStatus, err, retryAt); err != nil {
+				return fmt.Errorf("defer execution: %w", err)
+			}
+			continue
+		}
+
+		failures++
+		if delay, retry := agentConfig.Retry.RetryDelay(err, failures); retry {
+			if agentConfig.Retry.Resume && capabilities.Resume && result.ConversationID != "" {
+				conversationID := result.ConversationID
+				runtimeInput.ConversationID = &conversationID
+				runtimeInput.ForkConversation = false
+			}
+
+			if err := command.backoffExecution(ctx, execution, &executionStatus, err, delay); err != nil {
+				return fmt.Errorf("back off execution: %w", err)
+			}
+			continue
+		}
+
+		fallbackID := command.createFallbackExecution(ctx, executionRepository, configRepository, runtimeRegistry, executionMetadata, executionInput, agentConfig, err)
+		if fallbackID != agent.EmptyExecutionID {
+			executionStatus.FallbackExecutionID = &fallbackID
+		}
+
 		if updateErr := command.finishExecutionWithError(ctx, execution, executionStatus, err); updateErr != nil {
 			return updateErr
 		}
-		return fmt.Errorf("execute runtime: %w", err)
+
+		if fallbackID == agent.EmptyExecutionID {
+			command.recordTurn(ctx, sessionRepository, turnRepository, executionMetadata, executionInput, executionStatus, nil, err)
+
+			return fmt.Errorf("run runtime: %w", err)
+		}
+
+		slog.Info("Execution failed, continuing on the fallback execution.",
+			slog.String("executionID", string(command.ExecutionID)),
+			slog.String("fallbackExecutionID", string(fallbackID)),
+			slog.String("error", err.Error()))
+
+		command.ExecutionID = fallbackID
+		command.Retry = false
+
+		return command.run(ctx, executionRepository, configRepository, sessionRepository, turnRepository, runtimeRegistry)
+	}
+}
+
+// applyRetention deletes the executions expired under the configured retention policy, except the ones this runner ran.
+// Failures are logged because they do not affect the execution outcome.
+func (command *RunnerCommand) applyRetention(ctx context.Context, executionRepository agent.ExecutionRepository, protected ...agent.ExecutionID) {
+	policy, enabled := command.Retention.Policy()
+	if !enabled {
+		return
 	}
 
-	executionStatus.State = agent.ExecutionRunning
-	if err := execution.UpdateStatus(ctx, executionStatus); err != nil {
-		return fmt.Errorf("update execution status: %w", err)
+	ids, err := policy.SelectExpired(ctx, executionRepository, time.Now())
+	if err != nil {
+		slog.Warn("Failed to select expired executions.", slog.String("error", err.Error()))
+		return
+	}
+
+	ids = slices.DeleteFunc(ids, func(id agent.ExecutionID) bool {
+		return slices.Contains(protected, id)
+	})
+
+	deleted, err := cli.PruneExecutions(ctx, executionRepository, ids)
+	if err != nil {
+		slog.Warn("Failed to prune expired executions.", slog.String("error", err.Error()))
+	}
+
+	if len(deleted) > 0 {
+		slog.Info("Pruned expired executions.", slog.Int("count", len(deleted)))
+	}
+}
+
+// runAttempt executes the runtime once within the execution timeout.
+func (command *RunnerCommand) runAttempt(ctx context.Context, execution agent.Execution, status *agent.ExecutionStatus, runtime agent.Runtime, input agent.ExecutionInput, agentConfig agent.Config, outputSchema *agent.OutputSchema, capabilities agent.RuntimeCapabilities) (agent.RuntimeResult, json.RawMessage, error) {
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(input.Timeout))
+	defer cancel()
+
+	instance, err := runtime.Execute(runCtx, command.ExecutionID, input, agentConfig)
+	if err != nil {
+		return agent.RuntimeResult{}, nil, err
 	}
 
-	events := instance.Events()
-	go command.drainRuntimeEvents(runCtx, events)
+	status.State = agent.ExecutionRunning
+	if err := updateStatus(ctx, execution, status); err != nil {
+		return agent.RuntimeResult{}, nil, fmt.Errorf("update execution status: %w", err)
+	}
+
+	recorded := command.recordRuntimeEvents(ctx, execution, instance.Events())
 
 	result, err := instance.Wait(runCtx)
+	<-recorded
 	if err != nil {
-		if updateErr := command.finishExecutionWithError(ctx, execution, executionStatus, err); updateErr != nil {
-			return updateErr
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			err = &agent.RuntimeExecutionError{
+				Message: fmt.Sprintf("execution timed out after %s", time.Duration(input.Timeout)),
+				Class:   agent.RuntimeErrorTimeout,
+				Cause:   err,
+			}
 		}
 
-		return fmt.Errorf("wait for runtime: %w", err)
+		return result, nil, err
 	}
 
-	executionResult := agent.ExecutionResult{
-		Response:       result.Response,
-		ConversationID: result.ConversationID,
+	if outputSchema == nil {
+		return result, nil, nil
 	}
-	if err := execution.SetResult(ctx, executionResult); err != nil {
-		return fmt.Errorf("set execution result: %w", err)
+
+	return command.resolveStructuredOutput(runCtx, execution, runtime, input, agentConfig, outputSchema, capabilities, result)
+}
+
+// createFallbackExecution creates a linked execution with the same input on the first usable fallback agent.
+// It returns EmptyExecutionID when the failure class does not allow a fallback or no fallback agent is usable.
+func (command *RunnerCommand) createFallbackExecution(ctx context.Context, executionRepository agent.ExecutionRepository, configRepository agent.ConfigRepository, runtimeRegistry agent.RuntimeRegistry, metadata agent.ExecutionMetadata, input agent.ExecutionInput, agentConfig agent.Config, cause error) agent.ExecutionID {
+	if len(agentConfig.Fallback) == 0 || !agent.ClassifyRuntimeError(cause).AllowsFallback() {
+		return agent.EmptyExecutionID
+	}
+
+	for i, agentID := range agentConfig.Fallback {
+		fallbackConfig, err := configRepository.Get(ctx, agentID)
+		if err != nil {
+			slog.Warn("Skipping fallback agent.", slog.String("agentID", string(agentID)), slog.String("error", err.Error()))
+			continue
+		}
+
+		if err := fallbackConfig.Validate(ctx, runtimeRegistry); err != nil {
+			slog.Warn("Skipping fallback agent.", slog.String("agentID", string(agentID)), slog.String("error", err.Error()))
+			continue
+		}
+
+		// The remaining chain replaces the fallback agent's own list, so chains always terminate.
+		fallbackConfig.Fallback = agentConfig.Fallback[i+1:]
+
+		// Models and conversation IDs are specific to a runtime and do not carry over to another one.
+		fallbackInput := input
+		if fallbackConfig.Runtime.Kind != agentConfig.Runtime.Kind {
+			fallbackInput.Model = nil
+			fallbackInput.ConversationID = nil
+			fallbackInput.ForkConversation = false
+		}
+
+		if err := fallbackConfig.CheckCapabilities(ctx, runtimeRegistry, fallbackInput); err != nil {
+			slog.Warn("Skipping fallback agent.", slog.String("agentID", string(agentID)), slog.String("error", err.Error()))
+			continue
+		}
+
+		options := []agent.ExecutionOption{agent.WithAgentID(agentID), agent.WithFallbackOf(command.ExecutionID), agent.WithLabels(metadata.Labels)}
+		if metadata.SessionID != nil {
+			options = append(options, agent.WithSessionID(*metadata.SessionID))
+		}
+		if metadata.Instruction != "" {
+			options = append(options, agent.WithInstruction(metadata.Instruction))
+		}
+
+		id, err := executionRepository.Create(ctx, fallbackInput, fallbackConfig, options...)
+		if err != nil {
+			slog.Warn("Skipping fallback agent.", slog.String("agentID", string(agentID)), slog.String("error", err.Error()))
+			continue
+		}
+
+		return id
 	}
 
-	slog.Info("Execution succeeded.")
+	return agent.EmptyExecutionID
+}
+
+// recordTurn stores the finished execution as a turn of its session and appends it to the session transcript.
+// Failures are logged and do not change the outcome of the execution.
+func (command *RunnerCommand) recordTurn(ctx context.Context, sessionRepository agent.SessionRepository, turnRepository agent.TurnRepository, metadata agent.ExecutionMetadata, input agent.ExecutionInput, status agent.ExecutionStatus, result *agent.ExecutionResult, cause error) {
+	if metadata.SessionID == nil {
+		return
+	}
+
+	if err := command.appendTurn(ctx, sessionRepository, turnRepository, metadata, input, status, result, cause); err != nil {
+		slog.Warn("Failed to record session turn.",
+			slog.String("executionID", string(command.ExecutionID)),
+			slog.String("sessionID", string(*metadata.SessionID)),
+			slog.String("error", err.Error()))
+	}
+}
+
+func (command *RunnerCommand) appendTurn(ctx context.Context, sessionRepository agent.SessionRepository, turnRepository agent.TurnRepository, metadata agent.ExecutionMetadata, input agent.ExecutionInput, status agent.ExecutionStatus, result *agent.ExecutionResult, cause error) error {
+	sessionID := *metadata.SessionID
+
+	turnID, err := turnRepository.Create(ctx, agent.TurnRequest{
+		SessionID:   sessionID,
+		ExecutionID: command.ExecutionID,
+		Prompt:      input.Prompt,
+		Attachments: input.Attachments,
+	})
+	if err != nil {
+		return fmt.Errorf("create turn: %w", err)
+	}
+
+	turn, err := turnRepository.Get(ctx, turnID)
+	if err != nil {
+		return fmt.Errorf("get turn: %w", err)
+	}
+
+	entry := agent.TranscriptEntry{
+		TurnID:      turnID,
+		ExecutionID: command.ExecutionID,
+		AgentID:     metadata.AgentID,
+		Prompt:      metadata.Prompt(input),
+		CreatedAt:   status.CreatedAt,
+		FinishedAt:  time.Now(),
+	}
+
+	if result != nil {
+		entry.State = agent.ExecutionSucceeded
+		entry.Response = result.Response
+		entry.ConversationID = result.ConversationID
+
+		response := agent.TurnResponse{
+			AgentID:        result.AgentID,
+			ConversationID: result.ConversationID,
+			Response:       result.Response,
+			Structured:     result.Structured,
+		}
+		if err := turn.SetResponse(ctx, response); err != nil {
+			return fmt.Errorf("set turn response: %w", err)
+		}
+	} else {
+		entry.State = agent.ExecutionFailed
+		entry.Error = cause.Error()
+
+		turnStatus, err := turn.GetStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("get turn status: %w", err)
+		}
+
+		turnStatus.State = agent.ExecutionFailed
+		turnStatus.FinishedAt = &entry.FinishedAt
+		turnStatus.Error = &entry.Error
+		if err := turn.UpdateStatus(ctx, turnStatus); err != nil {
+			return fmt.Errorf("update turn status: %w", err)
+		}
+	}
+
+	if err := sessionRepository.AppendTranscript(ctx, sessionID, entry); err != nil {
+		return fmt.Errorf("append transcript: %w", err)
+	}
+
+	// Conversation IDs only resume on the session's own agent, so answers from fallback agents leave it unchanged.
+	if result == nil || result.ConversationID == "" {
+		return nil
+	}
+
+	session, err := sessionRepository.Get(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	if session.AgentID != metadata.AgentID {
+		return nil
+	}
+
+	conversationID := result.ConversationID
+	session.ConversationID = &conversationID
+	if err := sessionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("update session: %w", err)
+	}
+
+	return nil
+}
+
+// updateStatus stores the execution status and advances its revision to the stored one,
+// so the runner can keep updating the same status.
+func updateStatus(ctx context.Context, execution agent.Execution, status *agent.ExecutionStatus) error {
+	if err := execution.UpdateStatus(ctx, *status); err != nil {
+		return err
+	}
+	status.Revision++
 
 	return nil
 }
 
+// deferExecution marks the execution as deferred and waits until the rate limit resets.
+func (command *RunnerCommand) deferExecution(ctx context.Context, execution agent.Execution, status *agent.ExecutionStatus, cause error, retryAt time.Time) error {
+	message := cause.Error()
+	status.State = agent.ExecutionDeferred
+	status.Error = &message
+	status.ResetAt = &retryAt
+	if err := updateStatus(ctx, execution, status); err != nil {
+		return fmt.Errorf("update execution status: %w", err)
+	}
+
+	slog.Info("Execution deferred until the rate limit resets.", slog.String("executionID", string(command.ExecutionID)), slog.Time("retryAt", retryAt))
+
+	return sleepContext(ctx, time.Until(retryAt))
+}
+
+// backoffExecution records the failed attempt and waits before the next retry.
+func (command *RunnerCommand) backoffExecution(ctx context.Context, execution agent.Execution, status *agent.ExecutionStatus, cause error, delay time.Duration) error {
+	message := cause.Error()
+	status.Error = &message
+	if err := updateStatus(ctx, execution, status); err != nil {
+		return fmt.Errorf("update execution status: %w", err)
+	}
+
+	slog.Warn("Execution attempt failed, retrying.",
+		slog.String("executionID", string(command.ExecutionID)),
+		slog.Int("attempt", status.Attempts),
+		slog.Duration("delay", delay),
+		slog.String("error", message))
+
+	return sleepContext(ctx, delay)
+}
+
+func newExecutionAttempt(number int, startedAt time.Time, result agent.RuntimeResult, err error) agent.ExecutionAttempt {
+	attempt := agent.ExecutionAttempt{
+		Number:         number,
+		StartedAt:      startedAt,
+		FinishedAt:     time.Now(),
+		ConversationID: result.ConversationID,
+	}
+
+	if err != nil {
+		attempt.Error = err.Error()
+		attempt.Class = agent.ClassifyRuntimeError(err)
+
+		var runtimeErr *agent.RuntimeExecutionError
+		if errors.As(err, &runtimeErr) {
+			attempt.ExitCode = runtimeErr.ExitCode
+		}
+	}
+
+	return attempt
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// resolveStructuredOutput extracts and validates the JSON value from the runtime response.
+// When validation fails and the runtime can resume the conversation, the agent gets one repair turn.
+func (command *RunnerCommand) resolveStructuredOutput(ctx context.Context, execution agent.Execution, runtime agent.Runtime, input agent.ExecutionInput, agentConfig agent.Config, outputSchema *agent.OutputSchema, capabilities agent.RuntimeCapabilities, result agent.RuntimeResult) (agent.RuntimeResult, json.RawMessage, error) {
+	structured, err := extractStructuredOutput(outputSchema, result.Response)
+	if err == nil {
+		return result, structured, nil
+	}
+
+	if !capabilities.Resume || result.ConversationID == "" {
+		return result, nil, err
+	}
+
+	slog.Info("Structured output invalid, requesting repair.", slog.String("executionID", string(command.ExecutionID)), slog.String("error", err.Error()))
+
+	conversationID := result.ConversationID
+	repairInput := input
+	repairInput.ConversationID = &conversationID
+	repairInput.ForkConversation = false
+	repairInput.Prompt = agent.StructuredOutputRepairPrompt(input.OutputSchema, err)
+	repairInput.Attachments = nil
+
+	instance, err := runtime.Execute(ctx, command.ExecutionID, repairInput, agentConfig)
+	if err != nil {
+		return result, nil, fmt.Errorf("execute repair turn: %w", err)
+	}
+
+	recorded := command.recordRuntimeEvents(ctx, execution, instance.Events())
+
+	repairResult, err := instance.Wait(ctx)
+	<-recorded
+	if err != nil {
+		return result, nil, fmt.Errorf("wait for repair turn: %w", err)
+	}
+
+	if result.Usage != nil {
+		usage := *result.Usage
+		if repairResult.Usage != nil {
+			usage = usage.Add(*repairResult.Usage)
+		}
+		repairResult.Usage = &usage
+	}
+
+	if repairResult.ConversationID == "" {
+		repairResult.ConversationID = conversationID
+	}
+
+	structured, err = extractStructuredOutput(outputSchema, repairResult.Response)
+	if err != nil {
+		return repairResult, nil, err
+	}
+
+	return repairResult, structured, nil
+}
+
+func extractStructuredOutput(outputSchema *agent.OutputSchema, response string) (json.RawMessage, error) {
+	structured, err := agent.ExtractStructuredOutput(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := outputSchema.Validate(structured); err != nil {
+		return nil, err
+	}
+
+	return structured, nil
+}
+
 func (command *RunnerCommand) finishExecutionWithError(ctx context.Context, execution agent.Execution, status agent.ExecutionStatus, err error) error {
 	now := time.Now()
 	status.State = agent.ExecutionFailed
@@ -124,24 +600,36 @@ func (command *RunnerCommand) finishExecutionWithError(ctx context.Context, exec
 	var runtimeErr *agent.RuntimeExecutionError
 	if errors.As(err, &runtimeErr) {
 		status.ExitCode = runtimeErr.ExitCode
+		status.ResetAt = runtimeErr.RetryAt
 	}
 
-	if updateErr := execution.UpdateStatus(ctx, status); updateErr != nil {
+	if updateErr := updateStatus(ctx, execution, &status); updateErr != nil {
 		return fmt.Errorf("update execution status: %w", updateErr)
 	}
 
 	return nil
 }
 
-func (command *RunnerCommand) drainRuntimeEvents(ctx context.Context, events <-chan agent.RuntimeEvent) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case _, ok := <-events:
-			if !ok {
-				return
+// recordRuntimeEvents appends the runtime events to the execution event log until the channel closes.
+// The returned channel is closed once every event has been recorded.
+func (command *RunnerCommand) recordRuntimeEvents(ctx context.Context, execution agent.Execution, events <-chan agent.RuntimeEvent) <-chan struct{} {
+	recorded := make(chan struct{})
+
+	go func() {
+		defer close(recorded)
+
+		for event := range events {
+			envelope, err := agent.NewRuntimeEventEnvelope(event)
+			if err != nil {
+				slog.Warn("Failed to encode runtime event.", slog.String("eventKind", string(event.Kind())), slog.String("error", err.Error()))
+				continue
+			}
+
+			if err := execution.AppendEvent(ctx, envelope); err != nil {
+				slog.Warn("Failed to record runtime event.", slog.String("eventKind", string(event.Kind())), slog.String("error", err.Error()))
 			}
 		}
-	}
+	}()
+
+	return recorded
 }
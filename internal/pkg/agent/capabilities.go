@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RuntimeCapabilities describes which execution features a runtime supports.
+type RuntimeCapabilities struct {
+	// Resume reports whether the runtime can continue a conversation by its identifier.
+	Resume bool `json:"resume"`
+
+	// Fork reports whether the runtime can continue a conversation in a new conversation that branches off it.
+	Fork bool `json:"fork"`
+
+	// AttachmentMimeTypes lists the attachment MIME types the runtime accepts.
+	// Entries may use a wildcard subtype, for example "image/*".
+	AttachmentMimeTypes []string `json:"attachmentMimeTypes"`
+
+	// WebSearchToggle reports whether the runtime honors RuntimeFeatures.EnableWebSearch.
+	WebSearchToggle bool `json:"webSearchToggle"`
+
+	// NetworkAccessToggle reports whether the runtime honors RuntimeFeatures.EnableNetworkAccess.
+	NetworkAccessToggle bool `json:"networkAccessToggle"`
+
+	// ModelOverride reports whether the runtime honors ExecutionInput.Model.
+	ModelOverride bool `json:"modelOverride"`
+
+	// StructuredOutput reports whether the runtime natively constrains its output to a JSON schema.
+	StructuredOutput bool `json:"structuredOutput"`
+
+	// SystemPrompt reports whether the runtime can extend its system prompt.
+	SystemPrompt bool `json:"systemPrompt"`
+}
+
+// SupportsAttachment reports whether the runtime accepts attachments of the given MIME type.
+func (capabilities RuntimeCapabilities) SupportsAttachment(mimeType string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(mimeType))
+
+	for _, pattern := range capabilities.AttachmentMimeTypes {
+		matched, err := path.Match(strings.ToLower(pattern), normalized)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check verifies that the execution input and runtime features only need supported capabilities.
+// Returns ErrRuntimeCapabilityUnsupported naming the first unsupported capability.
+func (capabilities RuntimeCapabilities) Check(input ExecutionInput, features RuntimeFeatures) error {
+	if input.ConversationID != nil && !capabilities.Resume {
+		return fmt.Errorf("%w: resume", ErrRuntimeCapabilityUnsupported)
+	}
+
+	if input.ForkConversation && !capabilities.Fork {
+		return fmt.Errorf("%w: fork", ErrRuntimeCapabilityUnsupported)
+	}
+
+	if input.Model != nil && !capabilities.ModelOverride {
+		return fmt.Errorf("%w: model override", ErrRuntimeCapabilityUnsupported)
+	}
+
+	for _, attachment := range input.Attachments {
+		if !capabilities.SupportsAttachment(attachment.MimeType) {
+			return fmt.Errorf("%w: attachment %s", ErrRuntimeCapabilityUnsupported, attachment.MimeType)
+		}
+	}
+
+	if strings.TrimSpace(input.SystemPrompt) != "" && !capabilities.SystemPrompt {
+		return fmt.Errorf("%w: system prompt", ErrRuntimeCapabilityUnsupported)
+	}
+
+	if features.EnableWebSearch != nil && !capabilities.WebSearchToggle {
+		return fmt.Errorf("%w: web search toggle", ErrRuntimeCapabilityUnsupported)
+	}
+
+	if features.EnableNetworkAccess != nil && !capabilities.NetworkAccessToggle {
+		return fmt.Errorf("%w: network access toggle", ErrRuntimeCapabilityUnsupported)
+	}
+
+	return nil
+}
+
+var (
+	// ErrRuntimeCapabilityUnsupported indicates the execution needs a capability the runtime does not support.
+	ErrRuntimeCapabilityUnsupported = errors.New("runtime capability unsupported")
+)
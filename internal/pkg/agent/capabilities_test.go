@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeCapabilitiesSupportsAttachment(t *testing.T) {
+	capabilities := RuntimeCapabilities{AttachmentMimeTypes: []string{"image/*", "text/plain"}}
+
+	assert.True(t, capabilities.SupportsAttachment("image/png"))
+	assert.True(t, capabilities.SupportsAttachment(" Image/JPEG "))
+	assert.True(t, capabilities.SupportsAttachment("text/plain"))
+	assert.False(t, capabilities.SupportsAttachment("text/markdown"))
+	assert.False(t, RuntimeCapabilities{}.SupportsAttachment("image/png"))
+}
+
+func TestRuntimeCapabilitiesCheck(t *testing.T) {
+	conversationID := ConversationID("conv-1")
+	model := "fast"
+
+	tests := []struct {
+		name         string
+		capabilities RuntimeCapabilities
+		input        ExecutionInput
+		features     RuntimeFeatures
+		valid        bool
+	}{
+		{
+			name:  "plain prompt",
+			input: ExecutionInput{Prompt: "hi"},
+			valid: true,
+		},
+		{
+			name:  "resume unsupported",
+			input: ExecutionInput{ConversationID: &conversationID},
+		},
+		{
+			name:         "resume supported",
+			capabilities: RuntimeCapabilities{Resume: true},
+			input:        ExecutionInput{ConversationID: &conversationID},
+			valid:        true,
+		},
+		{
+			name:         "fork unsupported",
+			capabilities: RuntimeCapabilities{Resume: true},
+			input:        ExecutionInput{ConversationID: &conversationID, ForkConversation: true},
+		},
+		{
+			name:         "fork supported",
+			capabilities: RuntimeCapabilities{Resume: true, Fork: true},
+			input:        ExecutionInput{ConversationID: &conversationID, ForkConversation: true},
+			valid:        true,
+		},
+		{
+			name:  "model override unsupported",
+			input: ExecutionInput{Model: &model},
+		},
+		{
+			name:         "attachment unsupported",
+			capabilities: RuntimeCapabilities{AttachmentMimeTypes: []string{"image/*"}},
+			input:        ExecutionInput{Attachments: []ExecutionInputAttachment{{MimeType: "application/pdf", Path: "/tmp/a.pdf"}}},
+		},
+		{
+			name:         "attachment supported",
+			capabilities: RuntimeCapabilities{AttachmentMimeTypes: []string{"image/*"}},
+			input:        ExecutionInput{Attachments: []ExecutionInputAttachment{{MimeType: "image/png", Path: "/tmp/a.png"}}},
+			valid:        true,
+		},
+		{
+			name:  "system prompt unsupported",
+			input: ExecutionInput{SystemPrompt: "You review code."},
+		},
+		{
+			name:     "web search toggle unsupported",
+			features: RuntimeFeatures{EnableWebSearch: utils.ToPointer(true)},
+		},
+		{
+			name:     "network toggle unsupported",
+			features: RuntimeFeatures{EnableNetworkAccess: utils.ToPointer(false)},
+		},
+		{
+			name:         "toggles supported",
+			capabilities: RuntimeCapabilities{WebSearchToggle: true, NetworkAccessToggle: true},
+			features:     RuntimeFeatures{EnableWebSearch: utils.ToPointer(true), EnableNetworkAccess: utils.ToPointer(false)},
+			valid:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.capabilities.Check(tt.input, tt.features)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrRuntimeCapabilityUnsupported)
+		})
+	}
+}
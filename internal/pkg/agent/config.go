@@ -3,14 +3,437 @@ package agent
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/process"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/version"
 )
 
 type Config struct {
-	Runtime struct {
-		Kind    RuntimeKind     `json:"kind"`
-		Config  RuntimeConfig   `json:"config"`
-		Feature RuntimeFeatures `json:"feature,omitempty"`
-	} `json:"runtime"`
+	// Instructions are agent-level system instructions added to every execution, for example a reviewer persona.
+	Instructions string `json:"instructions,omitempty"`
+
+	// Models restricts and names the models executions of this agent may use.
+	Models ConfigModels `json:"models,omitempty"`
+
+	// Environment controls the environment and home directory of the runtime CLI process.
+	Environment ConfigEnvironment `json:"environment,omitempty"`
+
+	// RateLimit controls how the runner handles rate-limited executions.
+	RateLimit ConfigRateLimit `json:"rateLimit,omitempty"`
+
+	// Retry controls how the runner retries failed attempts of an execution.
+	Retry ConfigRetry `json:"retry,omitempty"`
+
+	// Fallback lists agents that take over, in order, when an execution fails with a rate limit, auth error, crash or timeout.
+	Fallback []AgentID `json:"fallback,omitempty"`
+
+	Runtime ConfigRuntime `json:"runtime"`
+}
+
+// ConfigRuntime selects and configures the runtime executing an agent.
+type ConfigRuntime struct {
+	Kind    RuntimeKind     `json:"kind"`
+	Config  RuntimeConfig   `json:"config"`
+	Feature RuntimeFeatures `json:"feature,omitempty"`
+
+	// Version optionally pins the runtime CLI version with a constraint such as ">=2.0.0, <2.1.0".
+	// It replaces the supported range declared by the runtime.
+	Version string `json:"version,omitempty"`
+}
+
+// ConfigModels declares the models an agent accepts and short aliases for them.
+type ConfigModels struct {
+	// Allowed lists the model identifiers accepted for executions. Empty allows any model.
+	Allowed []string `json:"allowed,omitempty"`
+
+	// Aliases maps short names to model identifiers, for example fast to haiku.
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// Validate checks that aliases are well-formed and only point at allowed models.
+func (models ConfigModels) Validate() error {
+	for _, model := range models.Allowed {
+		if strings.TrimSpace(model) == "" {
+			return fmt.Errorf("%w: empty allowed model", ErrAgentConfigInvalid)
+		}
+	}
+
+	for alias, model := range models.Aliases {
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(model) == "" {
+			return fmt.Errorf("%w: empty model alias", ErrAgentConfigInvalid)
+		}
+
+		if len(models.Allowed) > 0 && !slices.Contains(models.Allowed, model) {
+			return fmt.Errorf("%w: model alias %s points to %s which is not allowed", ErrAgentConfigInvalid, alias, model)
+		}
+	}
+
+	return nil
+}
+
+// Resolve expands an alias and checks the model against the allowed list.
+// Returns ErrExecutionModelNotAllowed when the resolved model is not allowed.
+func (models ConfigModels) Resolve(model string) (string, error) {
+	resolved := strings.TrimSpace(model)
+	if target, ok := models.Aliases[resolved]; ok {
+		resolved = target
+	}
+
+	if len(models.Allowed) > 0 && !slices.Contains(models.Allowed, resolved) {
+		return "", fmt.Errorf("%w: %s (choose one of %s)", ErrExecutionModelNotAllowed, model, strings.Join(models.Choices(), ", "))
+	}
+
+	return resolved, nil
+}
+
+// Choices returns the sorted model names and aliases accepted by Resolve.
+// Returns nil when any model is accepted.
+func (models ConfigModels) Choices() []string {
+	if len(models.Allowed) == 0 {
+		return nil
+	}
+
+	choices := slices.Concat(models.Allowed, slices.Collect(maps.Keys(models.Aliases)))
+	slices.Sort(choices)
+
+	return slices.Compact(choices)
+}
+
+// ConfigEnvironment controls the environment of the runtime CLI process.
+type ConfigEnvironment struct {
+	// Variables are set on top of the inherited environment.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// Allow lists name patterns inherited from the runner environment, for example "LC_*". Empty inherits everything.
+	Allow []string `json:"allow,omitempty"`
+
+	// Deny lists name patterns removed from the inherited environment.
+	Deny []string `json:"deny,omitempty"`
+
+	// Home is a dedicated CLI home directory for the agent, created on first use.
+	// Each runtime maps it onto its own variable, such as CLAUDE_CONFIG_DIR or CODEX_HOME.
+	Home string `json:"home,omitempty"`
+}
+
+// Validate checks that variable names and name patterns are well-formed.
+func (environment ConfigEnvironment) Validate() error {
+	for name := range environment.Variables {
+		if strings.TrimSpace(name) == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("%w: environment variable name %q", ErrAgentConfigInvalid, name)
+		}
+	}
+
+	for _, pattern := range slices.Concat(environment.Allow, environment.Deny) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: environment pattern %q: %w", ErrAgentConfigInvalid, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Resolve builds the runtime process environment from the runner environment.
+// The home directory is created when missing and exported as homeVariable.
+func (environment ConfigEnvironment) Resolve(environ []string, homeVariable string) ([]string, error) {
+	resolved := process.FilterEnvironment(environ, environment.Allow, environment.Deny)
+
+	variables := maps.Clone(environment.Variables)
+	if strings.TrimSpace(environment.Home) != "" {
+		expanded, err := homedir.Expand(environment.Home)
+		if err != nil {
+			return nil, fmt.Errorf("expand agent home: %w", err)
+		}
+
+		home, err := filepath.Abs(expanded)
+		if err != nil {
+			return nil, fmt.Errorf("resolve agent home: %w", err)
+		}
+
+		if err := os.MkdirAll(home, 0700); err != nil {
+			return nil, fmt.Errorf("create agent home: %w", err)
+		}
+
+		if variables == nil {
+			variables = map[string]string{}
+		}
+		variables[homeVariable] = home
+	}
+
+	return process.MergeEnvironment(resolved, variables), nil
+}
+
+// ConfigRateLimit is the opt-in policy for retrying executions after a rate limit resets.
+type ConfigRateLimit struct {
+	// Retry makes the runner defer a rate-limited execution and retry it after the reset.
+	Retry bool `json:"retry,omitempty"`
+
+	// MaxWait is the longest the runner waits for a reset. Zero waits for any reset time.
+	MaxWait utils.Duration `json:"maxWait,omitempty"`
+
+	// Delay is the wait used when the runtime did not report a reset time. Defaults to 15 minutes.
+	Delay utils.Duration `json:"delay,omitempty"`
+
+	// MaxRetries limits the deferred retries of a single execution. Defaults to 3.
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
+const (
+	defaultRateLimitDelay      = 15 * time.Minute
+	defaultRateLimitMaxRetries = 3
+)
+
+// Validate checks that the rate limit durations and retry count are not negative.
+func (policy ConfigRateLimit) Validate() error {
+	if policy.MaxWait < 0 || policy.Delay < 0 || policy.MaxRetries < 0 {
+		return fmt.Errorf("%w: rate limit values must not be negative", ErrAgentConfigInvalid)
+	}
+
+	return nil
+}
+
+// RetryTime reports when a failed execution should be retried under the policy.
+// It returns false when the policy is off, the error is not a rate limit, the retries are used up or the wait is too long.
+func (policy ConfigRateLimit) RetryTime(err error, retries int, now time.Time) (time.Time, bool) {
+	if !policy.Retry {
+		return time.Time{}, false
+	}
+
+	var runtimeErr *RuntimeExecutionError
+	if !errors.As(err, &runtimeErr) || runtimeErr.Class != RuntimeErrorRateLimited {
+		return time.Time{}, false
+	}
+
+	maxRetries := policy.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultRateLimitMaxRetries
+	}
+	if retries >= maxRetries {
+		return time.Time{}, false
+	}
+
+	retryAt := now.Add(defaultRateLimitDelay)
+	if policy.Delay > 0 {
+		retryAt = now.Add(time.Duration(policy.Delay))
+	}
+	if runtimeErr.RetryAt != nil {
+		retryAt = *runtimeErr.RetryAt
+	}
+
+	if retryAt.Before(now) {
+		retryAt = now
+	}
+
+	if policy.MaxWait > 0 && retryAt.Sub(now) > time.Duration(policy.MaxWait) {
+		return time.Time{}, false
+	}
+
+	return retryAt, true
+}
+
+// ConfigRetry is the policy for retrying failed execution attempts in the runner.
+// An attempt is retried when the runtime crashed, exited with one of ExitCodes or its error matches one of Patterns.
+type ConfigRetry struct {
+	// MaxAttempts is the total number of attempts, including the first one. Zero or one disables retries.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// Backoff is the wait before the first retry. It doubles for every following retry. Defaults to 5 seconds.
+	Backoff utils.Duration `json:"backoff,omitempty"`
+
+	// MaxBackoff caps the wait between retries. Defaults to 1 hour.
+	MaxBackoff utils.Duration `json:"maxBackoff,omitempty"`
+
+	// ExitCodes lists runtime exit codes that make an attempt retryable.
+	ExitCodes []int `json:"exitCodes,omitempty"`
+
+	// Patterns lists regular expressions matched against the runtime error output that make an attempt retryable.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Resume continues the conversation started by the failed attempt instead of starting a new one.
+	Resume bool `json:"resume,omitempty"`
+}
+
+const (
+	defaultRetryBackoff    = 5 * time.Second
+	defaultRetryMaxBackoff = time.Hour
+)
+
+// Validate checks that the retry values are not negative and the patterns compile.
+func (policy ConfigRetry) Validate() error {
+	if policy.MaxAttempts < 0 || policy.Backoff < 0 || policy.MaxBackoff < 0 {
+		return fmt.Errorf("%w: retry values must not be negative", ErrAgentConfigInvalid)
+	}
+
+	for _, pattern := range policy.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("%w: retry pattern %q: %w", ErrAgentConfigInvalid, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// RetryDelay reports how long to wait before retrying an attempt that failed with err.
+// Attempt is the number of attempts made so far. It returns false when the attempts are used up or the error is not retryable.
+func (policy ConfigRetry) RetryDelay(err error, attempt int) (time.Duration, bool) {
+	if attempt >= policy.MaxAttempts || !policy.IsRetryable(err) {
+		return 0, false
+	}
+
+	return policy.delay(attempt), true
+}
+
+// MaxDelay reports the longest wait between two attempts, or zero when the policy does not retry.
+func (policy ConfigRetry) MaxDelay() time.Duration {
+	if policy.MaxAttempts <= 1 {
+		return 0
+	}
+
+	return policy.delay(policy.MaxAttempts - 1)
+}
+
+// delay returns the backoff before the attempt following the given number of attempts.
+func (policy ConfigRetry) delay(attempt int) time.Duration {
+	delay := defaultRetryBackoff
+	if policy.Backoff > 0 {
+		delay = time.Duration(policy.Backoff)
+	}
+
+	maxBackoff := defaultRetryMaxBackoff
+	if policy.MaxBackoff > 0 {
+		maxBackoff = time.Duration(policy.MaxBackoff)
+	}
+
+	// Doubling stops at the cap, so the delay cannot overflow however many attempts were made.
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay
+}
+
+// IsRetryable reports whether an attempt that failed with err may be retried under the policy.
+func (policy ConfigRetry) IsRetryable(err error) bool {
+	if ClassifyRuntimeError(err) == RuntimeErrorCrash {
+		return true
+	}
+
+	var runtimeErr *RuntimeExecutionError
+	if errors.As(err, &runtimeErr) && runtimeErr.ExitCode != nil && slices.Contains(policy.ExitCodes, *runtimeErr.ExitCode) {
+		return true
+	}
+
+	for _, pattern := range policy.Patterns {
+		expression, compileErr := regexp.Compile(pattern)
+		if compileErr == nil && expression.MatchString(err.Error()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks the agent configuration against the runtime registered for its kind.
+// Returns ErrRuntimeNotFound when the runtime kind is not registered.
+// Returns ErrAgentConfigInvalid when the model settings or the runtime configuration are invalid.
+func (config Config) Validate(ctx context.Context, registry RuntimeRegistry) error {
+	if err := config.Models.Validate(); err != nil {
+		return err
+	}
+
+	if err := config.Environment.Validate(); err != nil {
+		return err
+	}
+
+	if err := config.RateLimit.Validate(); err != nil {
+		return err
+	}
+
+	if err := config.Retry.Validate(); err != nil {
+		return err
+	}
+
+	for _, id := range config.Fallback {
+		if err := id.Validate(); err != nil {
+			return fmt.Errorf("%w: fallback agent %q: %w", ErrAgentConfigInvalid, id, err)
+		}
+	}
+
+	if config.Runtime.Version != "" {
+		if _, err := version.ParseConstraint(config.Runtime.Version); err != nil {
+			return fmt.Errorf("%w: runtime version: %w", ErrAgentConfigInvalid, err)
+		}
+	}
+
+	runtime, err := registry.Get(ctx, config.Runtime.Kind)
+	if err != nil {
+		return fmt.Errorf("get runtime %s: %w", config.Runtime.Kind, err)
+	}
+
+	if err := runtime.ValidateConfig(ctx, config.Runtime.Config); err != nil {
+		return fmt.Errorf("%w: %w", ErrAgentConfigInvalid, err)
+	}
+
+	return nil
+}
+
+// CheckCapabilities verifies that the agent runtime supports everything the execution input and configured features need.
+// Returns ErrRuntimeCapabilityUnsupported when a required capability is missing.
+func (config Config) CheckCapabilities(ctx context.Context, registry RuntimeRegistry, input ExecutionInput) error {
+	runtime, err := registry.Get(ctx, config.Runtime.Kind)
+	if err != nil {
+		return fmt.Errorf("get runtime %s: %w", config.Runtime.Kind, err)
+	}
+
+	input.SystemPrompt = config.ResolveSystemPrompt(input)
+
+	capabilities, err := runtime.GetCapabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("get runtime %s capabilities: %w", config.Runtime.Kind, err)
+	}
+
+	if err := capabilities.Check(input, config.Runtime.Feature); err != nil {
+		return fmt.Errorf("runtime %s: %w", config.Runtime.Kind, err)
+	}
+
+	return nil
+}
+
+// CheckRuntimeVersion verifies the detected runtime version against the version pin of the agent.
+// See RuntimeInfo.CheckVersion for the errors returned.
+func (config Config) CheckRuntimeVersion(info RuntimeInfo) error {
+	if err := info.CheckVersion(config.Runtime.Version); err != nil {
+		return fmt.Errorf("%s: %w", config.Runtime.Kind, err)
+	}
+
+	return nil
+}
+
+// ResolveSystemPrompt combines the agent instructions with the per-call system prompt.
+// Empty parts are skipped; the result is empty when neither is set.
+func (config Config) ResolveSystemPrompt(input ExecutionInput) string {
+	var parts []string
+	for _, part := range []string{config.Instructions, input.SystemPrompt} {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
 }
 
 type ConfigRepository interface {
@@ -18,6 +441,7 @@ type ConfigRepository interface {
 	Get(ctx context.Context, id AgentID) (Config, error)
 	Update(ctx context.Context, id AgentID, config Config) error
 	List(ctx context.Context) ([]AgentID, error)
+	Delete(ctx context.Context, id AgentID) error
 }
 
 var (
@@ -26,4 +450,13 @@ var (
 
 	// ErrAgentIDInvalid indicates the agent identifier is missing or invalid.
 	ErrAgentIDInvalid = errors.New("agent id invalid")
+
+	// ErrAgentConfigInvalid indicates the agent configuration is rejected by its runtime.
+	ErrAgentConfigInvalid = errors.New("agent config invalid")
+
+	// ErrRuntimeVersionIncompatible indicates the runtime version does not satisfy the agent's version pin.
+	ErrRuntimeVersionIncompatible = errors.New("runtime version incompatible")
+
+	// ErrRuntimeVersionUnsupported indicates the runtime version is outside the range supported by the runtime.
+	ErrRuntimeVersionUnsupported = errors.New("runtime version unsupported")
 )
@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigResolveSystemPrompt(t *testing.T) {
+	config := Config{Instructions: " You are a security reviewer. "}
+
+	assert.Equal(t, "You are a security reviewer.", config.ResolveSystemPrompt(ExecutionInput{}))
+	assert.Equal(t,
+		"You are a security reviewer.\n\nFocus on authentication.",
+		config.ResolveSystemPrompt(ExecutionInput{SystemPrompt: "Focus on authentication."}),
+	)
+	assert.Equal(t, "Focus on authentication.", Config{}.ResolveSystemPrompt(ExecutionInput{SystemPrompt: "Focus on authentication."}))
+	assert.Empty(t, Config{}.ResolveSystemPrompt(ExecutionInput{SystemPrompt: "  "}))
+}
+
+func TestConfigModels(t *testing.T) {
+	models := ConfigModels{
+		Allowed: []string{"haiku", "opus"},
+		Aliases: map[string]string{"fast": "haiku", "deep": "opus"},
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, models.Validate())
+		require.NoError(t, ConfigModels{Aliases: map[string]string{"fast": "anything"}}.Validate())
+
+		invalid := ConfigModels{Allowed: []string{"haiku"}, Aliases: map[string]string{"deep": "opus"}}
+		require.ErrorIs(t, invalid.Validate(), ErrAgentConfigInvalid)
+	})
+
+	t.Run("resolve alias", func(t *testing.T) {
+		model, err := models.Resolve("fast")
+		require.NoError(t, err)
+		assert.Equal(t, "haiku", model)
+	})
+
+	t.Run("resolve allowed model", func(t *testing.T) {
+		model, err := models.Resolve("opus")
+		require.NoError(t, err)
+		assert.Equal(t, "opus", model)
+	})
+
+	t.Run("resolve unknown model", func(t *testing.T) {
+		_, err := models.Resolve("sonet")
+		require.ErrorIs(t, err, ErrExecutionModelNotAllowed)
+	})
+
+	t.Run("resolve without restrictions", func(t *testing.T) {
+		model, err := ConfigModels{}.Resolve("sonnet")
+		require.NoError(t, err)
+		assert.Equal(t, "sonnet", model)
+	})
+
+	t.Run("choices", func(t *testing.T) {
+		assert.Equal(t, []string{"deep", "fast", "haiku", "opus"}, models.Choices())
+		assert.Nil(t, ConfigModels{Aliases: map[string]string{"fast": "haiku"}}.Choices())
+	})
+}
+
+func TestConfigCheckRuntimeVersion(t *testing.T) {
+	info := RuntimeInfo{Version: "2.0.14", SupportedVersions: ">=1.0.0, <2.0.0"}
+
+	require.ErrorIs(t, Config{}.CheckRuntimeVersion(info), ErrRuntimeVersionUnsupported)
+	require.NoError(t, Config{Runtime: ConfigRuntime{Version: ">=2.0.0, <2.1.0"}}.CheckRuntimeVersion(info))
+
+	err := Config{Runtime: ConfigRuntime{Kind: "claude", Version: "2.0.13"}}.CheckRuntimeVersion(info)
+	require.ErrorIs(t, err, ErrRuntimeVersionIncompatible)
+	assert.Contains(t, err.Error(), "claude")
+}
+
+func TestConfigValidateFallback(t *testing.T) {
+	config := Config{Runtime: ConfigRuntime{Kind: "codex"}, Fallback: []AgentID{"gemini", ""}}
+	err := config.Validate(context.Background(), nil)
+	require.ErrorIs(t, err, ErrAgentConfigInvalid)
+}
+
+func TestConfigValidateRuntimeVersion(t *testing.T) {
+	config := Config{Runtime: ConfigRuntime{Kind: "codex", Version: "~0.44"}}
+	err := config.Validate(context.Background(), nil)
+	require.ErrorIs(t, err, ErrAgentConfigInvalid)
+}
+
+func TestConfigEnvironmentResolve(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "claude-work")
+	environment := ConfigEnvironment{
+		Variables: map[string]string{"ANTHROPIC_LOG": "debug"},
+		Allow:     []string{"PATH", "ANTHROPIC_*"},
+		Deny:      []string{"ANTHROPIC_API_KEY"},
+		Home:      home,
+	}
+	require.NoError(t, environment.Validate())
+
+	resolved, err := environment.Resolve([]string{"PATH=/bin", "ANTHROPIC_API_KEY=secret", "ANTHROPIC_LOG=info", "AWS_SECRET=x"}, "CLAUDE_CONFIG_DIR")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PATH=/bin", "ANTHROPIC_LOG=debug", "CLAUDE_CONFIG_DIR=" + home}, resolved)
+	assert.DirExists(t, home)
+}
+
+func TestConfigEnvironmentValidate(t *testing.T) {
+	require.ErrorIs(t, ConfigEnvironment{Variables: map[string]string{"A=B": "x"}}.Validate(), ErrAgentConfigInvalid)
+	require.ErrorIs(t, ConfigEnvironment{Deny: []string{"AWS_["}}.Validate(), ErrAgentConfigInvalid)
+}
+
+func TestConfigRateLimitRetryTime(t *testing.T) {
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	resetAt := now.Add(2 * time.Hour)
+	rateLimited := &RuntimeExecutionError{Message: "usage limit reached", Class: RuntimeErrorRateLimited, RetryAt: &resetAt}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, ok := ConfigRateLimit{}.RetryTime(rateLimited, 0, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("reported reset time", func(t *testing.T) {
+		retryAt, ok := ConfigRateLimit{Retry: true}.RetryTime(rateLimited, 0, now)
+		require.True(t, ok)
+		assert.Equal(t, resetAt, retryAt)
+	})
+
+	t.Run("default delay", func(t *testing.T) {
+		retryAt, ok := ConfigRateLimit{Retry: true}.RetryTime(&RuntimeExecutionError{Class: RuntimeErrorRateLimited}, 0, now)
+		require.True(t, ok)
+		assert.Equal(t, now.Add(15*time.Minute), retryAt)
+	})
+
+	t.Run("not rate limited", func(t *testing.T) {
+		_, ok := ConfigRateLimit{Retry: true}.RetryTime(errors.New("exit status 1"), 0, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("retries used up", func(t *testing.T) {
+		_, ok := ConfigRateLimit{Retry: true, MaxRetries: 1}.RetryTime(rateLimited, 1, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("reset beyond max wait", func(t *testing.T) {
+		_, ok := ConfigRateLimit{Retry: true, MaxWait: utils.Duration(time.Hour)}.RetryTime(rateLimited, 0, now)
+		assert.False(t, ok)
+	})
+}
+
+func TestConfigRetryRetryDelay(t *testing.T) {
+	exitCode := 2
+	flaky := &RuntimeExecutionError{Message: "ECONNRESET while streaming", ExitCode: &exitCode}
+
+	policy := ConfigRetry{
+		MaxAttempts: 4,
+		Backoff:     utils.Duration(time.Second),
+		MaxBackoff:  utils.Duration(3 * time.Second),
+		Patterns:    []string{"ECONNRESET"},
+	}
+	require.NoError(t, policy.Validate())
+
+	delay, ok := policy.RetryDelay(flaky, 1)
+	require.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	delay, ok = policy.RetryDelay(flaky, 2)
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	delay, ok = policy.RetryDelay(flaky, 3)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	_, ok = policy.RetryDelay(flaky, 4)
+	assert.False(t, ok, "attempts used up")
+
+	_, ok = policy.RetryDelay(&RuntimeExecutionError{Message: "invalid prompt", ExitCode: &exitCode}, 1)
+	assert.False(t, ok, "not retryable")
+
+	delay, ok = ConfigRetry{MaxAttempts: 200, Patterns: []string{"ECONNRESET"}}.RetryDelay(flaky, 100)
+	require.True(t, ok)
+	assert.Equal(t, time.Hour, delay, "uncapped policy is clamped instead of overflowing")
+
+	assert.Equal(t, 3*time.Second, policy.MaxDelay())
+	assert.Zero(t, ConfigRetry{MaxAttempts: 1}.MaxDelay(), "single attempt never waits")
+
+	assert.True(t, ConfigRetry{ExitCodes: []int{2}}.IsRetryable(&RuntimeExecutionError{ExitCode: &exitCode}))
+
+	signaled := -1
+	assert.True(t, ConfigRetry{}.IsRetryable(&RuntimeExecutionError{ExitCode: &signaled}))
+
+	require.ErrorIs(t, ConfigRetry{Patterns: []string{"("}}.Validate(), ErrAgentConfigInvalid)
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"path/filepath"
 	"strings"
@@ -33,6 +34,9 @@ const (
 
 	// ExecutionFailed indicates the execution has finished with an error.
 	ExecutionFailed ExecutionState = "failed"
+
+	// ExecutionDeferred indicates the execution hit a rate limit and the runner waits for the reset to retry it.
+	ExecutionDeferred ExecutionState = "deferred"
 )
 
 // ExecutionInput captures the runtime input required to run an execution.
@@ -47,13 +51,24 @@ type ExecutionInput struct {
 	// Prompt is the user input sent to the agent.
 	Prompt string `json:"prompt"`
 
+	// Model overrides the runtime default model. Agent model aliases are resolved when the execution is created.
 	Model *string `json:"model,omitempty"`
 
 	// ConversationID continues an existing agent conversation when provided.
 	ConversationID *ConversationID `json:"conversationId,omitempty"`
 
+	// ForkConversation continues ConversationID in a new conversation and leaves the original conversation unchanged.
+	ForkConversation bool `json:"forkConversation,omitempty"`
+
 	// Attachments lists optional files supplied with the prompt.
 	Attachments []ExecutionInputAttachment `json:"attachments,omitempty"`
+
+	// SystemPrompt is added to the agent system prompt for this execution, after the agent instructions.
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+
+	// OutputSchema is an optional JSON Schema the agent response must conform to.
+	// When set, the execution result carries the validated JSON value in Structured.
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
 }
 
 // ExecutionInputAttachment describes a single file attached to the execution input.
@@ -72,10 +87,146 @@ type ExecutionResult struct {
 
 	// Response carries the final agent response text.
 	Response string `json:"response"`
+
+	// Structured carries the JSON value validated against ExecutionInput.OutputSchema.
+	Structured json.RawMessage `json:"structured,omitempty"`
+
+	// AgentID is the agent that produced the response, when the execution recorded it.
+	AgentID AgentID `json:"agentId,omitempty"`
+
+	// SessionID is the session the response was appended to, when the execution belongs to one.
+	SessionID *SessionID `json:"sessionId,omitempty"`
+
+	// Usage reports the tokens consumed by the execution, when the runtime reported them.
+	Usage *RuntimeUsage `json:"usage,omitempty"`
+}
+
+// ExecutionMetadata describes who created an execution and how it relates to other executions.
+type ExecutionMetadata struct {
+	// AgentID is the agent the execution runs on.
+	AgentID AgentID `json:"agentId,omitempty"`
+
+	// FallbackOf is the failed execution this execution takes over from.
+	FallbackOf *ExecutionID `json:"fallbackOf,omitempty"`
+
+	// SessionID is the session the execution answers a turn of.
+	SessionID *SessionID `json:"sessionId,omitempty"`
+
+	// ParentID is the execution whose conversation state this execution continues.
+	ParentID *ExecutionID `json:"parentId,omitempty"`
+
+	// Fork reports that the execution branches off its parent instead of continuing the latest turn.
+	Fork bool `json:"fork,omitempty"`
+
+	// Instruction is the user instruction when the prompt wraps it with replayed turns, for example after a handoff.
+	// Transcripts record it instead of the whole prompt.
+	Instruction string `json:"instruction,omitempty"`
+
+	// Labels are user-defined key-value pairs used to find the execution later.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Parent returns the execution this execution descends from in a conversation tree:
+// the turn it continues or forks, or the failed execution it takes over as a fallback.
+// Returns nil for the first turn of a conversation.
+func (metadata ExecutionMetadata) Parent() *ExecutionID {
+	if metadata.ParentID != nil {
+		return metadata.ParentID
+	}
+
+	return metadata.FallbackOf
+}
+
+// Prompt returns the instruction the user gave for the execution: the recorded instruction, or the input prompt.
+func (metadata ExecutionMetadata) Prompt(input ExecutionInput) string {
+	if metadata.Instruction != "" {
+		return metadata.Instruction
+	}
+
+	return input.Prompt
+}
+
+// ExecutionOption sets metadata on a new execution.
+type ExecutionOption func(metadata *ExecutionMetadata)
+
+// WithAgentID records the agent the execution runs on.
+func WithAgentID(id AgentID) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		metadata.AgentID = id
+	}
+}
+
+// WithFallbackOf links the execution to the failed execution it takes over from.
+func WithFallbackOf(id ExecutionID) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		metadata.FallbackOf = &id
+	}
+}
+
+// WithSessionID records the session the execution answers a turn of.
+func WithSessionID(id SessionID) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		metadata.SessionID = &id
+	}
+}
+
+// WithParentID links the execution to the execution whose conversation state it continues.
+// An empty identifier leaves the execution without a parent.
+func WithParentID(id ExecutionID) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		if id != EmptyExecutionID {
+			metadata.ParentID = &id
+		}
+	}
+}
+
+// WithFork links the execution to the execution it branches the conversation off.
+func WithFork(id ExecutionID) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		metadata.ParentID = &id
+		metadata.Fork = true
+	}
+}
+
+// WithInstruction records the user instruction wrapped by a prompt that replays earlier turns.
+func WithInstruction(instruction string) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		metadata.Instruction = instruction
+	}
+}
+
+// WithLabels records user-defined labels on the execution.
+func WithLabels(labels map[string]string) ExecutionOption {
+	return func(metadata *ExecutionMetadata) {
+		if len(labels) == 0 {
+			return
+		}
+
+		if metadata.Labels == nil {
+			metadata.Labels = make(map[string]string, len(labels))
+		}
+		for key, value := range labels {
+			metadata.Labels[key] = value
+		}
+	}
+}
+
+// NewExecutionMetadata applies the options to empty metadata.
+func NewExecutionMetadata(options ...ExecutionOption) ExecutionMetadata {
+	var metadata ExecutionMetadata
+	for _, option := range options {
+		option(&metadata)
+	}
+
+	return metadata
 }
 
 // ExecutionStatus tracks lifecycle timestamps and state for an execution.
 type ExecutionStatus struct {
+	// Revision counts the stored changes of the status. The store increments it on every update,
+	// and an update carrying an older revision is rejected.
+	Revision int64 `json:"revision"`
+
 	// CreatedAt is the timestamp when the execution was created.
 	CreatedAt time.Time `json:"createdAt"`
 
@@ -96,14 +247,70 @@ type ExecutionStatus struct {
 
 	// Error carries a runtime error message when the execution fails.
 	Error *string `json:"error,omitempty"`
+
+	// ResetAt is when the rate limit that stopped the execution resets, when the runtime reported it.
+	// While the execution is deferred the runner retries it at this time.
+	ResetAt *time.Time `json:"resetAt,omitempty"`
+
+	// RuntimeVersion is the runtime CLI version detected when the execution started.
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+
+	// FallbackExecutionID is the execution created on the next fallback agent after this execution failed.
+	FallbackExecutionID *ExecutionID `json:"fallbackExecutionId,omitempty"`
+
+	// History records every attempt of the execution, oldest first.
+	History []ExecutionAttempt `json:"history,omitempty"`
+}
+
+// ExecutionAttempt records a single run of the runtime for an execution.
+type ExecutionAttempt struct {
+	// Number is the attempt number, counted across runner invocations.
+	Number int `json:"number"`
+
+	// StartedAt is when the attempt started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// FinishedAt is when the attempt finished.
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// ConversationID is the conversation the attempt ran in, when the runtime reported it.
+	ConversationID ConversationID `json:"conversationId,omitempty"`
+
+	// ExitCode is the runtime process exit code, when available.
+	ExitCode *int `json:"exitCode,omitempty"`
+
+	// Error is the failure message. It is empty when the attempt succeeded.
+	Error string `json:"error,omitempty"`
+
+	// Class categorizes the failure.
+	Class RuntimeErrorClass `json:"class,omitempty"`
 }
 
-// ExecutionQuery describes filters used to locate executions in a repository.
-type ExecutionQuery struct {
+// ExecutionDeleteOptions controls how an execution is deleted.
+type ExecutionDeleteOptions struct {
+	// Force deletes the execution even while it is active.
+	Force bool
 }
 
-// ExecutionFilter applies a filter to an execution query.
-type ExecutionFilter func(query *ExecutionQuery)
+// ExecutionDeleteOption adjusts how an execution is deleted.
+type ExecutionDeleteOption func(options *ExecutionDeleteOptions)
+
+// ForceDelete deletes the execution even while a runner is working on it.
+func ForceDelete() ExecutionDeleteOption {
+	return func(options *ExecutionDeleteOptions) {
+		options.Force = true
+	}
+}
+
+// NewExecutionDeleteOptions applies the options to the default delete options.
+func NewExecutionDeleteOptions(options ...ExecutionDeleteOption) ExecutionDeleteOptions {
+	var deleteOptions ExecutionDeleteOptions
+	for _, option := range options {
+		option(&deleteOptions)
+	}
+
+	return deleteOptions
+}
 
 // ExecutionRepository provides access to execution handles in a store.
 type ExecutionRepository interface {
@@ -115,7 +322,11 @@ type ExecutionRepository interface {
 	// Returns ErrExecutionWorkingDirectoryNotAbsolute when the input working directory is not absolute.
 	// Returns ErrExecutionAttachmentMimeTypeRequired when an attachment MIME type is missing.
 	// Returns ErrExecutionAttachmentPathRequired when an attachment path is missing.
-	Create(ctx context.Context, input ExecutionInput, agentConfig Config) (ExecutionID, error)
+	// Returns ErrExecutionOutputSchemaInvalid when the output schema is not a valid JSON Schema object.
+	// Returns ErrExecutionModelRequired when the model override is empty.
+	// Returns ErrExecutionForkConversationRequired when a fork is requested without a conversation.
+	// Returns ErrExecutionModelNotAllowed when the model is not allowed by the agent config.
+	Create(ctx context.Context, input ExecutionInput, agentConfig Config, options ...ExecutionOption) (ExecutionID, error)
 
 	// Exists reports whether an execution with the given identifier exists.
 	// Returns ErrExecutionIDInvalid when the identifier is missing or malformed.
@@ -126,8 +337,20 @@ type ExecutionRepository interface {
 	// Returns ErrExecutionIDInvalid when the identifier is missing or malformed.
 	Get(ctx context.Context, id ExecutionID) (Execution, error)
 
-	// Find returns execution identifiers matching the provided filters.
+	// Find returns execution identifiers matching the provided filters, in the query sort order.
 	Find(ctx context.Context, filters ...ExecutionFilter) ([]ExecutionID, error)
+
+	// Delete removes the execution with its status, result and events.
+	// Returns ErrExecutionNotFound when the execution does not exist.
+	// Returns ErrExecutionIDInvalid when the identifier is missing or malformed.
+	// Returns ErrExecutionActive when the execution is active and the delete is not forced.
+	Delete(ctx context.Context, id ExecutionID, options ...ExecutionDeleteOption) error
+}
+
+// ExecutionIndexer is implemented by execution repositories that keep an index to answer Find.
+type ExecutionIndexer interface {
+	// RebuildIndex recreates the index from the stored executions.
+	RebuildIndex(ctx context.Context) error
 }
 
 // Execution encapsulates per-execution state accessors and updates.
@@ -141,6 +364,11 @@ type Execution interface {
 	// Returns ErrExecutionAgentConfigNotFound when the agent config snapshot is missing.
 	GetAgentConfig(ctx context.Context) (Config, error)
 
+	// GetMetadata returns the metadata recorded when the execution was created.
+	// Executions created without metadata return empty metadata.
+	// Returns ErrExecutionNotFound when the execution does not exist.
+	GetMetadata(ctx context.Context) (ExecutionMetadata, error)
+
 	// GetResult returns the stored result for the execution.
 	// Returns ErrExecutionNotFound when the execution does not exist.
 	// Returns ErrExecutionNoResult when the execution has no result yet.
@@ -150,7 +378,7 @@ type Execution interface {
 	// Returns ErrExecutionNotFound when the execution does not exist.
 	HasResult(ctx context.Context) (bool, error)
 
-	// SetResult stores the result for the execution.
+	// SetResult stores the result for the execution, marks it succeeded and advances the status revision.
 	// Returns ErrExecutionNotFound when the execution does not exist.
 	SetResult(ctx context.Context, result ExecutionResult) error
 
@@ -158,9 +386,19 @@ type Execution interface {
 	// Returns ErrExecutionNotFound when the execution does not exist.
 	GetStatus(ctx context.Context) (ExecutionStatus, error)
 
-	// UpdateStatus stores the lifecycle status for the execution.
+	// UpdateStatus stores the lifecycle status for the execution with the next revision.
+	// The status must carry the revision of the stored status it was derived from.
 	// Returns ErrExecutionNotFound when the execution does not exist.
+	// Returns ErrExecutionStatusConflict when the stored status changed since it was read.
 	UpdateStatus(ctx context.Context, status ExecutionStatus) error
+
+	// AppendEvent adds a runtime event to the end of the execution event log.
+	// Returns ErrExecutionNotFound when the execution does not exist.
+	AppendEvent(ctx context.Context, envelope RuntimeEventEnvelope) error
+
+	// GetEvents returns the runtime events recorded for the execution, skipping the first offset events.
+	// Returns ErrExecutionNotFound when the execution does not exist.
+	GetEvents(ctx context.Context, offset int) ([]RuntimeEventEnvelope, error)
 }
 
 // NewExecutionID generates a new execution identifier.
@@ -187,6 +425,11 @@ func (state ExecutionState) IsFinished() bool {
 	return state == ExecutionSucceeded || state == ExecutionFailed
 }
 
+// IsActive reports whether a runner is working on an execution in the state, or is about to start on a created one.
+func (state ExecutionState) IsActive() bool {
+	return state == ExecutionCreated || state == ExecutionStarted || state == ExecutionRunning || state == ExecutionDeferred
+}
+
 // Validate checks whether the attachment contains the required metadata.
 func (attachment ExecutionInputAttachment) Validate() error {
 	if strings.TrimSpace(attachment.MimeType) == "" {
@@ -210,6 +453,14 @@ func (input ExecutionInput) Validate() error {
 		return ErrExecutionTimeoutRequired
 	}
 
+	if input.Model != nil && strings.TrimSpace(*input.Model) == "" {
+		return ErrExecutionModelRequired
+	}
+
+	if input.ForkConversation && input.ConversationID == nil {
+		return ErrExecutionForkConversationRequired
+	}
+
 	if input.WorkingDirectory != nil {
 		if strings.TrimSpace(*input.WorkingDirectory) == "" {
 			return ErrExecutionWorkingDirectoryRequired
@@ -231,6 +482,12 @@ func (input ExecutionInput) Validate() error {
 		}
 	}
 
+	if len(input.OutputSchema) > 0 {
+		if _, err := CompileOutputSchema(input.OutputSchema); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -247,6 +504,12 @@ var (
 	// ErrExecutionAgentConfigNotFound indicates the execution exists but has no stored agent config yet.
 	ErrExecutionAgentConfigNotFound = errors.New("execution agent config not found")
 
+	// ErrExecutionActive indicates the execution is created, started, running or deferred and cannot be deleted without force.
+	ErrExecutionActive = errors.New("execution active")
+
+	// ErrExecutionStatusConflict indicates the execution status was updated by someone else since it was read.
+	ErrExecutionStatusConflict = errors.New("execution status conflict")
+
 	// ErrExecutionIDInvalid indicates the execution identifier is missing or malformed.
 	ErrExecutionIDInvalid = errors.New("execution id invalid")
 
@@ -270,4 +533,13 @@ var (
 
 	// ErrExecutionAttachmentPathRequired indicates the attachment path is missing.
 	ErrExecutionAttachmentPathRequired = errors.New("execution attachment path required")
+
+	// ErrExecutionModelRequired indicates the model override is set but empty.
+	ErrExecutionModelRequired = errors.New("execution model required")
+
+	// ErrExecutionModelNotAllowed indicates the model is not in the agent's allowed models.
+	ErrExecutionModelNotAllowed = errors.New("execution model not allowed")
+
+	// ErrExecutionForkConversationRequired indicates a conversation fork was requested without a conversation to fork.
+	ErrExecutionForkConversationRequired = errors.New("execution fork conversation required")
 )
@@ -0,0 +1,390 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExecutionSort orders the executions returned by a query.
+type ExecutionSort string
+
+const (
+	// ExecutionSortID orders executions by identifier. It is the default order.
+	ExecutionSortID ExecutionSort = "id"
+
+	// ExecutionSortCreatedAsc orders executions from the oldest to the newest.
+	ExecutionSortCreatedAsc ExecutionSort = "created-asc"
+
+	// ExecutionSortCreatedDesc orders executions from the newest to the oldest.
+	ExecutionSortCreatedDesc ExecutionSort = "created-desc"
+)
+
+// ExecutionQuery describes filters used to locate executions in a repository.
+type ExecutionQuery struct {
+	// AgentID matches executions run by the agent.
+	AgentID *AgentID
+
+	// RuntimeKind matches executions run on the runtime kind.
+	RuntimeKind *RuntimeKind
+
+	// States matches executions in any of the states.
+	States []ExecutionState
+
+	// CreatedAfter matches executions created at or after the time.
+	CreatedAfter *time.Time
+
+	// CreatedBefore matches executions created before the time.
+	CreatedBefore *time.Time
+
+	// WorkingDirectory matches executions run in the directory.
+	WorkingDirectory *string
+
+	// ConversationID matches executions that continued or produced the conversation.
+	ConversationID *ConversationID
+
+	// Labels matches executions carrying every label. An empty value matches any value of the label.
+	Labels map[string]string
+
+	// Sort is the order of the results. Defaults to ExecutionSortID.
+	Sort ExecutionSort
+
+	// Limit caps the number of results. Zero means no limit.
+	Limit int
+
+	// Cursor is the position of the last execution of the previous page; results start after it.
+	Cursor *ExecutionCursor
+}
+
+// ExecutionCursor is the position of an execution in the query sort order: its creation time and identifier.
+// Results start after the position, so paging carries on when the execution itself was deleted in the meantime.
+type ExecutionCursor struct {
+	// CreatedAt is when the execution was created. It is ignored when sorting by identifier.
+	CreatedAt time.Time
+
+	// ID is the execution identifier.
+	ID ExecutionID
+}
+
+// ParseExecutionCursor reads a cursor in the form returned by ExecutionCursor.String.
+// Returns ErrExecutionCursorInvalid when the value is not a cursor.
+func ParseExecutionCursor(value string) (ExecutionCursor, error) {
+	createdAt, id, ok := strings.Cut(value, "_")
+	if !ok {
+		return ExecutionCursor{}, ErrExecutionCursorInvalid
+	}
+
+	parsedCreatedAt, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return ExecutionCursor{}, fmt.Errorf("%w: %w", ErrExecutionCursorInvalid, err)
+	}
+
+	cursor := ExecutionCursor{CreatedAt: parsedCreatedAt, ID: ExecutionID(id)}
+	if err := cursor.ID.Validate(); err != nil {
+		return ExecutionCursor{}, fmt.Errorf("%w: %w", ErrExecutionCursorInvalid, err)
+	}
+
+	return cursor, nil
+}
+
+// String renders the cursor as the creation time in RFC 3339 and the identifier, joined by an underscore.
+func (cursor ExecutionCursor) String() string {
+	return cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "_" + string(cursor.ID)
+}
+
+// MarshalText renders the cursor as returned by String.
+func (cursor ExecutionCursor) MarshalText() ([]byte, error) {
+	return []byte(cursor.String()), nil
+}
+
+// UnmarshalText reads a cursor as returned by String.
+func (cursor *ExecutionCursor) UnmarshalText(text []byte) error {
+	parsed, err := ParseExecutionCursor(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cursor = parsed
+	return nil
+}
+
+// ExecutionFilter applies a filter to an execution query.
+type ExecutionFilter func(query *ExecutionQuery)
+
+// FilterByAgentID matches executions run by the agent.
+func FilterByAgentID(id AgentID) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.AgentID = &id
+	}
+}
+
+// FilterByRuntimeKind matches executions run on the runtime kind.
+func FilterByRuntimeKind(kind RuntimeKind) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.RuntimeKind = &kind
+	}
+}
+
+// FilterByState matches executions in any of the states.
+func FilterByState(states ...ExecutionState) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.States = append(query.States, states...)
+	}
+}
+
+// FilterCreatedAfter matches executions created at or after the time.
+func FilterCreatedAfter(at time.Time) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.CreatedAfter = &at
+	}
+}
+
+// FilterCreatedBefore matches executions created before the time.
+func FilterCreatedBefore(at time.Time) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.CreatedBefore = &at
+	}
+}
+
+// FilterByWorkingDirectory matches executions run in the directory.
+func FilterByWorkingDirectory(path string) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		path = filepath.Clean(path)
+		query.WorkingDirectory = &path
+	}
+}
+
+// FilterByConversationID matches executions that continued or produced the conversation.
+func FilterByConversationID(id ConversationID) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.ConversationID = &id
+	}
+}
+
+// FilterByLabel matches executions carrying the label. An empty value matches any value of the label.
+func FilterByLabel(key string, value string) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		if query.Labels == nil {
+			query.Labels = make(map[string]string)
+		}
+		query.Labels[key] = value
+	}
+}
+
+// SortExecutions sets the order of the results.
+func SortExecutions(order ExecutionSort) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.Sort = order
+	}
+}
+
+// LimitExecutions caps the number of results.
+func LimitExecutions(limit int) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.Limit = limit
+	}
+}
+
+// ExecutionsAfter starts the results after the cursor, the position of the last execution of the previous page.
+func ExecutionsAfter(cursor ExecutionCursor) ExecutionFilter {
+	return func(query *ExecutionQuery) {
+		query.Cursor = &cursor
+	}
+}
+
+// NewExecutionQuery applies the filters to an empty query.
+func NewExecutionQuery(filters ...ExecutionFilter) ExecutionQuery {
+	var query ExecutionQuery
+	for _, filter := range filters {
+		filter(&query)
+	}
+
+	return query
+}
+
+// Validate checks whether the query sort order and limit are supported.
+func (query ExecutionQuery) Validate() error {
+	switch query.Sort {
+	case "", ExecutionSortID, ExecutionSortCreatedAsc, ExecutionSortCreatedDesc:
+	default:
+		return ErrExecutionSortInvalid
+	}
+
+	if query.Limit < 0 {
+		return ErrExecutionLimitInvalid
+	}
+
+	return nil
+}
+
+// NeedsRecords reports whether the query filters or sorts on stored execution data,
+// as opposed to identifiers alone.
+func (query ExecutionQuery) NeedsRecords() bool {
+	return query.AgentID != nil ||
+		query.RuntimeKind != nil ||
+		len(query.States) > 0 ||
+		query.CreatedAfter != nil ||
+		query.CreatedBefore != nil ||
+		query.WorkingDirectory != nil ||
+		query.ConversationID != nil ||
+		len(query.Labels) > 0 ||
+		query.Sort == ExecutionSortCreatedAsc ||
+		query.Sort == ExecutionSortCreatedDesc
+}
+
+// ExecutionRecord is the stored data of an execution that queries match against.
+type ExecutionRecord struct {
+	// ID is the execution identifier.
+	ID ExecutionID `json:"id"`
+
+	// AgentID is the agent the execution runs on.
+	AgentID AgentID `json:"agentId,omitempty"`
+
+	// RuntimeKind is the runtime kind of the execution agent config.
+	RuntimeKind RuntimeKind `json:"runtimeKind,omitempty"`
+
+	// State is the current execution state.
+	State ExecutionState `json:"state"`
+
+	// CreatedAt is when the execution was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the execution status last changed.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// WorkingDirectory is the directory the execution runs in.
+	WorkingDirectory string `json:"workingDirectory,omitempty"`
+
+	// ConversationIDs lists the conversation the execution continued and the one it produced.
+	ConversationIDs []ConversationID `json:"conversationIds,omitempty"`
+
+	// Labels are the user-defined labels of the execution.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NewExecutionRecord collects the queryable data of an execution. Pass a nil result while the execution has none.
+func NewExecutionRecord(id ExecutionID, input ExecutionInput, agentConfig Config, metadata ExecutionMetadata, status ExecutionStatus, result *ExecutionResult) ExecutionRecord {
+	record := ExecutionRecord{
+		ID:          id,
+		AgentID:     metadata.AgentID,
+		RuntimeKind: agentConfig.Runtime.Kind,
+		State:       status.State,
+		CreatedAt:   status.CreatedAt,
+		UpdatedAt:   status.UpdatedAt,
+		Labels:      metadata.Labels,
+	}
+
+	if input.WorkingDirectory != nil {
+		record.WorkingDirectory = filepath.Clean(*input.WorkingDirectory)
+	}
+
+	if input.ConversationID != nil {
+		record.ConversationIDs = append(record.ConversationIDs, *input.ConversationID)
+	}
+
+	if result != nil && result.ConversationID != "" {
+		record.ConversationIDs = append(record.ConversationIDs, result.ConversationID)
+	}
+
+	return record
+}
+
+// Matches reports whether the record passes every filter of the query.
+func (query ExecutionQuery) Matches(record ExecutionRecord) bool {
+	if query.AgentID != nil && record.AgentID != *query.AgentID {
+		return false
+	}
+
+	if query.RuntimeKind != nil && record.RuntimeKind != *query.RuntimeKind {
+		return false
+	}
+
+	if len(query.States) > 0 && !slices.Contains(query.States, record.State) {
+		return false
+	}
+
+	if query.CreatedAfter != nil && record.CreatedAt.Before(*query.CreatedAfter) {
+		return false
+	}
+
+	if query.CreatedBefore != nil && !record.CreatedAt.Before(*query.CreatedBefore) {
+		return false
+	}
+
+	if query.WorkingDirectory != nil && record.WorkingDirectory != *query.WorkingDirectory {
+		return false
+	}
+
+	if query.ConversationID != nil && !slices.Contains(record.ConversationIDs, *query.ConversationID) {
+		return false
+	}
+
+	for key, value := range query.Labels {
+		labelValue, ok := record.Labels[key]
+		if !ok || (value != "" && labelValue != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply sorts the records, starts after the cursor, and returns the identifiers of the matching records up to the limit.
+func (query ExecutionQuery) Apply(records []ExecutionRecord) ([]ExecutionID, error) {
+	sorted := slices.Clone(records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return query.less(sorted[i], sorted[j])
+	})
+
+	var cursor ExecutionRecord
+	if query.Cursor != nil {
+		cursor = ExecutionRecord{ID: query.Cursor.ID, CreatedAt: query.Cursor.CreatedAt}
+	}
+
+	ids := make([]ExecutionID, 0, len(sorted))
+	for _, record := range sorted {
+		if query.Limit > 0 && len(ids) == query.Limit {
+			break
+		}
+
+		if query.Cursor != nil && !query.less(cursor, record) {
+			continue
+		}
+
+		if query.Matches(record) {
+			ids = append(ids, record.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (query ExecutionQuery) less(a ExecutionRecord, b ExecutionRecord) bool {
+	switch query.Sort {
+	case ExecutionSortCreatedAsc:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	case ExecutionSortCreatedDesc:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+	}
+
+	return a.ID < b.ID
+}
+
+var (
+	// ErrExecutionSortInvalid indicates the query sort order is not supported.
+	ErrExecutionSortInvalid = errors.New("execution sort invalid")
+
+	// ErrExecutionLimitInvalid indicates the query limit is negative.
+	ErrExecutionLimitInvalid = errors.New("execution limit invalid")
+
+	// ErrExecutionCursorInvalid indicates the query cursor is not a creation time and execution identifier.
+	ErrExecutionCursorInvalid = errors.New("execution cursor invalid")
+)
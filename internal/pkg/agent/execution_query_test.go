@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionQuery_Matches(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	record := ExecutionRecord{
+		ID:               "e1",
+		AgentID:          "claude-code",
+		RuntimeKind:      "claude",
+		State:            ExecutionSucceeded,
+		CreatedAt:        createdAt,
+		WorkingDirectory: "/repo",
+		ConversationIDs:  []ConversationID{"conv-1", "conv-2"},
+		Labels:           map[string]string{"team": "payments", "ticket": "PAY-12"},
+	}
+
+	tests := []struct {
+		name    string
+		filters []ExecutionFilter
+		matches bool
+	}{
+		{name: "no filters", matches: true},
+		{name: "agent", filters: []ExecutionFilter{FilterByAgentID("claude-code")}, matches: true},
+		{name: "other agent", filters: []ExecutionFilter{FilterByAgentID("codex")}, matches: false},
+		{name: "runtime kind", filters: []ExecutionFilter{FilterByRuntimeKind("claude")}, matches: true},
+		{name: "any of states", filters: []ExecutionFilter{FilterByState(ExecutionFailed, ExecutionSucceeded)}, matches: true},
+		{name: "other state", filters: []ExecutionFilter{FilterByState(ExecutionRunning)}, matches: false},
+		{name: "created after is inclusive", filters: []ExecutionFilter{FilterCreatedAfter(createdAt)}, matches: true},
+		{name: "created before is exclusive", filters: []ExecutionFilter{FilterCreatedBefore(createdAt)}, matches: false},
+		{name: "created range", filters: []ExecutionFilter{FilterCreatedAfter(createdAt.Add(-time.Hour)), FilterCreatedBefore(createdAt.Add(time.Hour))}, matches: true},
+		{name: "working directory is cleaned", filters: []ExecutionFilter{FilterByWorkingDirectory("/repo/")}, matches: true},
+		{name: "produced conversation", filters: []ExecutionFilter{FilterByConversationID("conv-2")}, matches: true},
+		{name: "other conversation", filters: []ExecutionFilter{FilterByConversationID("conv-3")}, matches: false},
+		{name: "label value", filters: []ExecutionFilter{FilterByLabel("team", "payments")}, matches: true},
+		{name: "label presence", filters: []ExecutionFilter{FilterByLabel("ticket", "")}, matches: true},
+		{name: "every label", filters: []ExecutionFilter{FilterByLabel("team", "payments"), FilterByLabel("env", "")}, matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, NewExecutionQuery(tt.filters...).Matches(record))
+		})
+	}
+}
+
+func TestExecutionQuery_Apply(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := []ExecutionRecord{
+		{ID: "b", State: ExecutionSucceeded, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "a", State: ExecutionFailed, CreatedAt: base.Add(3 * time.Minute)},
+		{ID: "d", State: ExecutionSucceeded, CreatedAt: base},
+		{ID: "c", State: ExecutionSucceeded, CreatedAt: base},
+	}
+
+	t.Run("sorts by id by default", func(t *testing.T) {
+		ids, err := NewExecutionQuery().Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"a", "b", "c", "d"}, ids)
+	})
+
+	t.Run("sorts by creation time with ties by id", func(t *testing.T) {
+		ids, err := NewExecutionQuery(SortExecutions(ExecutionSortCreatedAsc)).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"c", "d", "b", "a"}, ids)
+
+		ids, err = NewExecutionQuery(SortExecutions(ExecutionSortCreatedDesc)).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"a", "b", "c", "d"}, ids)
+	})
+
+	t.Run("pages with limit and cursor", func(t *testing.T) {
+		filters := []ExecutionFilter{SortExecutions(ExecutionSortCreatedAsc), FilterByState(ExecutionSucceeded), LimitExecutions(2)}
+
+		ids, err := NewExecutionQuery(filters...).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"c", "d"}, ids)
+
+		ids, err = NewExecutionQuery(append(filters, ExecutionsAfter(ExecutionCursor{CreatedAt: base, ID: "d"}))...).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"b"}, ids)
+	})
+
+	t.Run("cursor no longer matching the filters", func(t *testing.T) {
+		ids, err := NewExecutionQuery(FilterByState(ExecutionSucceeded), ExecutionsAfter(ExecutionCursor{ID: "a"})).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"b", "c", "d"}, ids)
+	})
+
+	t.Run("cursor of a deleted execution", func(t *testing.T) {
+		cursor := ExecutionCursor{CreatedAt: base.Add(time.Minute), ID: "z"}
+		ids, err := NewExecutionQuery(SortExecutions(ExecutionSortCreatedAsc), ExecutionsAfter(cursor)).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"b", "a"}, ids)
+
+		ids, err = NewExecutionQuery(SortExecutions(ExecutionSortCreatedDesc), ExecutionsAfter(cursor)).Apply(records)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutionID{"c", "d"}, ids)
+	})
+}
+
+func TestParseExecutionCursor(t *testing.T) {
+	cursor := ExecutionCursor{CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 1500, time.UTC), ID: NewExecutionID()}
+
+	parsed, err := ParseExecutionCursor(cursor.String())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	text, err := cursor.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01T12:00:00.0000015Z_"+string(cursor.ID), string(text))
+
+	for _, value := range []string{"", string(cursor.ID), "yesterday_" + string(cursor.ID), "2025-03-01T12:00:00Z_e1"} {
+		_, err := ParseExecutionCursor(value)
+		assert.ErrorIs(t, err, ErrExecutionCursorInvalid, value)
+	}
+}
+
+func TestExecutionQuery_Validate(t *testing.T) {
+	assert.NoError(t, NewExecutionQuery(SortExecutions(ExecutionSortCreatedDesc), LimitExecutions(10)).Validate())
+	assert.ErrorIs(t, NewExecutionQuery(SortExecutions("newest")).Validate(), ErrExecutionSortInvalid)
+	assert.ErrorIs(t, NewExecutionQuery(LimitExecutions(-1)).Validate(), ErrExecutionLimitInvalid)
+}
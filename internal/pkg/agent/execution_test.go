@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,6 +37,16 @@ func TestExecutionIDValidate(t *testing.T) {
 	})
 }
 
+func TestExecutionStateIsActive(t *testing.T) {
+	for _, state := range []ExecutionState{ExecutionCreated, ExecutionStarted, ExecutionRunning, ExecutionDeferred} {
+		assert.True(t, state.IsActive(), state)
+	}
+
+	for _, state := range []ExecutionState{ExecutionSucceeded, ExecutionFailed} {
+		assert.False(t, state.IsActive(), state)
+	}
+}
+
 func TestExecutionInputAttachmentValidate(t *testing.T) {
 	t.Run("missing mime type", func(t *testing.T) {
 		err := ExecutionInputAttachment{Path: "/tmp/file.png"}.Validate()
@@ -105,6 +116,13 @@ func TestExecutionInputValidate(t *testing.T) {
 		require.ErrorIs(t, err, ErrExecutionAttachmentMimeTypeRequired)
 	})
 
+	t.Run("invalid output schema", func(t *testing.T) {
+		input := valid
+		input.OutputSchema = []byte(`{"type": 42}`)
+		err := input.Validate()
+		require.ErrorIs(t, err, ErrExecutionOutputSchemaInvalid)
+	})
+
 	t.Run("tilde working directory", func(t *testing.T) {
 		home := t.TempDir()
 		t.Setenv("HOME", home)
@@ -115,8 +133,42 @@ func TestExecutionInputValidate(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("fork without conversation", func(t *testing.T) {
+		input := valid
+		input.ForkConversation = true
+		err := input.Validate()
+		require.ErrorIs(t, err, ErrExecutionForkConversationRequired)
+	})
+
 	t.Run("valid", func(t *testing.T) {
 		err := valid.Validate()
 		require.NoError(t, err)
 	})
 }
+
+func TestExecutionMetadata(t *testing.T) {
+	t.Run("turn parent", func(t *testing.T) {
+		metadata := NewExecutionMetadata(WithParentID("e1"), WithFallbackOf("e0"))
+		require.NotNil(t, metadata.Parent())
+		assert.Equal(t, ExecutionID("e1"), *metadata.Parent())
+		assert.False(t, metadata.Fork)
+	})
+
+	t.Run("fallback parent", func(t *testing.T) {
+		metadata := NewExecutionMetadata(WithParentID(EmptyExecutionID), WithFallbackOf("e0"))
+		require.NotNil(t, metadata.Parent())
+		assert.Equal(t, ExecutionID("e0"), *metadata.Parent())
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		metadata := NewExecutionMetadata(WithFork("e1"))
+		require.NotNil(t, metadata.Parent())
+		assert.True(t, metadata.Fork)
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		input := ExecutionInput{Prompt: "replayed turns and the instruction"}
+		assert.Equal(t, input.Prompt, NewExecutionMetadata().Prompt(input))
+		assert.Equal(t, "the instruction", NewExecutionMetadata(WithInstruction("the instruction")).Prompt(input))
+	})
+}
@@ -0,0 +1,197 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ForkPoint is a successful turn a conversation branches off, with the session turns leading up to it.
+type ForkPoint struct {
+	// Session is the session the turn belongs to.
+	Session Session
+
+	// Transcript holds the session turns up to and including the fork point turn.
+	Transcript []TranscriptEntry
+
+	// Latest reports whether no later turn of the session continued the fork point conversation.
+	Latest bool
+}
+
+// FindForkPoint locates the session turn answered by the execution.
+// The turn of a deleted execution is still found in the transcript of its session, which keeps the turn.
+// Returns ErrForkPointNotFound when the execution did not answer a turn of a session.
+// Returns ErrForkPointUnfinished when the turn did not succeed.
+func FindForkPoint(ctx context.Context, executionRepository ExecutionRepository, sessionRepository SessionRepository, id ExecutionID) (ForkPoint, error) {
+	execution, err := executionRepository.Get(ctx, id)
+	if errors.Is(err, ErrExecutionNotFound) {
+		return findTranscriptForkPoint(ctx, sessionRepository, id)
+	}
+	if err != nil {
+		return ForkPoint{}, fmt.Errorf("get execution: %w", err)
+	}
+
+	metadata, err := execution.GetMetadata(ctx)
+	if err != nil {
+		return ForkPoint{}, fmt.Errorf("get execution metadata: %w", err)
+	}
+
+	if metadata.SessionID == nil {
+		return ForkPoint{}, fmt.Errorf("%w: execution %s does not belong to a session", ErrForkPointNotFound, id)
+	}
+
+	session, err := sessionRepository.Get(ctx, *metadata.SessionID)
+	if err != nil {
+		return ForkPoint{}, fmt.Errorf("get session: %w", err)
+	}
+
+	transcript, err := sessionRepository.GetTranscript(ctx, session.ID)
+	if err != nil {
+		return ForkPoint{}, fmt.Errorf("get session transcript: %w", err)
+	}
+
+	return NewForkPoint(session, transcript, id)
+}
+
+// findTranscriptForkPoint locates the turn of a deleted execution in the session transcripts.
+// Fork sessions copy the turns they branch off, so the oldest session with the turn is the one that ran it.
+func findTranscriptForkPoint(ctx context.Context, repository SessionRepository, id ExecutionID) (ForkPoint, error) {
+	ids, err := repository.List(ctx)
+	if err != nil {
+		return ForkPoint{}, fmt.Errorf("list sessions: %w", err)
+	}
+
+	var found *ForkPoint
+	for _, sessionID := range ids {
+		session, err := repository.Get(ctx, sessionID)
+		if err != nil {
+			return ForkPoint{}, fmt.Errorf("get session: %w", err)
+		}
+
+		if found != nil && !session.CreatedAt.Before(found.Session.CreatedAt) {
+			continue
+		}
+
+		transcript, err := repository.GetTranscript(ctx, sessionID)
+		if err != nil {
+			return ForkPoint{}, fmt.Errorf("get session transcript: %w", err)
+		}
+
+		point, err := NewForkPoint(session, transcript, id)
+		if errors.Is(err, ErrForkPointNotFound) {
+			continue
+		}
+		if err != nil {
+			return ForkPoint{}, err
+		}
+
+		found = &point
+	}
+
+	if found == nil {
+		return ForkPoint{}, fmt.Errorf("%w: execution %s does not exist and is not a turn of any session", ErrForkPointNotFound, id)
+	}
+
+	return *found, nil
+}
+
+// NewForkPoint locates the turn answered by the execution in the session transcript.
+// Returns ErrForkPointNotFound when the transcript has no turn for the execution.
+// Returns ErrForkPointUnfinished when the turn did not succeed.
+func NewForkPoint(session Session, transcript []TranscriptEntry, id ExecutionID) (ForkPoint, error) {
+	for i, entry := range transcript {
+		if entry.ExecutionID != id {
+			continue
+		}
+
+		if entry.State != ExecutionSucceeded {
+			return ForkPoint{}, fmt.Errorf("%w: execution %s %s", ErrForkPointUnfinished, id, entry.State)
+		}
+
+		latest := true
+		for _, later := range transcript[i+1:] {
+			if entry.ConversationID != "" && later.ConversationID == entry.ConversationID {
+				latest = false
+				break
+			}
+		}
+
+		return ForkPoint{
+			Session:    session,
+			Transcript: transcript[:i+1],
+			Latest:     latest,
+		}, nil
+	}
+
+	return ForkPoint{}, fmt.Errorf("%w: execution %s is not a turn of session %s", ErrForkPointNotFound, id, session.ID)
+}
+
+// Turn returns the fork point turn.
+func (point ForkPoint) Turn() TranscriptEntry {
+	return point.Transcript[len(point.Transcript)-1]
+}
+
+// AgentID returns the agent that answered the fork point turn.
+func (point ForkPoint) AgentID() AgentID {
+	if turn := point.Turn(); turn.AgentID != "" {
+		return turn.AgentID
+	}
+
+	return point.Session.AgentID
+}
+
+// Input prepares the input of the first turn of a branch.
+// The runtime forks the fork point conversation when it supports forking and no later turn continued that conversation,
+// because a runtime fork starts from the latest state of the conversation.
+// Otherwise the prompt replays the turns up to the fork point in a new conversation.
+func (point ForkPoint) Input(input ExecutionInput, capabilities RuntimeCapabilities) (ExecutionInput, error) {
+	conversationID := point.Turn().ConversationID
+	if capabilities.Resume && capabilities.Fork && point.Latest && conversationID != "" {
+		input.ConversationID = &conversationID
+		input.ForkConversation = true
+
+		return input, nil
+	}
+
+	prompt, err := BuildReplayPrompt(point.Session, point.Transcript, input.Prompt)
+	if err != nil {
+		return input, err
+	}
+
+	input.Prompt = prompt
+	input.ConversationID = nil
+	input.ForkConversation = false
+
+	return input, nil
+}
+
+// StartForkSession creates a session on the fork point agent that branches off the fork point.
+// The turns up to the fork point are copied into the new transcript, so the branch can be continued,
+// handed off and forked like any other session.
+func StartForkSession(ctx context.Context, repository SessionRepository, point ForkPoint) (SessionID, error) {
+	forkOf := point.Turn().ExecutionID
+	id, err := repository.Create(ctx, Session{
+		AgentID:          point.AgentID(),
+		WorkingDirectory: point.Session.WorkingDirectory,
+		ForkOf:           &forkOf,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create fork session: %w", err)
+	}
+
+	for _, entry := range point.Transcript {
+		if err := repository.AppendTranscript(ctx, id, entry); err != nil {
+			return "", fmt.Errorf("copy transcript to fork session: %w", err)
+		}
+	}
+
+	return id, nil
+}
+
+var (
+	// ErrForkPointNotFound indicates the execution is not a turn of a session that can be forked.
+	ErrForkPointNotFound = errors.New("fork point not found")
+
+	// ErrForkPointUnfinished indicates the execution to fork did not succeed.
+	ErrForkPointUnfinished = errors.New("fork point unfinished")
+)
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewForkPoint(t *testing.T) {
+	session := Session{ID: "session", AgentID: "claude-code"}
+	transcript := []TranscriptEntry{
+		{ExecutionID: "e1", Prompt: "Plan the migration.", Response: "Three steps.", ConversationID: "conv-1", State: ExecutionSucceeded},
+		{ExecutionID: "e2", Prompt: "Start with step one.", Error: "timeout", State: ExecutionFailed},
+		{ExecutionID: "e3", Prompt: "Start with step one.", Response: "Done.", ConversationID: "conv-1", State: ExecutionSucceeded},
+	}
+
+	t.Run("earlier turn", func(t *testing.T) {
+		point, err := NewForkPoint(session, transcript, "e1")
+		require.NoError(t, err)
+
+		assert.Len(t, point.Transcript, 1)
+		assert.False(t, point.Latest)
+		assert.Equal(t, AgentID("claude-code"), point.AgentID())
+	})
+
+	t.Run("latest turn", func(t *testing.T) {
+		point, err := NewForkPoint(session, transcript, "e3")
+		require.NoError(t, err)
+
+		assert.Len(t, point.Transcript, 3)
+		assert.True(t, point.Latest)
+	})
+
+	t.Run("failed turn", func(t *testing.T) {
+		_, err := NewForkPoint(session, transcript, "e2")
+		require.ErrorIs(t, err, ErrForkPointUnfinished)
+	})
+
+	t.Run("unknown execution", func(t *testing.T) {
+		_, err := NewForkPoint(session, transcript, "e4")
+		require.ErrorIs(t, err, ErrForkPointNotFound)
+	})
+}
+
+// deletedExecutionRepository reports every execution as deleted.
+type deletedExecutionRepository struct {
+	ExecutionRepository
+}
+
+func (r deletedExecutionRepository) Get(ctx context.Context, id ExecutionID) (Execution, error) {
+	return nil, ErrExecutionNotFound
+}
+
+// transcriptRepository answers session reads from in-memory sessions and transcripts.
+type transcriptRepository struct {
+	SessionRepository
+
+	sessions    []Session
+	transcripts map[SessionID][]TranscriptEntry
+}
+
+func (r transcriptRepository) List(ctx context.Context) ([]SessionID, error) {
+	ids := make([]SessionID, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		ids = append(ids, session.ID)
+	}
+
+	return ids, nil
+}
+
+func (r transcriptRepository) Get(ctx context.Context, id SessionID) (Session, error) {
+	for _, session := range r.sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+
+	return Session{}, ErrSessionNotFound
+}
+
+func (r transcriptRepository) GetTranscript(ctx context.Context, id SessionID) ([]TranscriptEntry, error) {
+	return r.transcripts[id], nil
+}
+
+func TestFindForkPoint_DeletedExecution(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := TranscriptEntry{ExecutionID: "e1", Prompt: "Plan the migration.", Response: "Three steps.", ConversationID: "conv-1", State: ExecutionSucceeded}
+	sessions := transcriptRepository{
+		// The fork session copied the turn, but the original session ran it.
+		sessions: []Session{
+			{ID: "fork", AgentID: "codex", CreatedAt: createdAt.Add(time.Hour)},
+			{ID: "session", AgentID: "claude-code", CreatedAt: createdAt},
+			{ID: "other", AgentID: "claude-code", CreatedAt: createdAt.Add(-time.Hour)},
+		},
+		transcripts: map[SessionID][]TranscriptEntry{
+			"fork":    {first},
+			"session": {first, {ExecutionID: "e2", Prompt: "Start with step one.", Response: "Done.", ConversationID: "conv-1", State: ExecutionSucceeded}},
+			"other":   {{ExecutionID: "e3", Prompt: "Unrelated.", State: ExecutionSucceeded}},
+		},
+	}
+
+	point, err := FindForkPoint(context.Background(), deletedExecutionRepository{}, sessions, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, SessionID("session"), point.Session.ID)
+	assert.Equal(t, []TranscriptEntry{first}, point.Transcript)
+	assert.False(t, point.Latest)
+
+	_, err = FindForkPoint(context.Background(), deletedExecutionRepository{}, sessions, "e4")
+	require.ErrorIs(t, err, ErrForkPointNotFound)
+}
+
+func TestForkPointInput(t *testing.T) {
+	session := Session{ID: "session", AgentID: "claude-code"}
+	transcript := []TranscriptEntry{
+		{ExecutionID: "e1", Prompt: "Plan the migration.", Response: "Three steps.", ConversationID: "conv-1", State: ExecutionSucceeded},
+		{ExecutionID: "e2", Prompt: "Use approach A.", Response: "Done with A.", ConversationID: "conv-1", State: ExecutionSucceeded},
+	}
+	forking := RuntimeCapabilities{Resume: true, Fork: true}
+
+	t.Run("runtime fork at the latest turn", func(t *testing.T) {
+		point, err := NewForkPoint(session, transcript, "e2")
+		require.NoError(t, err)
+
+		input, err := point.Input(ExecutionInput{Prompt: "Try approach B."}, forking)
+		require.NoError(t, err)
+
+		require.NotNil(t, input.ConversationID)
+		assert.Equal(t, ConversationID("conv-1"), *input.ConversationID)
+		assert.True(t, input.ForkConversation)
+		assert.Equal(t, "Try approach B.", input.Prompt)
+	})
+
+	t.Run("replay at an earlier turn", func(t *testing.T) {
+		point, err := NewForkPoint(session, transcript, "e1")
+		require.NoError(t, err)
+
+		input, err := point.Input(ExecutionInput{Prompt: "Try approach B."}, forking)
+		require.NoError(t, err)
+
+		assert.Nil(t, input.ConversationID)
+		assert.False(t, input.ForkConversation)
+		assert.Contains(t, input.Prompt, "Plan the migration.")
+		assert.NotContains(t, input.Prompt, "Use approach A.")
+		assert.Contains(t, input.Prompt, "## Next instruction\n\nTry approach B.\n")
+	})
+
+	t.Run("replay without runtime fork", func(t *testing.T) {
+		point, err := NewForkPoint(session, transcript, "e2")
+		require.NoError(t, err)
+
+		input, err := point.Input(ExecutionInput{Prompt: "Try approach B."}, RuntimeCapabilities{Resume: true})
+		require.NoError(t, err)
+
+		assert.Nil(t, input.ConversationID)
+		assert.Contains(t, input.Prompt, "Use approach A.")
+	})
+}
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HandoffMode selects how the prior turns of a session seed a conversation on another agent.
+type HandoffMode string
+
+const (
+	// HandoffTranscript replays every prior turn verbatim.
+	HandoffTranscript HandoffMode = "transcript"
+
+	// HandoffExcerpt cuts older turns to short excerpts and keeps the most recent turns verbatim.
+	HandoffExcerpt HandoffMode = "excerpt"
+)
+
+const (
+	handoffExcerptRecentTurns = 2
+	handoffExcerptLength      = 280
+	handoffDefaultPrompt      = "Briefly restate where the work stands, then continue with the next step."
+)
+
+// Validate checks whether the handoff mode is known.
+func (mode HandoffMode) Validate() error {
+	switch mode {
+	case HandoffTranscript, HandoffExcerpt:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrHandoffModeInvalid, mode)
+	}
+}
+
+// BuildHandoffPrompt returns the prompt that seeds a new conversation with the prior turns of a session,
+// followed by the next instruction. Failed turns are left out. An empty prompt asks the agent to continue the work.
+// Returns ErrHandoffModeInvalid when the mode is unknown.
+// Returns ErrHandoffTranscriptEmpty when the session has no successful turns.
+func BuildHandoffPrompt(session Session, transcript []TranscriptEntry, mode HandoffMode, prompt string) (string, error) {
+	if err := mode.Validate(); err != nil {
+		return "", err
+	}
+
+	turns := successfulTurns(transcript)
+	if len(turns) == 0 {
+		return "", fmt.Errorf("%w: session %s", ErrHandoffTranscriptEmpty, session.ID)
+	}
+
+	intro := fmt.Sprintf("You are taking over a conversation that was held with agent %s. ", session.AgentID)
+
+	return buildTranscriptPrompt(intro, session.AgentID, turns, mode, prompt), nil
+}
+
+// BuildReplayPrompt returns the prompt that restores a conversation from its transcript in a new conversation,
+// followed by the next instruction. It is used when a runtime cannot branch a conversation natively.
+// Returns ErrHandoffTranscriptEmpty when the transcript has no successful turns.
+func BuildReplayPrompt(session Session, transcript []TranscriptEntry, prompt string) (string, error) {
+	turns := successfulTurns(transcript)
+	if len(turns) == 0 {
+		return "", fmt.Errorf("%w: session %s", ErrHandoffTranscriptEmpty, session.ID)
+	}
+
+	intro := "You are continuing a conversation from an earlier point. "
+
+	return buildTranscriptPrompt(intro, session.AgentID, turns, HandoffTranscript, prompt), nil
+}
+
+func successfulTurns(transcript []TranscriptEntry) []TranscriptEntry {
+	var turns []TranscriptEntry
+	for _, entry := range transcript {
+		if entry.State == ExecutionSucceeded {
+			turns = append(turns, entry)
+		}
+	}
+
+	return turns
+}
+
+func buildTranscriptPrompt(intro string, sessionAgentID AgentID, turns []TranscriptEntry, mode HandoffMode, prompt string) string {
+	var builder strings.Builder
+	builder.WriteString(intro)
+	builder.WriteString("The earlier turns are below; treat them as your own prior work.\n")
+
+	verbatimFrom := 0
+	if mode == HandoffExcerpt && len(turns) > handoffExcerptRecentTurns {
+		verbatimFrom = len(turns) - handoffExcerptRecentTurns
+
+		builder.WriteString("\n## Excerpts of earlier turns\n\n")
+		for i, turn := range turns[:verbatimFrom] {
+			fmt.Fprintf(&builder, "- Turn %d: the user asked %q and the agent answered %q\n", i+1, excerpt(turn.Prompt), excerpt(turn.Response))
+		}
+	}
+
+	for i, turn := range turns[verbatimFrom:] {
+		agentID := turn.AgentID
+		if agentID == "" {
+			agentID = sessionAgentID
+		}
+
+		fmt.Fprintf(&builder, "\n## Turn %d\n\n### User\n\n%s\n\n### Agent (%s)\n\n%s\n", verbatimFrom+i+1, strings.TrimSpace(turn.Prompt), agentID, strings.TrimSpace(turn.Response))
+	}
+
+	fmt.Fprintf(&builder, "\n## Next instruction\n\n%s\n", HandoffInstruction(prompt))
+
+	return builder.String()
+}
+
+// HandoffInstruction returns the next instruction a handoff or replay prompt ends with.
+// An empty prompt asks the agent to continue the work.
+func HandoffInstruction(prompt string) string {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return handoffDefaultPrompt
+	}
+
+	return prompt
+}
+
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= handoffExcerptLength {
+		return text
+	}
+
+	return string(runes[:handoffExcerptLength]) + "…"
+}
+
+var (
+	// ErrHandoffModeInvalid indicates the handoff mode is not transcript or excerpt.
+	ErrHandoffModeInvalid = errors.New("handoff mode invalid")
+
+	// ErrHandoffTranscriptEmpty indicates the source session has no successful turns to hand off.
+	ErrHandoffTranscriptEmpty = errors.New("handoff transcript empty")
+)
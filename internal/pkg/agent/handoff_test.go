@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHandoffPrompt(t *testing.T) {
+	session := Session{ID: "source", AgentID: "gemini"}
+	transcript := []TranscriptEntry{
+		{Prompt: "Propose a cache design.", Response: "Use an LRU in front of the store.", State: ExecutionSucceeded},
+		{Prompt: "What about invalidation?", Error: "rate limited", State: ExecutionFailed},
+		{Prompt: "What about invalidation?", Response: "Invalidate on write.", State: ExecutionSucceeded},
+		{Prompt: "Size it.", Response: "Start with 10k entries.", AgentID: "claude-code", State: ExecutionSucceeded},
+	}
+
+	t.Run("transcript", func(t *testing.T) {
+		prompt, err := BuildHandoffPrompt(session, transcript, HandoffTranscript, "Implement it.")
+		require.NoError(t, err)
+
+		assert.Contains(t, prompt, "held with agent gemini")
+		assert.Contains(t, prompt, "## Turn 1\n\n### User\n\nPropose a cache design.\n\n### Agent (gemini)\n\nUse an LRU in front of the store.\n")
+		assert.Contains(t, prompt, "## Turn 3")
+		assert.Contains(t, prompt, "### Agent (claude-code)")
+		assert.NotContains(t, prompt, "rate limited")
+		assert.True(t, strings.HasSuffix(prompt, "## Next instruction\n\nImplement it.\n"))
+	})
+
+	t.Run("excerpt", func(t *testing.T) {
+		prompt, err := BuildHandoffPrompt(session, transcript, HandoffExcerpt, "")
+		require.NoError(t, err)
+
+		assert.Contains(t, prompt, "- Turn 1: the user asked \"Propose a cache design.\" and the agent answered \"Use an LRU in front of the store.\"")
+		assert.NotContains(t, prompt, "## Turn 1\n")
+		assert.Contains(t, prompt, "## Turn 2\n")
+		assert.Contains(t, prompt, "## Turn 3\n")
+		assert.Contains(t, prompt, handoffDefaultPrompt)
+	})
+
+	t.Run("empty transcript", func(t *testing.T) {
+		_, err := BuildHandoffPrompt(session, transcript[1:2], HandoffTranscript, "Implement it.")
+		require.ErrorIs(t, err, ErrHandoffTranscriptEmpty)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := BuildHandoffPrompt(session, transcript, "digest", "Implement it.")
+		require.ErrorIs(t, err, ErrHandoffModeInvalid)
+	})
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetentionPolicy describes which stored executions expire and may be deleted.
+type RetentionPolicy struct {
+	// MaxAge expires executions created longer ago than the age. Zero expires executions of any age.
+	MaxAge time.Duration
+
+	// KeepLast keeps the most recently created executions the policy applies to, whatever their age.
+	KeepLast int
+
+	// States limits the policy to executions in any of the states. Defaults to the finished states.
+	States []ExecutionState
+
+	// AgentID limits the policy to executions run by the agent.
+	AgentID *AgentID
+}
+
+// Validate checks whether the policy limits are not negative and only finished states expire.
+func (policy RetentionPolicy) Validate() error {
+	if policy.MaxAge < 0 {
+		return ErrRetentionMaxAgeInvalid
+	}
+
+	if policy.KeepLast < 0 {
+		return ErrRetentionKeepLastInvalid
+	}
+
+	for _, state := range policy.States {
+		if !state.IsFinished() {
+			return fmt.Errorf("%w: %s", ErrRetentionStateInvalid, state)
+		}
+	}
+
+	return nil
+}
+
+// SelectExpired returns the executions of the repository that expired under the policy at the time, newest first.
+// Returns ErrRetentionMaxAgeInvalid, ErrRetentionKeepLastInvalid or ErrRetentionStateInvalid when the policy is invalid.
+func (policy RetentionPolicy) SelectExpired(ctx context.Context, repository ExecutionRepository, now time.Time) ([]ExecutionID, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	states := policy.States
+	if len(states) == 0 {
+		states = []ExecutionState{ExecutionSucceeded, ExecutionFailed}
+	}
+
+	filters := []ExecutionFilter{FilterByState(states...), SortExecutions(ExecutionSortCreatedDesc)}
+	if policy.AgentID != nil {
+		filters = append(filters, FilterByAgentID(*policy.AgentID))
+	}
+
+	ids, err := repository.Find(ctx, filters...)
+	if err != nil {
+		return nil, fmt.Errorf("find executions: %w", err)
+	}
+
+	if policy.KeepLast >= len(ids) {
+		return []ExecutionID{}, nil
+	}
+	ids = ids[policy.KeepLast:]
+
+	if policy.MaxAge == 0 {
+		return ids, nil
+	}
+
+	oldIDs, err := repository.Find(ctx, append(filters, FilterCreatedBefore(now.Add(-policy.MaxAge)))...)
+	if err != nil {
+		return nil, fmt.Errorf("find executions: %w", err)
+	}
+
+	old := make(map[ExecutionID]bool, len(oldIDs))
+	for _, id := range oldIDs {
+		old[id] = true
+	}
+
+	expired := make([]ExecutionID, 0, len(ids))
+	for _, id := range ids {
+		if old[id] {
+			expired = append(expired, id)
+		}
+	}
+
+	return expired, nil
+}
+
+var (
+	// ErrRetentionMaxAgeInvalid indicates the retention max age is negative.
+	ErrRetentionMaxAgeInvalid = errors.New("retention max age invalid")
+
+	// ErrRetentionKeepLastInvalid indicates the retention keep last count is negative.
+	ErrRetentionKeepLastInvalid = errors.New("retention keep last invalid")
+
+	// ErrRetentionStateInvalid indicates the retention policy applies to an unfinished execution state.
+	ErrRetentionStateInvalid = errors.New("retention state invalid")
+)
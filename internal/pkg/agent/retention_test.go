@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordRepository answers Find from in-memory records.
+type recordRepository struct {
+	ExecutionRepository
+
+	records []ExecutionRecord
+}
+
+func (r recordRepository) Find(ctx context.Context, filters ...ExecutionFilter) ([]ExecutionID, error) {
+	return NewExecutionQuery(filters...).Apply(r.records)
+}
+
+func TestRetentionPolicy_SelectExpired(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	repository := recordRepository{records: []ExecutionRecord{
+		{ID: "a", AgentID: "codex", State: ExecutionSucceeded, CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: "b", AgentID: "claude", State: ExecutionFailed, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "c", AgentID: "codex", State: ExecutionRunning, CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "d", AgentID: "codex", State: ExecutionSucceeded, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "e", AgentID: "claude", State: ExecutionSucceeded, CreatedAt: now.Add(-72 * time.Hour)},
+	}}
+	claude := AgentID("claude")
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		ids    []ExecutionID
+	}{
+		{name: "every finished execution", policy: RetentionPolicy{}, ids: []ExecutionID{"a", "b", "d", "e"}},
+		{name: "older than max age", policy: RetentionPolicy{MaxAge: 24 * time.Hour}, ids: []ExecutionID{"d", "e"}},
+		{name: "keep last", policy: RetentionPolicy{KeepLast: 2}, ids: []ExecutionID{"d", "e"}},
+		{name: "keep last with max age", policy: RetentionPolicy{MaxAge: 90 * time.Minute, KeepLast: 3}, ids: []ExecutionID{"e"}},
+		{name: "keep more than stored", policy: RetentionPolicy{KeepLast: 10}, ids: []ExecutionID{}},
+		{name: "state", policy: RetentionPolicy{States: []ExecutionState{ExecutionFailed}}, ids: []ExecutionID{"b"}},
+		{name: "agent", policy: RetentionPolicy{AgentID: &claude, KeepLast: 1}, ids: []ExecutionID{"e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := tt.policy.SelectExpired(context.Background(), repository, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := RetentionPolicy{MaxAge: -time.Hour}.SelectExpired(context.Background(), repository, now)
+		require.ErrorIs(t, err, ErrRetentionMaxAgeInvalid)
+
+		_, err = RetentionPolicy{KeepLast: -1}.SelectExpired(context.Background(), repository, now)
+		require.ErrorIs(t, err, ErrRetentionKeepLastInvalid)
+
+		_, err = RetentionPolicy{States: []ExecutionState{ExecutionRunning}}.SelectExpired(context.Background(), repository, now)
+		require.ErrorIs(t, err, ErrRetentionStateInvalid)
+	})
+}
@@ -3,8 +3,12 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/version"
 )
 
 type RuntimeKind string
@@ -29,6 +33,12 @@ const (
 
 	// RuntimeEventFinished indicates the runtime instance has finished.
 	RuntimeEventFinished RuntimeEventKind = "runtime-finished"
+
+	// RuntimeEventMessage indicates the agent produced response text.
+	RuntimeEventMessage RuntimeEventKind = "runtime-message"
+
+	// RuntimeEventActivity indicates the agent used a tool, ran a command or edited a file.
+	RuntimeEventActivity RuntimeEventKind = "runtime-activity"
 )
 
 // RuntimeEvent represents a runtime event emitted by a runtime instance.
@@ -60,6 +70,32 @@ func NewRuntimeEventEnvelope(event RuntimeEvent) (RuntimeEventEnvelope, error) {
 	}, nil
 }
 
+// Decode restores the runtime event carried by the envelope.
+// Returns ErrRuntimeEventKindUnknown when the envelope kind is not recognized.
+func (envelope RuntimeEventEnvelope) Decode() (RuntimeEvent, error) {
+	switch envelope.Kind {
+	case RuntimeEventStarted:
+		return decodeRuntimeEvent[RuntimeStartedEvent](envelope.Payload)
+	case RuntimeEventFinished:
+		return decodeRuntimeEvent[RuntimeFinishedEvent](envelope.Payload)
+	case RuntimeEventMessage:
+		return decodeRuntimeEvent[RuntimeMessageEvent](envelope.Payload)
+	case RuntimeEventActivity:
+		return decodeRuntimeEvent[RuntimeActivityEvent](envelope.Payload)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrRuntimeEventKindUnknown, envelope.Kind)
+	}
+}
+
+func decodeRuntimeEvent[T RuntimeEvent](payload json.RawMessage) (RuntimeEvent, error) {
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("unmarshal runtime event: %w", err)
+	}
+
+	return event, nil
+}
+
 // RuntimeStartedEvent describes a runtime start event.
 type RuntimeStartedEvent struct {
 	Timestamp time.Time `json:"timestamp"`
@@ -90,6 +126,62 @@ func (event RuntimeFinishedEvent) At() time.Time {
 	return event.Timestamp
 }
 
+// RuntimeMessageEvent carries response text as the agent produces it.
+type RuntimeMessageEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Text is the response text produced since the previous message event.
+	Text string `json:"text"`
+}
+
+// Kind returns the runtime event kind.
+func (RuntimeMessageEvent) Kind() RuntimeEventKind {
+	return RuntimeEventMessage
+}
+
+// At returns the timestamp when the event occurred.
+func (event RuntimeMessageEvent) At() time.Time {
+	return event.Timestamp
+}
+
+// RuntimeActivityKind categorizes agent activity.
+type RuntimeActivityKind string
+
+const (
+	// RuntimeActivityCommand is a shell command run by the agent.
+	RuntimeActivityCommand RuntimeActivityKind = "command"
+
+	// RuntimeActivityEdit is a file created or changed by the agent.
+	RuntimeActivityEdit RuntimeActivityKind = "edit"
+
+	// RuntimeActivityTool is any other tool call, such as a search or an MCP tool.
+	RuntimeActivityTool RuntimeActivityKind = "tool"
+)
+
+// RuntimeActivityEvent describes a tool call, command or edit made by the agent.
+type RuntimeActivityEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Activity categorizes the activity.
+	Activity RuntimeActivityKind `json:"activity"`
+
+	// Name is the runtime's name for the tool.
+	Name string `json:"name"`
+
+	// Detail is the command line, file path or a short summary of the tool input.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Kind returns the runtime event kind.
+func (RuntimeActivityEvent) Kind() RuntimeEventKind {
+	return RuntimeEventActivity
+}
+
+// At returns the timestamp when the event occurred.
+func (event RuntimeActivityEvent) At() time.Time {
+	return event.Timestamp
+}
+
 // RuntimeRegistry provides access to available runtimes.
 type RuntimeRegistry interface {
 	// Get returns the runtime implementation for the provided kind.
@@ -116,11 +208,55 @@ type Runtime interface {
 
 	// GetInfo returns metadata about the runtime implementation.
 	GetInfo(ctx context.Context) (RuntimeInfo, error)
+
+	// ValidateConfig checks whether the runtime-specific configuration is well-formed.
+	ValidateConfig(ctx context.Context, config RuntimeConfig) error
+
+	// GetCapabilities returns the execution features supported by the runtime.
+	GetCapabilities(ctx context.Context) (RuntimeCapabilities, error)
+
+	// ListModels returns the model identifiers known to the runtime.
+	// Runtimes whose CLI cannot report its models return a static list, which may be outdated.
+	// The list is informational; runtimes may accept models that are not listed.
+	ListModels(ctx context.Context) ([]string, error)
 }
 
 // RuntimeInfo describes runtime metadata.
 type RuntimeInfo struct {
 	Version string `json:"version"`
+
+	// SupportedVersions is the version constraint the runtime integration is tested against, for example ">=1.0.0, <2.0.0".
+	SupportedVersions string `json:"supportedVersions,omitempty"`
+}
+
+// CheckVersion verifies the detected version. When pin is set, only the pin is checked; otherwise the supported range applies.
+// Returns ErrRuntimeVersionIncompatible when the pin is not satisfied or the version cannot be parsed for a pin.
+// Returns ErrRuntimeVersionUnsupported when the version is outside the supported range.
+func (info RuntimeInfo) CheckVersion(pin string) error {
+	constraintValue, mismatchErr := info.SupportedVersions, ErrRuntimeVersionUnsupported
+	if pin != "" {
+		constraintValue, mismatchErr = pin, ErrRuntimeVersionIncompatible
+	}
+
+	if constraintValue == "" {
+		return nil
+	}
+
+	constraint, err := version.ParseConstraint(constraintValue)
+	if err != nil {
+		return fmt.Errorf("%w: %w", mismatchErr, err)
+	}
+
+	detected, err := version.Parse(info.Version)
+	if err != nil {
+		return fmt.Errorf("%w: %w", mismatchErr, err)
+	}
+
+	if !constraint.Check(detected) {
+		return fmt.Errorf("%w: %s does not satisfy %s", mismatchErr, detected, constraint)
+	}
+
+	return nil
 }
 
 // RuntimeResult captures the output of a runtime instance.
@@ -128,6 +264,41 @@ type RuntimeResult struct {
 	Response string `json:"response,omitempty"`
 
 	ConversationID ConversationID `json:"conversationId,omitempty"`
+
+	// Usage reports the tokens consumed by the run, when the runtime reports them.
+	Usage *RuntimeUsage `json:"usage,omitempty"`
+}
+
+// RuntimeUsage reports the tokens and cost consumed by a runtime run.
+type RuntimeUsage struct {
+	// InputTokens is the number of prompt tokens, including cached ones.
+	InputTokens int64 `json:"inputTokens"`
+
+	// CachedInputTokens is the number of prompt tokens served from the cache.
+	CachedInputTokens int64 `json:"cachedInputTokens,omitempty"`
+
+	// OutputTokens is the number of generated tokens.
+	OutputTokens int64 `json:"outputTokens"`
+
+	// CostUSD is the cost of the run in US dollars, when the runtime reports it.
+	CostUSD *float64 `json:"costUsd,omitempty"`
+}
+
+// Add accumulates the usage of another run.
+func (usage RuntimeUsage) Add(other RuntimeUsage) RuntimeUsage {
+	usage.InputTokens += other.InputTokens
+	usage.CachedInputTokens += other.CachedInputTokens
+	usage.OutputTokens += other.OutputTokens
+
+	if other.CostUSD != nil {
+		cost := *other.CostUSD
+		if usage.CostUSD != nil {
+			cost += *usage.CostUSD
+		}
+		usage.CostUSD = &cost
+	}
+
+	return usage
 }
 
 // RuntimeExecutionError reports a runtime execution failure.
@@ -138,10 +309,77 @@ type RuntimeExecutionError struct {
 	// ExitCode is the process exit code when available.
 	ExitCode *int
 
+	// Class categorizes the failure when the runtime recognizes it.
+	Class RuntimeErrorClass
+
+	// RetryAt is when the runtime reported the failure condition clears, for example a rate limit reset.
+	RetryAt *time.Time
+
 	// Cause is the underlying error, when available.
 	Cause error
 }
 
+// RuntimeErrorClass categorizes runtime execution failures.
+type RuntimeErrorClass string
+
+const (
+	// RuntimeErrorUnknown is a failure the runtime did not classify.
+	RuntimeErrorUnknown RuntimeErrorClass = ""
+
+	// RuntimeErrorRateLimited is a usage or rate limit failure that clears after a reset time.
+	RuntimeErrorRateLimited RuntimeErrorClass = "rate-limited"
+
+	// RuntimeErrorAuth is a missing or expired login or API key.
+	RuntimeErrorAuth RuntimeErrorClass = "auth"
+
+	// RuntimeErrorCrash is a runtime CLI that was killed by a signal or aborted with a crash report.
+	RuntimeErrorCrash RuntimeErrorClass = "crash"
+
+	// RuntimeErrorTimeout is an execution that did not finish within its timeout.
+	RuntimeErrorTimeout RuntimeErrorClass = "timeout"
+)
+
+var crashPattern = regexp.MustCompile(`(?m)^(panic: |fatal error: |FATAL ERROR: |Segmentation fault)`)
+
+// ClassifyRuntimeError returns the class of an execution failure.
+// The class reported by the runtime wins; otherwise timeouts and crashes are recognized from the error itself.
+func ClassifyRuntimeError(err error) RuntimeErrorClass {
+	if err == nil {
+		return RuntimeErrorUnknown
+	}
+
+	var runtimeErr *RuntimeExecutionError
+	if errors.As(err, &runtimeErr) && runtimeErr.Class != RuntimeErrorUnknown {
+		return runtimeErr.Class
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return RuntimeErrorTimeout
+	}
+
+	if runtimeErr != nil {
+		if runtimeErr.ExitCode != nil && *runtimeErr.ExitCode < 0 {
+			return RuntimeErrorCrash
+		}
+
+		if crashPattern.MatchString(runtimeErr.Message) {
+			return RuntimeErrorCrash
+		}
+	}
+
+	return RuntimeErrorUnknown
+}
+
+// AllowsFallback reports whether another agent may take over an execution that failed with this class.
+func (class RuntimeErrorClass) AllowsFallback() bool {
+	switch class {
+	case RuntimeErrorRateLimited, RuntimeErrorAuth, RuntimeErrorCrash, RuntimeErrorTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
 // Error returns the error message for the runtime execution error.
 func (err *RuntimeExecutionError) Error() string {
 	if err == nil {
@@ -178,5 +416,10 @@ type RuntimeInstance interface {
 	Wait(ctx context.Context) (RuntimeResult, error)
 }
 
-// ErrRuntimeNotFound indicates the requested runtime is not registered.
-var ErrRuntimeNotFound = fmt.Errorf("runtime not found")
+var (
+	// ErrRuntimeNotFound indicates the requested runtime is not registered.
+	ErrRuntimeNotFound = fmt.Errorf("runtime not found")
+
+	// ErrRuntimeEventKindUnknown indicates the runtime event kind is not recognized.
+	ErrRuntimeEventKindUnknown = fmt.Errorf("runtime event kind unknown")
+)
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeEventEnvelopeDecode(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("started event", func(t *testing.T) {
+		envelope, err := NewRuntimeEventEnvelope(RuntimeStartedEvent{Timestamp: timestamp})
+		require.NoError(t, err)
+
+		event, err := envelope.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, RuntimeStartedEvent{Timestamp: timestamp}, event)
+	})
+
+	t.Run("finished event", func(t *testing.T) {
+		envelope, err := NewRuntimeEventEnvelope(RuntimeFinishedEvent{Timestamp: timestamp})
+		require.NoError(t, err)
+
+		event, err := envelope.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, RuntimeFinishedEvent{Timestamp: timestamp}, event)
+	})
+
+	t.Run("message event", func(t *testing.T) {
+		envelope, err := NewRuntimeEventEnvelope(RuntimeMessageEvent{Timestamp: timestamp, Text: "Hello"})
+		require.NoError(t, err)
+		assert.Equal(t, RuntimeEventMessage, envelope.Kind)
+
+		event, err := envelope.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, RuntimeMessageEvent{Timestamp: timestamp, Text: "Hello"}, event)
+	})
+
+	t.Run("activity event", func(t *testing.T) {
+		activity := RuntimeActivityEvent{Timestamp: timestamp, Activity: RuntimeActivityCommand, Name: "Bash", Detail: "go test ./..."}
+		envelope, err := NewRuntimeEventEnvelope(activity)
+		require.NoError(t, err)
+
+		event, err := envelope.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, activity, event)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		envelope := RuntimeEventEnvelope{Kind: "unknown", Payload: json.RawMessage(`{}`)}
+
+		_, err := envelope.Decode()
+		require.ErrorIs(t, err, ErrRuntimeEventKindUnknown)
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		envelope := RuntimeEventEnvelope{Kind: RuntimeEventStarted, Payload: json.RawMessage(`[]`)}
+
+		_, err := envelope.Decode()
+		require.Error(t, err)
+	})
+}
+
+func TestRuntimeUsageAdd(t *testing.T) {
+	cost := func(value float64) *float64 { return &value }
+
+	usage := RuntimeUsage{InputTokens: 100, CachedInputTokens: 40, OutputTokens: 10}
+	usage = usage.Add(RuntimeUsage{InputTokens: 50, OutputTokens: 5, CostUSD: cost(0.25)})
+	usage = usage.Add(RuntimeUsage{InputTokens: 1, OutputTokens: 1, CostUSD: cost(0.5)})
+
+	assert.Equal(t, int64(151), usage.InputTokens)
+	assert.Equal(t, int64(40), usage.CachedInputTokens)
+	assert.Equal(t, int64(16), usage.OutputTokens)
+	require.NotNil(t, usage.CostUSD)
+	assert.InDelta(t, 0.75, *usage.CostUSD, 1e-9)
+}
+
+func TestClassifyRuntimeError(t *testing.T) {
+	signaled := -1
+	failed := 1
+
+	tests := []struct {
+		name     string
+		err      error
+		expected RuntimeErrorClass
+		fallback bool
+	}{
+		{name: "reported class", err: &RuntimeExecutionError{Class: RuntimeErrorAuth}, expected: RuntimeErrorAuth, fallback: true},
+		{name: "deadline exceeded", err: fmt.Errorf("wait: %w", context.DeadlineExceeded), expected: RuntimeErrorTimeout, fallback: true},
+		{name: "killed by signal", err: &RuntimeExecutionError{ExitCode: &signaled}, expected: RuntimeErrorCrash, fallback: true},
+		{name: "crash report", err: &RuntimeExecutionError{Message: "FATAL ERROR: Reached heap limit", ExitCode: &failed}, expected: RuntimeErrorCrash, fallback: true},
+		{name: "regular failure", err: &RuntimeExecutionError{Message: "invalid prompt", ExitCode: &failed}, expected: RuntimeErrorUnknown},
+		{name: "plain error", err: errors.New("boom"), expected: RuntimeErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class := ClassifyRuntimeError(tt.err)
+			assert.Equal(t, tt.expected, class)
+			assert.Equal(t, tt.fallback, class.AllowsFallback())
+		})
+	}
+}
+
+func TestRuntimeInfoCheckVersion(t *testing.T) {
+	info := RuntimeInfo{Version: "2.0.14", SupportedVersions: ">=1.0.0, <2.0.0"}
+
+	t.Run("unsupported without pin", func(t *testing.T) {
+		require.ErrorIs(t, info.CheckVersion(""), ErrRuntimeVersionUnsupported)
+	})
+
+	t.Run("supported without pin", func(t *testing.T) {
+		require.NoError(t, RuntimeInfo{Version: "1.4.0", SupportedVersions: info.SupportedVersions}.CheckVersion(""))
+	})
+
+	t.Run("no declared range", func(t *testing.T) {
+		require.NoError(t, RuntimeInfo{Version: "9.9.9"}.CheckVersion(""))
+	})
+
+	t.Run("pin replaces supported range", func(t *testing.T) {
+		require.NoError(t, info.CheckVersion(">=2.0.0, <2.1.0"))
+	})
+
+	t.Run("pin not satisfied", func(t *testing.T) {
+		require.ErrorIs(t, info.CheckVersion("2.0.13"), ErrRuntimeVersionIncompatible)
+	})
+
+	t.Run("pin with undetected version", func(t *testing.T) {
+		require.ErrorIs(t, RuntimeInfo{}.CheckVersion(">=2.0.0"), ErrRuntimeVersionIncompatible)
+	})
+}
@@ -0,0 +1,204 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionID identifies a BriefKit session in the store.
+type SessionID string
+
+// Session is a continuous conversation with one agent that groups the turns of its executions.
+type Session struct {
+	// ID is the session identifier.
+	ID SessionID `json:"id"`
+
+	// AgentID is the agent the session talks to.
+	AgentID AgentID `json:"agentId"`
+
+	// WorkingDirectory is the filesystem path the session's executions run in.
+	WorkingDirectory *string `json:"workingDirectory,omitempty"`
+
+	// ConversationID is the runtime conversation continued by the next turn.
+	ConversationID *ConversationID `json:"conversationId,omitempty"`
+
+	// HandoffFrom is the session whose turns seeded this session, when it was started by a handoff.
+	HandoffFrom *SessionID `json:"handoffFrom,omitempty"`
+
+	// ForkOf is the execution whose turn the session branches off, when it was started by a fork.
+	ForkOf *ExecutionID `json:"forkOf,omitempty"`
+
+	// CreatedAt is the timestamp when the session was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is the timestamp of the most recent session update.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TranscriptEntry is a single turn in a session transcript.
+type TranscriptEntry struct {
+	// TurnID identifies the stored turn.
+	TurnID TurnID `json:"turnId"`
+
+	// ExecutionID is the execution that answered the turn.
+	ExecutionID ExecutionID `json:"executionId"`
+
+	// AgentID is the agent that answered the turn.
+	AgentID AgentID `json:"agentId,omitempty"`
+
+	// Prompt is the user input of the turn.
+	Prompt string `json:"prompt"`
+
+	// Response is the final agent response. It is empty when the turn failed.
+	Response string `json:"response,omitempty"`
+
+	// ConversationID is the runtime conversation the turn ran in.
+	ConversationID ConversationID `json:"conversationId,omitempty"`
+
+	// State is the final execution state of the turn.
+	State ExecutionState `json:"state"`
+
+	// Error carries the failure message when the turn failed.
+	Error string `json:"error,omitempty"`
+
+	// CreatedAt is when the turn's execution was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// FinishedAt is when the turn finished.
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// SessionRepository provides access to sessions and their transcripts in a store.
+type SessionRepository interface {
+	// Create persists a new session and returns its identifier.
+	// The identifier and timestamps of the given session are assigned by the repository.
+	// Returns ErrAgentIDInvalid when the session agent is missing.
+	Create(ctx context.Context, session Session) (SessionID, error)
+
+	// Exists reports whether a session with the given identifier exists.
+	// Returns ErrSessionIDInvalid when the identifier is missing or malformed.
+	Exists(ctx context.Context, id SessionID) (bool, error)
+
+	// Get loads the session with the given identifier.
+	// Returns ErrSessionNotFound when the session does not exist.
+	// Returns ErrSessionIDInvalid when the identifier is missing or malformed.
+	Get(ctx context.Context, id SessionID) (Session, error)
+
+	// Update stores the session.
+	// Returns ErrSessionNotFound when the session does not exist.
+	Update(ctx context.Context, session Session) error
+
+	// List returns the identifiers of all sessions.
+	List(ctx context.Context) ([]SessionID, error)
+
+	// AppendTranscript adds a turn to the end of the session transcript.
+	// Returns ErrSessionNotFound when the session does not exist.
+	AppendTranscript(ctx context.Context, id SessionID, entry TranscriptEntry) error
+
+	// GetTranscript returns the session transcript, oldest turn first.
+	// Returns ErrSessionNotFound when the session does not exist.
+	GetTranscript(ctx context.Context, id SessionID) ([]TranscriptEntry, error)
+}
+
+// NewSessionID generates a new session identifier.
+func NewSessionID() SessionID {
+	return SessionID(uuid.NewString())
+}
+
+// Validate checks whether the session identifier is non-empty and a valid UUID.
+func (id SessionID) Validate() error {
+	if id == "" {
+		return ErrSessionIDInvalid
+	}
+
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return ErrSessionIDInvalid
+	}
+
+	return nil
+}
+
+// StartSession creates a new session for the agent, seeded with the input working directory and conversation ID.
+func StartSession(ctx context.Context, repository SessionRepository, agentID AgentID, input ExecutionInput) (SessionID, error) {
+	id, err := repository.Create(ctx, Session{
+		AgentID:          agentID,
+		WorkingDirectory: input.WorkingDirectory,
+		ConversationID:   input.ConversationID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create session: %w", err)
+	}
+
+	return id, nil
+}
+
+// StartHandoffSession creates a session on the target agent that continues the work of the source session.
+// It keeps the source working directory and records the source in HandoffFrom.
+func StartHandoffSession(ctx context.Context, repository SessionRepository, source Session, agentID AgentID) (SessionID, error) {
+	sourceID := source.ID
+	id, err := repository.Create(ctx, Session{
+		AgentID:          agentID,
+		WorkingDirectory: source.WorkingDirectory,
+		HandoffFrom:      &sourceID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create handoff session: %w", err)
+	}
+
+	return id, nil
+}
+
+// SessionHead returns the execution of the latest successful turn of a session, which the next turn continues.
+// Returns EmptyExecutionID when the session has no successful turns.
+func SessionHead(ctx context.Context, repository SessionRepository, id SessionID) (ExecutionID, error) {
+	transcript, err := repository.GetTranscript(ctx, id)
+	if err != nil {
+		return EmptyExecutionID, fmt.Errorf("get session transcript: %w", err)
+	}
+
+	turns := successfulTurns(transcript)
+	if len(turns) == 0 {
+		return EmptyExecutionID, nil
+	}
+
+	return turns[len(turns)-1].ExecutionID, nil
+}
+
+// ContinueSession prepares the execution input for the next turn of a session.
+// It fills the working directory and conversation ID the input does not set from the session.
+// Returns ErrSessionAgentMismatch when the session belongs to another agent.
+func ContinueSession(ctx context.Context, repository SessionRepository, id SessionID, agentID AgentID, input ExecutionInput) (ExecutionInput, error) {
+	session, err := repository.Get(ctx, id)
+	if err != nil {
+		return input, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.AgentID != agentID {
+		return input, fmt.Errorf("%w: session %s belongs to agent %s", ErrSessionAgentMismatch, session.ID, session.AgentID)
+	}
+
+	if input.WorkingDirectory == nil {
+		input.WorkingDirectory = session.WorkingDirectory
+	}
+
+	if input.ConversationID == nil {
+		input.ConversationID = session.ConversationID
+	}
+
+	return input, nil
+}
+
+var (
+	// ErrSessionNotFound indicates the session does not exist in the repository.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionIDInvalid indicates the session identifier is missing or malformed.
+	ErrSessionIDInvalid = errors.New("session id invalid")
+
+	// ErrSessionAgentMismatch indicates the session belongs to a different agent than the execution.
+	ErrSessionAgentMismatch = errors.New("session agent mismatch")
+)
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const outputSchemaResource = "output-schema.json"
+
+var fencedBlockPattern = regexp.MustCompile("(?s)```[a-zA-Z]*[ \t]*\r?\n(.*?)```")
+
+// OutputSchema is a compiled JSON Schema used to validate structured execution output.
+type OutputSchema struct {
+	schema *jsonschema.Schema
+}
+
+// CompileOutputSchema parses and compiles a JSON Schema document.
+// Returns ErrExecutionOutputSchemaInvalid when the document is not a valid JSON Schema object.
+func CompileOutputSchema(document json.RawMessage) (*OutputSchema, error) {
+	if !json.Valid(document) || !strings.HasPrefix(strings.TrimSpace(string(document)), "{") {
+		return nil, fmt.Errorf("%w: schema must be a JSON object", ErrExecutionOutputSchemaInvalid)
+	}
+
+	parsed, err := jsonschema.UnmarshalJSON(bytes.NewReader(document))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecutionOutputSchemaInvalid, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(outputSchemaResource, parsed); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecutionOutputSchemaInvalid, err)
+	}
+
+	schema, err := compiler.Compile(outputSchemaResource)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecutionOutputSchemaInvalid, err)
+	}
+
+	return &OutputSchema{schema: schema}, nil
+}
+
+// Validate checks the structured output against the schema.
+// Returns ErrStructuredOutputInvalid when the output does not conform.
+func (schema *OutputSchema) Validate(output json.RawMessage) error {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(output))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStructuredOutputInvalid, err)
+	}
+
+	if err := schema.schema.Validate(instance); err != nil {
+		return fmt.Errorf("%w: %w", ErrStructuredOutputInvalid, err)
+	}
+
+	return nil
+}
+
+// ExtractStructuredOutput locates the JSON value in an agent response.
+// The whole response is tried first, then fenced code blocks from last to first, then the outermost object or array.
+// Returns ErrStructuredOutputNotFound when no JSON value is present.
+func ExtractStructuredOutput(response string) (json.RawMessage, error) {
+	candidates := []string{response}
+
+	blocks := fencedBlockPattern.FindAllStringSubmatch(response, -1)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		candidates = append(candidates, blocks[i][1])
+	}
+
+	for _, delimiters := range [][2]string{{"{", "}"}, {"[", "]"}} {
+		start := strings.Index(response, delimiters[0])
+		end := strings.LastIndex(response, delimiters[1])
+		if start >= 0 && end > start {
+			candidates = append(candidates, response[start:end+1])
+		}
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" || !json.Valid([]byte(candidate)) {
+			continue
+		}
+
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, []byte(candidate)); err != nil {
+			continue
+		}
+
+		return compacted.Bytes(), nil
+	}
+
+	return nil, ErrStructuredOutputNotFound
+}
+
+// StructuredOutputInstructions returns the prompt suffix asking the agent to answer with JSON matching the schema.
+// It is used for runtimes without native structured output support.
+func StructuredOutputInstructions(document json.RawMessage) string {
+	return "\n\nRespond with a single JSON value that conforms to the JSON Schema below. " +
+		"Output only the JSON value, without explanations or code fences.\n\n" +
+		"JSON Schema:\n" + string(document)
+}
+
+// StructuredOutputRepairPrompt returns the follow-up prompt asking the agent to correct output that failed validation.
+func StructuredOutputRepairPrompt(document json.RawMessage, err error) string {
+	return fmt.Sprintf("Your previous response did not satisfy the required JSON Schema: %s", err) +
+		StructuredOutputInstructions(document)
+}
+
+var (
+	// ErrExecutionOutputSchemaInvalid indicates the execution output schema is not a valid JSON Schema object.
+	ErrExecutionOutputSchemaInvalid = errors.New("execution output schema invalid")
+
+	// ErrStructuredOutputNotFound indicates the agent response does not contain a JSON value.
+	ErrStructuredOutputNotFound = errors.New("structured output not found")
+
+	// ErrStructuredOutputInvalid indicates the structured output does not conform to the output schema.
+	ErrStructuredOutputInvalid = errors.New("structured output invalid")
+)
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileOutputSchema(t *testing.T) {
+	t.Run("not an object", func(t *testing.T) {
+		_, err := CompileOutputSchema(json.RawMessage(`["type"]`))
+		require.ErrorIs(t, err, ErrExecutionOutputSchemaInvalid)
+	})
+
+	t.Run("malformed json", func(t *testing.T) {
+		_, err := CompileOutputSchema(json.RawMessage(`{"type":`))
+		require.ErrorIs(t, err, ErrExecutionOutputSchemaInvalid)
+	})
+
+	t.Run("validates output", func(t *testing.T) {
+		schema, err := CompileOutputSchema(json.RawMessage(`{
+			"type": "object",
+			"properties": {"score": {"type": "integer"}},
+			"required": ["score"]
+		}`))
+		require.NoError(t, err)
+
+		require.NoError(t, schema.Validate(json.RawMessage(`{"score": 3}`)))
+		require.ErrorIs(t, schema.Validate(json.RawMessage(`{"score": "high"}`)), ErrStructuredOutputInvalid)
+		require.ErrorIs(t, schema.Validate(json.RawMessage(`{}`)), ErrStructuredOutputInvalid)
+	})
+}
+
+func TestExtractStructuredOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{name: "plain json", response: " {\"a\": 1}\n", expected: `{"a":1}`},
+		{name: "fenced block", response: "Here you go:\n```json\n{\"a\": [1, 2]}\n```\nDone.", expected: `{"a":[1,2]}`},
+		{name: "last fenced block wins", response: "```\n{\"a\": 1}\n```\nFixed:\n```json\n{\"a\": 2}\n```", expected: `{"a":2}`},
+		{name: "embedded object", response: "The answer is {\"ok\": true} as requested.", expected: `{"ok":true}`},
+		{name: "embedded array", response: "Result: [1, 2, 3].", expected: `[1,2,3]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := ExtractStructuredOutput(tt.response)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(output))
+		})
+	}
+
+	t.Run("no json", func(t *testing.T) {
+		_, err := ExtractStructuredOutput("I could not do that.")
+		require.ErrorIs(t, err, ErrStructuredOutputNotFound)
+	})
+}
+
+func TestStructuredOutputRepairPrompt(t *testing.T) {
+	prompt := StructuredOutputRepairPrompt(json.RawMessage(`{"type":"object"}`), errors.New("missing property"))
+	assert.Contains(t, prompt, "missing property")
+	assert.Contains(t, prompt, `{"type":"object"}`)
+}
@@ -1,17 +1,128 @@
 package agent
 
-import "time"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// TurnID identifies a single user-to-agent exchange in the store.
 type TurnID string
 
+// TurnRequest is the user side of a turn.
 type TurnRequest struct {
+	// SessionID is the session the turn belongs to.
+	SessionID SessionID `json:"sessionId"`
+
+	// ExecutionID is the execution that processes the turn.
+	ExecutionID ExecutionID `json:"executionId"`
+
+	// Prompt is the user input sent to the agent.
+	Prompt string `json:"prompt"`
+
+	// Attachments lists files supplied with the prompt.
+	Attachments []ExecutionInputAttachment `json:"attachments,omitempty"`
 }
 
+// TurnResponse is the agent side of a turn.
 type TurnResponse struct {
+	// AgentID is the agent that answered the turn.
+	AgentID AgentID `json:"agentId,omitempty"`
+
+	// ConversationID is the runtime conversation the turn ran in.
+	ConversationID ConversationID `json:"conversationId,omitempty"`
+
+	// Response carries the final agent response text.
+	Response string `json:"response"`
+
+	// Structured carries the validated JSON value when the execution requested structured output.
+	Structured json.RawMessage `json:"structured,omitempty"`
 }
 
+// TurnStatus tracks lifecycle timestamps and state for a turn.
 type TurnStatus struct {
-	CreatedAt  time.Duration
-	UpdatedAt  time.Duration
-	FinishedAt time.Duration
+	// CreatedAt is the timestamp when the turn was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is the timestamp of the most recent status update.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// FinishedAt is the timestamp when the turn finished.
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// State is the state of the turn's execution.
+	State ExecutionState `json:"state"`
+
+	// Error carries the failure message when the turn failed.
+	Error *string `json:"error,omitempty"`
+}
+
+// TurnRepository provides access to turn handles in a store.
+type TurnRepository interface {
+	// Create persists a new turn and returns its identifier.
+	// Returns ErrSessionIDInvalid when the request session is missing or malformed.
+	// Returns ErrExecutionIDInvalid when the request execution is missing or malformed.
+	Create(ctx context.Context, request TurnRequest) (TurnID, error)
+
+	// Get loads the turn handle for the given identifier.
+	// Returns ErrTurnNotFound when the turn does not exist.
+	// Returns ErrTurnIDInvalid when the identifier is missing or malformed.
+	Get(ctx context.Context, id TurnID) (Turn, error)
+
+	// List returns the identifiers of all turns.
+	List(ctx context.Context) ([]TurnID, error)
 }
+
+// Turn encapsulates per-turn state accessors and updates.
+type Turn interface {
+	// GetRequest returns the stored request for the turn.
+	// Returns ErrTurnNotFound when the turn does not exist.
+	GetRequest(ctx context.Context) (TurnRequest, error)
+
+	// GetResponse returns the stored response for the turn.
+	// Returns ErrTurnNoResponse when the turn has no response yet.
+	GetResponse(ctx context.Context) (TurnResponse, error)
+
+	// SetResponse stores the response and marks the turn as succeeded.
+	SetResponse(ctx context.Context, response TurnResponse) error
+
+	// GetStatus returns the lifecycle status for the turn.
+	// Returns ErrTurnNotFound when the turn does not exist.
+	GetStatus(ctx context.Context) (TurnStatus, error)
+
+	// UpdateStatus stores the lifecycle status for the turn.
+	// Returns ErrTurnNotFound when the turn does not exist.
+	UpdateStatus(ctx context.Context, status TurnStatus) error
+}
+
+// NewTurnID generates a new turn identifier.
+func NewTurnID() TurnID {
+	return TurnID(uuid.NewString())
+}
+
+// Validate checks whether the turn identifier is non-empty and a valid UUID.
+func (id TurnID) Validate() error {
+	if id == "" {
+		return ErrTurnIDInvalid
+	}
+
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return ErrTurnIDInvalid
+	}
+
+	return nil
+}
+
+var (
+	// ErrTurnNotFound indicates the turn does not exist in the repository.
+	ErrTurnNotFound = errors.New("turn not found")
+
+	// ErrTurnNoResponse indicates the turn exists but has no stored response yet.
+	ErrTurnNoResponse = errors.New("turn response not found")
+
+	// ErrTurnIDInvalid indicates the turn identifier is missing or malformed.
+	ErrTurnIDInvalid = errors.New("turn id invalid")
+)
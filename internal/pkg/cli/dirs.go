@@ -1,11 +1,14 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 )
 
 func ResolveRuntimeLogDir() (string, error) {
@@ -26,3 +29,75 @@ func ResolveRuntimeLogDir() (string, error) {
 
 	return abs, nil
 }
+
+func ResolveRuntimePluginDir() (string, error) {
+	dir := os.Getenv("BRIEFKIT_RUNTIME_PLUGIN_DIR")
+	if dir == "" {
+		dir = "~/.orbiqd/briefkit/plugins/"
+	}
+
+	expanded, err := homedir.Expand(dir)
+	if err != nil {
+		return "", fmt.Errorf("expand runtime plugin dir: %w", err)
+	}
+
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", fmt.Errorf("resolve absolute runtime plugin dir: %w", err)
+	}
+
+	return abs, nil
+}
+
+// CreateRuntimeLogDir creates the log directory of one runtime attempt of the execution, named after its start time.
+// An attempt started within the same second as an earlier one gets a numbered directory, so it never overwrites its logs.
+func CreateRuntimeLogDir(logDir string, kind agent.RuntimeKind, id agent.ExecutionID, startedAt time.Time) (string, error) {
+	executionLogDir := filepath.Join(logDir, string(kind), string(id))
+	if err := os.MkdirAll(executionLogDir, 0755); err != nil {
+		return "", fmt.Errorf("create runtime log directory: %w", err)
+	}
+
+	name := startedAt.Format("2006-01-02_15-04-05")
+	for attempt := 1; ; attempt++ {
+		attemptLogDir := filepath.Join(executionLogDir, name)
+		if attempt > 1 {
+			attemptLogDir = fmt.Sprintf("%s_%d", attemptLogDir, attempt)
+		}
+
+		err := os.Mkdir(attemptLogDir, 0755)
+		if err == nil {
+			return attemptLogDir, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("create runtime log directory: %w", err)
+		}
+	}
+}
+
+// RemoveRuntimeLogs deletes the runtime logs of the execution for every runtime kind.
+func RemoveRuntimeLogs(id agent.ExecutionID) error {
+	logDir, err := ResolveRuntimeLogDir()
+	if err != nil {
+		return err
+	}
+
+	kinds, err := os.ReadDir(logDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read runtime log dir: %w", err)
+	}
+
+	for _, kind := range kinds {
+		if !kind.IsDir() {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(logDir, kind.Name(), string(id))); err != nil {
+			return fmt.Errorf("remove runtime logs: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRuntimeLogDir(t *testing.T) {
+	logDir := t.TempDir()
+	id := agent.NewExecutionID()
+	startedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local)
+
+	first, err := CreateRuntimeLogDir(logDir, "codex", id, startedAt)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(logDir, "codex", string(id), "2025-01-02_03-04-05"), first)
+
+	second, err := CreateRuntimeLogDir(logDir, "codex", id, startedAt.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, first+"_2", second)
+	assert.DirExists(t, second)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// ResolveRuntimePluginTimeout returns the call timeout configured for the runtime plugin of the kind.
+// BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT_<KIND> takes precedence over BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT, and zero means the plugin default.
+func ResolveRuntimePluginTimeout(kind agent.RuntimeKind) (time.Duration, error) {
+	for _, name := range []string{"BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT_" + environmentSuffix(kind), "BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return 0, fmt.Errorf("parse %s: invalid duration %q", name, value)
+		}
+
+		return timeout, nil
+	}
+
+	return 0, nil
+}
+
+// environmentSuffix turns the runtime kind into an environment variable suffix, e.g. my-agent into MY_AGENT.
+func environmentSuffix(kind agent.RuntimeKind) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, string(kind))
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRuntimePluginTimeout(t *testing.T) {
+	timeout, err := ResolveRuntimePluginTimeout("my-agent")
+	require.NoError(t, err)
+	assert.Zero(t, timeout)
+
+	t.Setenv("BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT", "10s")
+	timeout, err = ResolveRuntimePluginTimeout("my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, timeout)
+
+	t.Setenv("BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT_MY_AGENT", "2m")
+	timeout, err = ResolveRuntimePluginTimeout("my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, timeout)
+
+	t.Setenv("BRIEFKIT_RUNTIME_PLUGIN_TIMEOUT_MY_AGENT", "soon")
+	_, err = ResolveRuntimePluginTimeout("my-agent")
+	require.Error(t, err)
+}
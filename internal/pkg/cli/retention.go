@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// RetentionConfig is the retention policy the runner applies to finished executions after each run.
+type RetentionConfig struct {
+	MaxAge   time.Duration `help:"Delete finished executions created longer ago than the age, e.g. 720h. Zero disables expiry by age." env:"BRIEFKIT_RETENTION_MAX_AGE"`
+	KeepLast int           `help:"Keep the number of most recent finished executions whatever their age. Zero disables the limit." env:"BRIEFKIT_RETENTION_KEEP_LAST"`
+}
+
+// Policy returns the configured retention policy and whether retention is enabled.
+func (config RetentionConfig) Policy() (agent.RetentionPolicy, bool) {
+	policy := agent.RetentionPolicy{
+		MaxAge:   config.MaxAge,
+		KeepLast: config.KeepLast,
+	}
+
+	return policy, config.MaxAge > 0 || config.KeepLast > 0
+}
+
+// PruneExecutions deletes the executions with their runtime logs and returns the identifiers it deleted.
+// Executions already deleted by someone else, or started again since they were selected, are skipped.
+func PruneExecutions(ctx context.Context, repository agent.ExecutionRepository, ids []agent.ExecutionID) ([]agent.ExecutionID, error) {
+	deleted := make([]agent.ExecutionID, 0, len(ids))
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		if err := DeleteExecution(ctx, repository, id); err != nil {
+			if errors.Is(err, agent.ErrExecutionNotFound) || errors.Is(err, agent.ErrExecutionActive) {
+				continue
+			}
+			return deleted, err
+		}
+
+		deleted = append(deleted, id)
+	}
+
+	return deleted, nil
+}
+
+// DeleteExecution deletes the execution and then its runtime logs.
+func DeleteExecution(ctx context.Context, repository agent.ExecutionRepository, id agent.ExecutionID, options ...agent.ExecutionDeleteOption) error {
+	if err := repository.Delete(ctx, id, options...); err != nil {
+		return fmt.Errorf("delete execution %s: %w", id, err)
+	}
+
+	if err := RemoveRuntimeLogs(id); err != nil {
+		return fmt.Errorf("delete execution %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionConfig_Policy(t *testing.T) {
+	_, enabled := RetentionConfig{}.Policy()
+	assert.False(t, enabled)
+
+	policy, enabled := RetentionConfig{MaxAge: time.Hour, KeepLast: 5}.Policy()
+	assert.True(t, enabled)
+	assert.Equal(t, agent.RetentionPolicy{MaxAge: time.Hour, KeepLast: 5}, policy)
+}
+
+func TestPruneExecutions(t *testing.T) {
+	logDir := t.TempDir()
+	t.Setenv("BRIEFKIT_RUNTIME_LOG_DIR", logDir)
+
+	store, err := OpenStoreFromConfig(StoreConfig{Driver: StoreDriverFS, StatePath: t.TempDir(), AgentConfigPath: t.TempDir()})
+	require.NoError(t, err)
+	repository := store.Executions
+	ctx := context.Background()
+
+	workingDir := "/app"
+	input := agent.ExecutionInput{Prompt: "test prompt", Timeout: utils.Duration(time.Minute), WorkingDirectory: &workingDir}
+
+	pruned, err := repository.Create(ctx, input, agent.Config{Runtime: agent.ConfigRuntime{Kind: "codex"}})
+	require.NoError(t, err)
+
+	kept, err := repository.Create(ctx, input, agent.Config{Runtime: agent.ConfigRuntime{Kind: "codex"}})
+	require.NoError(t, err)
+
+	for _, id := range []agent.ExecutionID{pruned, kept} {
+		require.NoError(t, os.MkdirAll(filepath.Join(logDir, "codex", string(id), "2025-01-01_00-00-00"), 0755))
+	}
+
+	execution, err := repository.Get(ctx, pruned)
+	require.NoError(t, err)
+	status, err := execution.GetStatus(ctx)
+	require.NoError(t, err)
+	status.State = agent.ExecutionSucceeded
+	require.NoError(t, execution.UpdateStatus(ctx, status))
+
+	// The created execution is about to be run, so it is skipped like a deleted one.
+	deleted, err := PruneExecutions(ctx, repository, []agent.ExecutionID{pruned, kept, agent.NewExecutionID()})
+	require.NoError(t, err)
+	assert.Equal(t, []agent.ExecutionID{pruned}, deleted)
+
+	exists, err := repository.Exists(ctx, pruned)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.NoDirExists(t, filepath.Join(logDir, "codex", string(pruned)))
+
+	exists, err = repository.Exists(ctx, kept)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.DirExists(t, filepath.Join(logDir, "codex", string(kept)))
+}
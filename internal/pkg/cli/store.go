@@ -1,34 +1,163 @@
 package cli
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 	fsstore "github.com/orbiqd/orbiqd-briefkit/internal/pkg/store/fs"
+	sqlitestore "github.com/orbiqd/orbiqd-briefkit/internal/pkg/store/sqlite"
 	"github.com/spf13/afero"
 )
 
 type StoreConfig struct {
+	Driver          string `help:"Store for executions and agent configs (fs or sqlite)." default:"fs" enum:"fs,sqlite" env:"BRIEFKIT_STORE_DRIVER"`
 	StatePath       string `short:"s" help:"Base directory for runtime state." default:"~/.orbiqd/briefkit/state" env:"BRIEFKIT_STATE_PATH"`
 	AgentConfigPath string `help:"Directory with agent definition files." default:"~/.orbiqd/briefkit/agents" env:"BRIEFKIT_AGENT_CONFIG_PATH"`
+	SQLitePath      string `name:"sqlite-path" help:"SQLite database file used by the sqlite driver. Defaults to briefkit.db in the state path." env:"BRIEFKIT_SQLITE_PATH"`
 }
 
-const executionRepositoryDirName = "executions"
+const (
+	// StoreDriverFS keeps executions and agent configs as files.
+	StoreDriverFS = "fs"
 
-func CreateExecutionRepositoryFromConfig(config StoreConfig) (agent.ExecutionRepository, error) {
+	// StoreDriverSQLite keeps executions and agent configs in a SQLite database.
+	StoreDriverSQLite = "sqlite"
+)
+
+const (
+	sqliteDatabaseFileName     = "briefkit.db"
+	executionRepositoryDirName = "executions"
+	sessionRepositoryDirName   = "sessions"
+	turnRepositoryDirName      = "turns"
+)
+
+func resolveStatePath(config StoreConfig) (string, error) {
 	expanded, err := homedir.Expand(config.StatePath)
 	if err != nil {
-		return nil, fmt.Errorf("expand state path: %w", err)
+		return "", fmt.Errorf("expand state path: %w", err)
 	}
 
 	cleaned := filepath.Clean(expanded)
 	if !filepath.IsAbs(cleaned) {
-		return nil, fmt.Errorf("state path must be absolute: %s", config.StatePath)
+		return "", fmt.Errorf("state path must be absolute: %s", config.StatePath)
+	}
+
+	return cleaned, nil
+}
+
+// ResolveSQLitePath returns the absolute path of the SQLite database of the store.
+func ResolveSQLitePath(config StoreConfig) (string, error) {
+	if config.SQLitePath == "" {
+		statePath, err := resolveStatePath(config)
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(statePath, sqliteDatabaseFileName), nil
+	}
+
+	expanded, err := homedir.Expand(config.SQLitePath)
+	if err != nil {
+		return "", fmt.Errorf("expand sqlite path: %w", err)
+	}
+
+	cleaned := filepath.Clean(expanded)
+	if !filepath.IsAbs(cleaned) {
+		return "", fmt.Errorf("sqlite path must be absolute: %s", config.SQLitePath)
+	}
+
+	return cleaned, nil
+}
+
+// ExportStoreConfig sets the store environment variables so spawned runners use the same store.
+func ExportStoreConfig(config StoreConfig) error {
+	variables := map[string]string{
+		"BRIEFKIT_STORE_DRIVER":      config.Driver,
+		"BRIEFKIT_STATE_PATH":        config.StatePath,
+		"BRIEFKIT_AGENT_CONFIG_PATH": config.AgentConfigPath,
+		"BRIEFKIT_SQLITE_PATH":       config.SQLitePath,
 	}
 
-	repositoryPath := filepath.Join(cleaned, executionRepositoryDirName)
+	for name, value := range variables {
+		if value == "" {
+			continue
+		}
+
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("export %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Store holds the execution and agent config repositories of the store driver.
+// With the sqlite driver both share one database handle, which Close releases.
+type Store struct {
+	// Executions is the execution repository of the store.
+	Executions agent.ExecutionRepository
+
+	// Configs is the agent config repository of the store.
+	Configs agent.ConfigRepository
+
+	db *sql.DB
+}
+
+// Close releases the database of the sqlite driver. It does nothing for the fs driver.
+func (store *Store) Close() error {
+	if store.db == nil {
+		return nil
+	}
+
+	return store.db.Close()
+}
+
+// OpenStoreFromConfig opens the execution and agent config repositories of the configured driver.
+// The sqlite driver opens and migrates the database once for both; close the store when done.
+func OpenStoreFromConfig(config StoreConfig) (*Store, error) {
+	if config.Driver == StoreDriverSQLite {
+		path, err := ResolveSQLitePath(config)
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := sqlitestore.Open(context.Background(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Store{
+			Executions: sqlitestore.NewExecutionRepository(db),
+			Configs:    sqlitestore.NewConfigRepository(db),
+			db:         db,
+		}, nil
+	}
+
+	executions, err := createFSExecutionRepository(config)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := createFSConfigRepository(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{Executions: executions, Configs: configs}, nil
+}
+
+func createFSExecutionRepository(config StoreConfig) (agent.ExecutionRepository, error) {
+	statePath, err := resolveStatePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryPath := filepath.Join(statePath, executionRepositoryDirName)
 	fs := afero.NewOsFs()
 	repository, err := fsstore.NewExecutionRepository(repositoryPath, fs)
 	if err != nil {
@@ -38,7 +167,39 @@ func CreateExecutionRepositoryFromConfig(config StoreConfig) (agent.ExecutionRep
 	return repository, nil
 }
 
-func CreateConfigRepositoryFromConfig(config StoreConfig) (agent.ConfigRepository, error) {
+func CreateSessionRepositoryFromConfig(config StoreConfig) (agent.SessionRepository, error) {
+	statePath, err := resolveStatePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryPath := filepath.Join(statePath, sessionRepositoryDirName)
+	fs := afero.NewOsFs()
+	repository, err := fsstore.NewSessionRepository(repositoryPath, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func CreateTurnRepositoryFromConfig(config StoreConfig) (agent.TurnRepository, error) {
+	statePath, err := resolveStatePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryPath := filepath.Join(statePath, turnRepositoryDirName)
+	fs := afero.NewOsFs()
+	repository, err := fsstore.NewTurnRepository(repositoryPath, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func createFSConfigRepository(config StoreConfig) (agent.ConfigRepository, error) {
 	expanded, err := homedir.Expand(config.AgentConfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("expand agent config path: %w", err)
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	fsstore "github.com/orbiqd/orbiqd-briefkit/internal/pkg/store/fs"
+	sqlitestore "github.com/orbiqd/orbiqd-briefkit/internal/pkg/store/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSQLitePath(t *testing.T) {
+	path, err := ResolveSQLitePath(StoreConfig{StatePath: "/var/lib/briefkit"})
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/briefkit/briefkit.db", path)
+
+	path, err = ResolveSQLitePath(StoreConfig{StatePath: "/var/lib/briefkit", SQLitePath: "/data/state.db"})
+	require.NoError(t, err)
+	assert.Equal(t, "/data/state.db", path)
+
+	_, err = ResolveSQLitePath(StoreConfig{SQLitePath: "state.db"})
+	require.Error(t, err)
+}
+
+func TestOpenStoreFromConfig(t *testing.T) {
+	statePath := t.TempDir()
+	agentConfigPath := t.TempDir()
+
+	t.Run("fs driver", func(t *testing.T) {
+		store, err := OpenStoreFromConfig(StoreConfig{Driver: StoreDriverFS, StatePath: statePath, AgentConfigPath: agentConfigPath})
+		require.NoError(t, err)
+		assert.IsType(t, &fsstore.Repository{}, store.Executions)
+		assert.IsType(t, &fsstore.ConfigRepository{}, store.Configs)
+		assert.NoError(t, store.Close())
+	})
+
+	t.Run("sqlite driver", func(t *testing.T) {
+		store, err := OpenStoreFromConfig(StoreConfig{Driver: StoreDriverSQLite, StatePath: statePath, AgentConfigPath: agentConfigPath})
+		require.NoError(t, err)
+		assert.IsType(t, &sqlitestore.Repository{}, store.Executions)
+		assert.IsType(t, &sqlitestore.ConfigRepository{}, store.Configs)
+		assert.FileExists(t, filepath.Join(statePath, "briefkit.db"))
+
+		// Both repositories share the database, so closing the store closes it for both.
+		require.NoError(t, store.Close())
+		_, err = store.Configs.List(context.Background())
+		assert.Error(t, err)
+		_, err = store.Executions.Find(context.Background())
+		assert.Error(t, err)
+	})
+}
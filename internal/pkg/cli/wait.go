@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// attemptGrace lets the runner record the final status of an attempt that used the full execution timeout.
+const attemptGrace = 30 * time.Second
+
+// ExecutionOutcome is where a waited execution ended up.
+type ExecutionOutcome struct {
+	// ID is the execution that ended the wait, the last fallback execution when the execution failed over.
+	ID agent.ExecutionID
+
+	// Status is the final status: succeeded, failed, or deferred with ResetAt set.
+	Status agent.ExecutionStatus
+
+	// Result is set when the execution succeeded.
+	Result *agent.ExecutionResult
+}
+
+// WaitOption configures WaitForExecution.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	interval      time.Duration
+	onEvents      func(agent.ExecutionID, []agent.RuntimeEventEnvelope)
+	onStateChange func(agent.ExecutionID, agent.ExecutionState)
+	onFallback    func(agent.ExecutionID, agent.ExecutionID)
+}
+
+// WithPollInterval sets how often the execution status is read. Defaults to 500ms.
+func WithPollInterval(interval time.Duration) WaitOption {
+	return func(options *waitOptions) {
+		options.interval = interval
+	}
+}
+
+// WithEventHandler passes the runtime events recorded since the previous poll to handler, in order.
+// Every event recorded before the execution finished is passed before the wait returns.
+func WithEventHandler(handler func(id agent.ExecutionID, events []agent.RuntimeEventEnvelope)) WaitOption {
+	return func(options *waitOptions) {
+		options.onEvents = handler
+	}
+}
+
+// WithStateHandler calls handler whenever the state of the waited execution changes.
+func WithStateHandler(handler func(id agent.ExecutionID, state agent.ExecutionState)) WaitOption {
+	return func(options *waitOptions) {
+		options.onStateChange = handler
+	}
+}
+
+// WithFallbackHandler calls handler before the wait follows a failed execution to its fallback execution.
+func WithFallbackHandler(handler func(failed agent.ExecutionID, fallback agent.ExecutionID)) WaitOption {
+	return func(options *waitOptions) {
+		options.onFallback = handler
+	}
+}
+
+// WaitForExecution polls the execution until it succeeds, fails without a fallback, or is deferred by a rate limit,
+// following fallback executions on the way. There is no overall deadline: retries, backoffs and fallbacks may take far
+// longer than the execution timeout, so the timeout only bounds how long a single attempt may leave the status unchanged.
+func WaitForExecution(ctx context.Context, repository agent.ExecutionRepository, id agent.ExecutionID, options ...WaitOption) (ExecutionOutcome, error) {
+	waitOptions := waitOptions{interval: 500 * time.Millisecond}
+	for _, option := range options {
+		option(&waitOptions)
+	}
+
+	ticker := time.NewTicker(waitOptions.interval)
+	defer ticker.Stop()
+
+	execution, guard, err := watchExecution(ctx, repository, id)
+	if err != nil {
+		return ExecutionOutcome{}, err
+	}
+
+	offset := 0
+	var lastState agent.ExecutionState
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ExecutionOutcome{}, fmt.Errorf("wait for completion: %w", ctx.Err())
+		case <-ticker.C:
+		}
+
+		// Read the status first so that every event recorded before the execution finished is passed on.
+		status, err := execution.GetStatus(ctx)
+		if err != nil {
+			slog.Warn("Failed to get execution status.", slog.String("executionId", string(id)), slog.String("error", err.Error()))
+			continue
+		}
+
+		if waitOptions.onEvents != nil {
+			events, err := execution.GetEvents(ctx, offset)
+			if err != nil {
+				slog.Warn("Failed to get execution events.", slog.String("executionId", string(id)), slog.String("error", err.Error()))
+				continue
+			}
+			offset += len(events)
+
+			if len(events) > 0 {
+				waitOptions.onEvents(id, events)
+			}
+		}
+
+		if status.State != lastState {
+			lastState = status.State
+			if waitOptions.onStateChange != nil {
+				waitOptions.onStateChange(id, status.State)
+			}
+		}
+
+		if err := guard.check(status); err != nil {
+			return ExecutionOutcome{}, err
+		}
+
+		switch {
+		case status.State == agent.ExecutionDeferred && status.ResetAt != nil:
+			return ExecutionOutcome{ID: id, Status: status}, nil
+
+		case status.State == agent.ExecutionFailed && status.FallbackExecutionID != nil:
+			if waitOptions.onFallback != nil {
+				waitOptions.onFallback(id, *status.FallbackExecutionID)
+			}
+
+			id = *status.FallbackExecutionID
+			execution, guard, err = watchExecution(ctx, repository, id)
+			if err != nil {
+				return ExecutionOutcome{}, fmt.Errorf("follow fallback execution: %w", err)
+			}
+			offset = 0
+			lastState = ""
+
+		case status.State == agent.ExecutionSucceeded:
+			result, err := execution.GetResult(ctx)
+			if err != nil {
+				return ExecutionOutcome{}, fmt.Errorf("get result: %w", err)
+			}
+
+			return ExecutionOutcome{ID: id, Status: status, Result: &result}, nil
+
+		case status.State == agent.ExecutionFailed:
+			return ExecutionOutcome{ID: id, Status: status}, nil
+		}
+	}
+}
+
+// watchExecution opens the execution and the guard of its attempts.
+func watchExecution(ctx context.Context, repository agent.ExecutionRepository, id agent.ExecutionID) (agent.Execution, *attemptGuard, error) {
+	execution, err := repository.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get execution %s: %w", id, err)
+	}
+
+	guard, err := newAttemptGuard(ctx, execution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return execution, guard, nil
+}
+
+// attemptGuard stops waiting for an execution whose status stopped changing.
+// The runner updates the status on every attempt, backoff and deferral, so the guard bounds a single attempt
+// instead of the whole execution, which may retry many times.
+type attemptGuard struct {
+	limit     time.Duration
+	revision  int64
+	changedAt time.Time
+}
+
+// newAttemptGuard creates a guard allowing one attempt of the execution plus the longest backoff before it.
+func newAttemptGuard(ctx context.Context, execution agent.Execution) (*attemptGuard, error) {
+	input, err := execution.GetInput(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get execution input: %w", err)
+	}
+
+	agentConfig, err := execution.GetAgentConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get agent config: %w", err)
+	}
+
+	return &attemptGuard{
+		limit:     time.Duration(input.Timeout) + agentConfig.Retry.MaxDelay() + attemptGrace,
+		revision:  -1,
+		changedAt: time.Now(),
+	}, nil
+}
+
+// check records the status and returns an error when it has not changed for longer than an attempt may take.
+func (guard *attemptGuard) check(status agent.ExecutionStatus) error {
+	now := time.Now()
+	if status.Revision != guard.revision {
+		guard.revision = status.Revision
+		guard.changedAt = now
+		return nil
+	}
+
+	if now.Sub(guard.changedAt) > guard.limit {
+		return fmt.Errorf("wait for completion: execution status unchanged for %s while %s", guard.limit, status.State)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createWaitExecution stores an execution and moves it to the given status.
+func createWaitExecution(t *testing.T, repository agent.ExecutionRepository, update func(status *agent.ExecutionStatus)) (agent.ExecutionID, agent.Execution) {
+	t.Helper()
+	ctx := context.Background()
+
+	input := agent.ExecutionInput{Prompt: "test prompt", Timeout: utils.Duration(time.Minute)}
+	id, err := repository.Create(ctx, input, agent.Config{Runtime: agent.ConfigRuntime{Kind: "codex"}})
+	require.NoError(t, err)
+
+	execution, err := repository.Get(ctx, id)
+	require.NoError(t, err)
+
+	status, err := execution.GetStatus(ctx)
+	require.NoError(t, err)
+	update(&status)
+	require.NoError(t, execution.UpdateStatus(ctx, status))
+
+	return id, execution
+}
+
+func TestWaitForExecution(t *testing.T) {
+	store, err := OpenStoreFromConfig(StoreConfig{Driver: StoreDriverFS, StatePath: t.TempDir(), AgentConfigPath: t.TempDir()})
+	require.NoError(t, err)
+	repository := store.Executions
+	ctx := context.Background()
+
+	t.Run("succeeded", func(t *testing.T) {
+		id, execution := createWaitExecution(t, repository, func(status *agent.ExecutionStatus) {
+			status.State = agent.ExecutionSucceeded
+		})
+		require.NoError(t, execution.SetResult(ctx, agent.ExecutionResult{Response: "done"}))
+
+		envelope, err := agent.NewRuntimeEventEnvelope(agent.RuntimeMessageEvent{Text: "done"})
+		require.NoError(t, err)
+		require.NoError(t, execution.AppendEvent(ctx, envelope))
+
+		var events []agent.RuntimeEventEnvelope
+		var states []agent.ExecutionState
+		outcome, err := WaitForExecution(ctx, repository, id,
+			WithPollInterval(time.Millisecond),
+			WithEventHandler(func(_ agent.ExecutionID, batch []agent.RuntimeEventEnvelope) {
+				events = append(events, batch...)
+			}),
+			WithStateHandler(func(_ agent.ExecutionID, state agent.ExecutionState) {
+				states = append(states, state)
+			}),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, id, outcome.ID)
+		assert.Equal(t, agent.ExecutionSucceeded, outcome.Status.State)
+		require.NotNil(t, outcome.Result)
+		assert.Equal(t, "done", outcome.Result.Response)
+		assert.Len(t, events, 1)
+		assert.Equal(t, []agent.ExecutionState{agent.ExecutionSucceeded}, states)
+	})
+
+	t.Run("follows the fallback execution", func(t *testing.T) {
+		fallbackID, fallback := createWaitExecution(t, repository, func(status *agent.ExecutionStatus) {
+			status.State = agent.ExecutionSucceeded
+		})
+		require.NoError(t, fallback.SetResult(ctx, agent.ExecutionResult{Response: "fallback"}))
+
+		id, _ := createWaitExecution(t, repository, func(status *agent.ExecutionStatus) {
+			status.State = agent.ExecutionFailed
+			status.FallbackExecutionID = &fallbackID
+		})
+
+		var followed []agent.ExecutionID
+		outcome, err := WaitForExecution(ctx, repository, id,
+			WithPollInterval(time.Millisecond),
+			WithFallbackHandler(func(failed agent.ExecutionID, fallback agent.ExecutionID) {
+				followed = append(followed, failed, fallback)
+			}),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, []agent.ExecutionID{id, fallbackID}, followed)
+		assert.Equal(t, fallbackID, outcome.ID)
+		require.NotNil(t, outcome.Result)
+		assert.Equal(t, "fallback", outcome.Result.Response)
+	})
+
+	t.Run("deferred", func(t *testing.T) {
+		resetAt := time.Now().Add(time.Hour).UTC()
+		id, _ := createWaitExecution(t, repository, func(status *agent.ExecutionStatus) {
+			status.State = agent.ExecutionDeferred
+			status.ResetAt = &resetAt
+		})
+
+		outcome, err := WaitForExecution(ctx, repository, id, WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+
+		assert.Equal(t, agent.ExecutionDeferred, outcome.Status.State)
+		require.NotNil(t, outcome.Status.ResetAt)
+		assert.True(t, resetAt.Equal(*outcome.Status.ResetAt))
+		assert.Nil(t, outcome.Result)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		message := "boom"
+		id, _ := createWaitExecution(t, repository, func(status *agent.ExecutionStatus) {
+			status.State = agent.ExecutionFailed
+			status.Error = &message
+		})
+
+		outcome, err := WaitForExecution(ctx, repository, id, WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+
+		assert.Equal(t, agent.ExecutionFailed, outcome.Status.State)
+		require.NotNil(t, outcome.Status.Error)
+		assert.Equal(t, message, *outcome.Status.Error)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		id, _ := createWaitExecution(t, repository, func(status *agent.ExecutionStatus) {
+			status.State = agent.ExecutionRunning
+		})
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		_, err := WaitForExecution(cancelCtx, repository, id, WithPollInterval(time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,83 @@
+package process
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// FilterEnvironment keeps the KEY=value entries whose names match an allow pattern and no deny pattern.
+// An empty allow list keeps every entry. Patterns use path.Match syntax, for example "LC_*".
+func FilterEnvironment(environ []string, allow []string, deny []string) []string {
+	filtered := make([]string, 0, len(environ))
+
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+
+		if len(allow) > 0 && !matchesAny(name, allow) {
+			continue
+		}
+
+		if matchesAny(name, deny) {
+			continue
+		}
+
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
+
+// MergeEnvironment sets the given variables on top of the KEY=value entries.
+// Existing entries are replaced in place; new variables are appended in name order.
+func MergeEnvironment(environ []string, variables map[string]string) []string {
+	merged := make([]string, 0, len(environ)+len(variables))
+	seen := make(map[string]bool, len(variables))
+
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+
+		value, ok := variables[name]
+		if !ok {
+			merged = append(merged, entry)
+			continue
+		}
+
+		if !seen[name] {
+			merged = append(merged, name+"="+value)
+			seen[name] = true
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		if !seen[name] {
+			merged = append(merged, name+"="+variables[name])
+		}
+	}
+
+	return merged
+}
+
+// LookupEnvironment returns the value of the named variable in the KEY=value entries.
+// The last entry wins, matching os/exec.
+func LookupEnvironment(environ []string, name string) (string, bool) {
+	for i := len(environ) - 1; i >= 0; i-- {
+		key, value, _ := strings.Cut(environ[i], "=")
+		if key == name {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterEnvironment(t *testing.T) {
+	environ := []string{"PATH=/bin", "HOME=/home/me", "AWS_SECRET=x", "LC_ALL=C", "EMPTY="}
+
+	assert.Equal(t, environ, FilterEnvironment(environ, nil, nil))
+	assert.Equal(t, []string{"PATH=/bin", "LC_ALL=C"}, FilterEnvironment(environ, []string{"PATH", "LC_*"}, nil))
+	assert.Equal(t, []string{"PATH=/bin", "HOME=/home/me", "LC_ALL=C", "EMPTY="}, FilterEnvironment(environ, nil, []string{"AWS_*"}))
+	assert.Equal(t, []string{"PATH=/bin"}, FilterEnvironment(environ, []string{"PATH", "AWS_*"}, []string{"AWS_*"}))
+}
+
+func TestMergeEnvironment(t *testing.T) {
+	environ := []string{"PATH=/bin", "HOME=/home/me", "HOME=/dup"}
+
+	merged := MergeEnvironment(environ, map[string]string{"HOME": "/srv/agent", "B": "2", "A": "1"})
+	assert.Equal(t, []string{"PATH=/bin", "HOME=/srv/agent", "A=1", "B=2"}, merged)
+}
+
+func TestLookupEnvironment(t *testing.T) {
+	environ := []string{"HOME=/a", "CODEX_HOME=/b", "HOME=/c"}
+
+	value, ok := LookupEnvironment(environ, "HOME")
+	assert.True(t, ok)
+	assert.Equal(t, "/c", value)
+
+	_, ok = LookupEnvironment(environ, "MISSING")
+	assert.False(t, ok)
+}
@@ -0,0 +1,43 @@
+package claude
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// toolActivity describes a Claude Code tool call as agent activity.
+func toolActivity(name string, input json.RawMessage, now time.Time) agent.RuntimeActivityEvent {
+	var fields map[string]any
+	_ = json.Unmarshal(input, &fields)
+
+	activity := agent.RuntimeActivityEvent{
+		Timestamp: now,
+		Activity:  agent.RuntimeActivityTool,
+		Name:      name,
+	}
+
+	switch name {
+	case "Bash":
+		activity.Activity = agent.RuntimeActivityCommand
+		activity.Detail = stringField(fields, "command")
+	case "Edit", "MultiEdit", "Write", "NotebookEdit":
+		activity.Activity = agent.RuntimeActivityEdit
+		activity.Detail = stringField(fields, "file_path", "notebook_path")
+	default:
+		activity.Detail = stringField(fields, "file_path", "path", "pattern", "url", "query", "description")
+	}
+
+	return activity
+}
+
+func stringField(fields map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
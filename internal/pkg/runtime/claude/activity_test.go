@@ -0,0 +1,39 @@
+package claude
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolActivity(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		tool     string
+		input    string
+		activity agent.RuntimeActivityKind
+		detail   string
+	}{
+		{name: "command", tool: "Bash", input: `{"command":"go test ./...","description":"Run tests"}`, activity: agent.RuntimeActivityCommand, detail: "go test ./..."},
+		{name: "edit", tool: "Edit", input: `{"file_path":"/repo/main.go","old_string":"a","new_string":"b"}`, activity: agent.RuntimeActivityEdit, detail: "/repo/main.go"},
+		{name: "write", tool: "Write", input: `{"file_path":"/repo/README.md","content":"x"}`, activity: agent.RuntimeActivityEdit, detail: "/repo/README.md"},
+		{name: "search", tool: "Grep", input: `{"pattern":"TODO"}`, activity: agent.RuntimeActivityTool, detail: "TODO"},
+		{name: "malformed input", tool: "Read", input: `[]`, activity: agent.RuntimeActivityTool},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity := toolActivity(tt.tool, json.RawMessage(tt.input), now)
+
+			assert.Equal(t, tt.activity, activity.Activity)
+			assert.Equal(t, tt.tool, activity.Name)
+			assert.Equal(t, tt.detail, activity.Detail)
+			assert.Equal(t, now, activity.Timestamp)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package claude
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
@@ -11,6 +13,7 @@ import (
 type arguments struct {
 	flags            map[string]bool
 	values           map[string]string
+	multiValues      map[string][]string
 	settingsOverride map[string]any
 }
 
@@ -18,6 +21,7 @@ func defaultArguments() *arguments {
 	return &arguments{
 		flags:            map[string]bool{},
 		values:           map[string]string{},
+		multiValues:      map[string][]string{},
 		settingsOverride: map[string]any{},
 	}
 }
@@ -36,6 +40,20 @@ func (a *arguments) SetValue(name string, value any) error {
 	return nil
 }
 
+func (a *arguments) AddValue(name string, value any) error {
+	valueStr, err := a.valueToString(value)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(a.multiValues[name], valueStr) {
+		return nil
+	}
+
+	a.multiValues[name] = append(a.multiValues[name], valueStr)
+	return nil
+}
+
 func (a *arguments) SetSettingsOverride(key string, value any) error {
 	a.settingsOverride[key] = value
 	return nil
@@ -65,12 +83,18 @@ func (a *arguments) valueToString(value any) (string, error) {
 func (a *arguments) ToList() []string {
 	var list []string
 
-	for flag := range a.flags {
+	for _, flag := range slices.Sorted(maps.Keys(a.flags)) {
 		list = append(list, fmt.Sprintf("--%s", flag))
 	}
 
-	for key, value := range a.values {
-		list = append(list, fmt.Sprintf("--%s=%s", key, value))
+	for _, key := range slices.Sorted(maps.Keys(a.values)) {
+		list = append(list, fmt.Sprintf("--%s=%s", key, a.values[key]))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(a.multiValues)) {
+		for _, value := range a.multiValues[key] {
+			list = append(list, fmt.Sprintf("--%s=%s", key, value))
+		}
 	}
 
 	if len(a.settingsOverride) > 0 {
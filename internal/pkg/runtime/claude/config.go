@@ -2,16 +2,69 @@ package claude
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/mcuadros/go-defaults"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 )
 
+// Config defines runtime options for Claude execution.
 type Config struct {
+	// PermissionMode selects how Claude handles tool permissions (default, acceptEdits, bypassPermissions, plan).
+	PermissionMode string `json:"permissionMode,omitempty"`
+
+	// AllowedTools lists tools Claude may use without asking for permission.
+	AllowedTools []string `json:"allowedTools,omitempty"`
+
+	// DisallowedTools lists tools Claude must not use.
+	DisallowedTools []string `json:"disallowedTools,omitempty"`
+
+	// AppendSystemPrompt is appended to the default Claude system prompt.
+	AppendSystemPrompt string `json:"appendSystemPrompt,omitempty"`
+
+	// MaxTurns limits the number of agentic turns in a single execution. Zero means no limit.
+	MaxTurns int `json:"maxTurns,omitempty"`
+
+	// AdditionalDirectories grants Claude access to directories outside the working directory.
+	AdditionalDirectories []string `json:"additionalDirectories,omitempty"`
+
+	// MCPConfig lists MCP server configuration files or JSON strings to load.
+	MCPConfig []string `json:"mcpConfig,omitempty"`
+
+	// StrictMCPConfig makes Claude use only the MCP servers from MCPConfig.
+	StrictMCPConfig bool `json:"strictMcpConfig,omitempty"`
+
+	// Settings is merged into the settings JSON passed with --settings.
+	Settings map[string]any `json:"settings,omitempty"`
 }
 
-func applyRuntimeConfigArguments(args *arguments, config agent.RuntimeConfig) error {
+var permissionModes = []string{"default", "acceptEdits", "bypassPermissions", "plan"}
+
+// Validate checks whether the Claude runtime configuration is well-formed.
+func (config Config) Validate() error {
+	if config.PermissionMode != "" && !slices.Contains(permissionModes, config.PermissionMode) {
+		return fmt.Errorf("%w: %s", ErrPermissionModeInvalid, config.PermissionMode)
+	}
+
+	if config.MaxTurns < 0 {
+		return ErrMaxTurnsInvalid
+	}
+
+	for _, list := range [][]string{config.AllowedTools, config.DisallowedTools, config.AdditionalDirectories, config.MCPConfig} {
+		for _, value := range list {
+			if strings.TrimSpace(value) == "" {
+				return ErrConfigValueEmpty
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeConfig(config agent.RuntimeConfig) (Config, error) {
 	var claudeConfig Config
 
 	switch typed := config.(type) {
@@ -26,22 +79,87 @@ func applyRuntimeConfigArguments(args *arguments, config agent.RuntimeConfig) er
 	default:
 		payload, err := json.Marshal(config)
 		if err != nil {
-			return fmt.Errorf("marshal claude runtime config: %w", err)
+			return Config{}, fmt.Errorf("marshal claude runtime config: %w", err)
 		}
 
 		if err := json.Unmarshal(payload, &claudeConfig); err != nil {
-			return fmt.Errorf("unmarshal claude runtime config: %w", err)
+			return Config{}, fmt.Errorf("unmarshal claude runtime config: %w", err)
 		}
 	}
 
 	defaults.SetDefaults(&claudeConfig)
 
+	return claudeConfig, nil
+}
+
+func applyRuntimeConfigArguments(args *arguments, config agent.RuntimeConfig) error {
+	claudeConfig, err := decodeConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := claudeConfig.Validate(); err != nil {
+		return err
+	}
+
+	if claudeConfig.PermissionMode != "" {
+		if err = args.SetValue("permission-mode", claudeConfig.PermissionMode); err != nil {
+			return fmt.Errorf("set permission mode: %w", err)
+		}
+	}
+
+	for _, tool := range claudeConfig.AllowedTools {
+		if err = args.AddValue("allowed-tools", tool); err != nil {
+			return fmt.Errorf("add allowed tool: %w", err)
+		}
+	}
+
+	for _, tool := range claudeConfig.DisallowedTools {
+		if err = args.AddValue("disallowed-tools", tool); err != nil {
+			return fmt.Errorf("add disallowed tool: %w", err)
+		}
+	}
+
+	if strings.TrimSpace(claudeConfig.AppendSystemPrompt) != "" {
+		if err = args.SetValue("append-system-prompt", claudeConfig.AppendSystemPrompt); err != nil {
+			return fmt.Errorf("set append system prompt: %w", err)
+		}
+	}
+
+	if claudeConfig.MaxTurns > 0 {
+		if err = args.SetValue("max-turns", claudeConfig.MaxTurns); err != nil {
+			return fmt.Errorf("set max turns: %w", err)
+		}
+	}
+
+	for _, dir := range claudeConfig.AdditionalDirectories {
+		if err = args.AddValue("add-dir", dir); err != nil {
+			return fmt.Errorf("add directory: %w", err)
+		}
+	}
+
+	for _, mcpConfig := range claudeConfig.MCPConfig {
+		if err = args.AddValue("mcp-config", mcpConfig); err != nil {
+			return fmt.Errorf("add mcp config: %w", err)
+		}
+	}
+
+	if claudeConfig.StrictMCPConfig {
+		args.SetFlag("strict-mcp-config")
+	}
+
+	for key, value := range claudeConfig.Settings {
+		if err = args.SetSettingsOverride(key, value); err != nil {
+			return fmt.Errorf("set settings override %s: %w", key, err)
+		}
+	}
+
 	return nil
 }
 
 func applyRuntimeFeaturesArguments(args *arguments, features agent.RuntimeFeatures) error {
 	if features.EnableWebSearch != nil && !*features.EnableWebSearch {
-		err := args.SetValue("disallowed-tools", "WebSearch")
+		err := args.AddValue("disallowed-tools", "WebSearch")
 		if err != nil {
 			return fmt.Errorf("disable web search: %w", err)
 		}
@@ -65,7 +183,33 @@ func applyExecutionInputArguments(args *arguments, executionInput agent.Executio
 		if err != nil {
 			return fmt.Errorf("set resume: %w", err)
 		}
+
+		if executionInput.ForkConversation {
+			args.SetFlag("fork-session")
+		}
+	}
+
+	if systemPrompt := strings.TrimSpace(executionInput.SystemPrompt); systemPrompt != "" {
+		if configured, ok := args.values["append-system-prompt"]; ok {
+			systemPrompt = configured + "\n\n" + systemPrompt
+		}
+
+		err = args.SetValue("append-system-prompt", systemPrompt)
+		if err != nil {
+			return fmt.Errorf("set system prompt: %w", err)
+		}
 	}
 
 	return nil
 }
+
+var (
+	// ErrPermissionModeInvalid indicates the configured permission mode is not supported by Claude.
+	ErrPermissionModeInvalid = errors.New("claude permission mode invalid")
+
+	// ErrMaxTurnsInvalid indicates the configured max turns value is negative.
+	ErrMaxTurnsInvalid = errors.New("claude max turns invalid")
+
+	// ErrConfigValueEmpty indicates a configured list contains an empty value.
+	ErrConfigValueEmpty = errors.New("claude config value empty")
+)
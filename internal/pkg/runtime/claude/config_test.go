@@ -0,0 +1,91 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRuntimeConfigArguments(t *testing.T) {
+	t.Run("empty config", func(t *testing.T) {
+		args := defaultArguments()
+		require.NoError(t, applyRuntimeConfigArguments(args, nil))
+		assert.Empty(t, args.ToList())
+	})
+
+	t.Run("full config", func(t *testing.T) {
+		args := defaultArguments()
+		config := map[string]any{
+			"permissionMode":        "acceptEdits",
+			"allowedTools":          []any{"Read", "Bash(git:*)"},
+			"disallowedTools":       []any{"Edit"},
+			"appendSystemPrompt":    "Be concise.",
+			"maxTurns":              5,
+			"additionalDirectories": []any{"/srv/shared"},
+			"mcpConfig":             []any{"/etc/mcp-a.json", "/etc/mcp-b.json"},
+			"strictMcpConfig":       true,
+			"settings": map[string]any{
+				"cleanupPeriodDays": 7,
+			},
+		}
+
+		require.NoError(t, applyRuntimeConfigArguments(args, config))
+		require.NoError(t, applyRuntimeFeaturesArguments(args, agent.RuntimeFeatures{EnableWebSearch: utils.ToPointer(false)}))
+
+		assert.Equal(t, []string{
+			"--strict-mcp-config",
+			"--append-system-prompt=Be concise.",
+			"--max-turns=5",
+			"--permission-mode=acceptEdits",
+			"--add-dir=/srv/shared",
+			"--allowed-tools=Read",
+			"--allowed-tools=Bash(git:*)",
+			"--disallowed-tools=Edit",
+			"--disallowed-tools=WebSearch",
+			"--mcp-config=/etc/mcp-a.json",
+			"--mcp-config=/etc/mcp-b.json",
+			`--settings={"cleanupPeriodDays":7}`,
+		}, args.ToList())
+	})
+
+	t.Run("invalid permission mode", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{PermissionMode: "yolo"})
+		require.ErrorIs(t, err, ErrPermissionModeInvalid)
+	})
+
+	t.Run("negative max turns", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{MaxTurns: -1})
+		require.ErrorIs(t, err, ErrMaxTurnsInvalid)
+	})
+
+	t.Run("empty tool", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{AllowedTools: []string{" "}})
+		require.ErrorIs(t, err, ErrConfigValueEmpty)
+	})
+}
+
+func TestApplyExecutionInputArgumentsSystemPrompt(t *testing.T) {
+	t.Run("system prompt only", func(t *testing.T) {
+		args := defaultArguments()
+		require.NoError(t, applyExecutionInputArguments(args, agent.ExecutionInput{SystemPrompt: "You review security."}))
+		assert.Equal(t, []string{"--append-system-prompt=You review security."}, args.ToList())
+	})
+
+	t.Run("appended to configured prompt", func(t *testing.T) {
+		args := defaultArguments()
+		require.NoError(t, applyRuntimeConfigArguments(args, Config{AppendSystemPrompt: "Be concise."}))
+		require.NoError(t, applyExecutionInputArguments(args, agent.ExecutionInput{SystemPrompt: "You review security."}))
+		assert.Equal(t, []string{"--append-system-prompt=Be concise.\n\nYou review security."}, args.ToList())
+	})
+}
+
+func TestApplyExecutionInputArgumentsFork(t *testing.T) {
+	conversationID := agent.ConversationID("session-1")
+
+	args := defaultArguments()
+	require.NoError(t, applyExecutionInputArguments(args, agent.ExecutionInput{ConversationID: &conversationID, ForkConversation: true}))
+	assert.Equal(t, []string{"--fork-session", "--resume=session-1"}, args.ToList())
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/process"
 )
 
@@ -30,6 +31,9 @@ type Instance struct {
 
 	stderr strings.Builder
 
+	// failure is the error text Claude reported on stdout, such as an error result.
+	failure string
+
 	closers []io.Closer
 }
 
@@ -39,14 +43,26 @@ type claudeEvent struct {
 	SessionID string `json:"session_id,omitempty"`
 	Message   struct {
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text,omitempty"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text,omitempty"`
+			Name  string          `json:"name,omitempty"`
+			Input json.RawMessage `json:"input,omitempty"`
 		} `json:"content,omitempty"`
 	} `json:"message,omitempty"`
-	Result string `json:"result,omitempty"`
+	Result       string       `json:"result,omitempty"`
+	IsError      bool         `json:"is_error,omitempty"`
+	TotalCostUSD *float64     `json:"total_cost_usd,omitempty"`
+	Usage        *claudeUsage `json:"usage,omitempty"`
+}
+
+type claudeUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
 }
 
-func newInstance(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, runtimeConfig Config, runtimeFeatures agent.RuntimeFeatures, logDir string) (*Instance, error) {
+func newInstance(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, runtimeConfig Config, runtimeFeatures agent.RuntimeFeatures, environment []string, logDir string) (*Instance, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -82,6 +98,7 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 	instanceArgumentsList := runtimeArguments.ToList()
 
 	cmd := exec.CommandContext(ctx, path, instanceArgumentsList...)
+	cmd.Env = environment
 
 	if executionInput.WorkingDirectory != nil && strings.TrimSpace(*executionInput.WorkingDirectory) != "" {
 		cmd.Dir = *executionInput.WorkingDirectory
@@ -99,9 +116,9 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 		done:   make(chan struct{}),
 	}
 
-	sessionLogDir := filepath.Join(logDir, "claude", string(executionId), time.Now().Format("2006-01-02_15-04-05"))
-	if err := os.MkdirAll(sessionLogDir, 0755); err != nil {
-		return nil, fmt.Errorf("create session log directory: %w", err)
+	sessionLogDir, err := cli.CreateRuntimeLogDir(logDir, Claude, executionId, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	stdinLog, err := os.Create(filepath.Join(sessionLogDir, "stdin.log"))
@@ -219,12 +236,30 @@ func (instance *Instance) watchClaudeEvents(stdoutLog io.Writer) error {
 			for _, content := range event.Message.Content {
 				if content.Type == "text" {
 					instance.result.Response += content.Text
+					instance.emitRuntimeEvent(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: content.Text})
+				}
+				if content.Type == "tool_use" {
+					instance.emitRuntimeEvent(toolActivity(content.Name, content.Input, time.Now()))
 				}
 			}
 		case "result":
 			if event.Subtype == "success" && event.Result != "" {
 				instance.result.Response = event.Result
 			}
+
+			// Usage limit notices arrive as a result flagged as an error rather than on stderr.
+			if (event.IsError || event.Subtype != "success") && event.Result != "" {
+				instance.failure = event.Result
+			}
+
+			if event.Usage != nil {
+				instance.result.Usage = &agent.RuntimeUsage{
+					InputTokens:       event.Usage.InputTokens + event.Usage.CacheCreationInputTokens + event.Usage.CacheReadInputTokens,
+					CachedInputTokens: event.Usage.CacheReadInputTokens,
+					OutputTokens:      event.Usage.OutputTokens,
+					CostUSD:           event.TotalCostUSD,
+				}
+			}
 		}
 	}
 
@@ -237,6 +272,9 @@ func (instance *Instance) watchClaudeEvents(stdoutLog io.Writer) error {
 
 func (instance *Instance) runtimeError(err error) error {
 	message := strings.TrimSpace(instance.stderr.String())
+	if message == "" {
+		message = strings.TrimSpace(instance.failure)
+	}
 	if message == "" {
 		message = err.Error()
 	}
@@ -246,6 +284,9 @@ func (instance *Instance) runtimeError(err error) error {
 		Cause:   err,
 	}
 
+	// The response is left out: a reply discussing rate limits is not a rate limit.
+	runtimeErr.Class, runtimeErr.RetryAt = classifyError(message+"\n"+instance.failure, time.Now())
+
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
 		code := exitErr.ExitCode()
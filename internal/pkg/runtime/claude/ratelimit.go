@@ -0,0 +1,58 @@
+package claude
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+)
+
+var (
+	// usageLimitEpochPattern matches "Claude AI usage limit reached|1760000000".
+	usageLimitEpochPattern = regexp.MustCompile(`usage limit reached\|(\d{9,})`)
+
+	// limitResetsPattern matches "5-hour limit reached ∙ resets 3pm" with an optional time zone in parentheses.
+	limitResetsPattern = regexp.MustCompile(`(?i)(?:usage|\d+-hour|weekly|session) limit reached.*?resets\s+(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\s*\(([^)]+)\))?`)
+
+	// rateLimitPattern matches usage and rate limit wording, but not context or turn limits.
+	rateLimitPattern = regexp.MustCompile(`(?i)usage limit|rate limit|rate_limit_error|(?:\d+-hour|weekly|session) limit reached`)
+
+	authPattern = regexp.MustCompile(`(?i)invalid api key|not logged in|please run /login|oauth token (?:has )?expired|authentication_error`)
+)
+
+// classifyError recognizes Claude usage limit and authentication messages and extracts the reset time when present.
+func classifyError(message string, now time.Time) (agent.RuntimeErrorClass, *time.Time) {
+	if match := usageLimitEpochPattern.FindStringSubmatch(message); match != nil {
+		seconds, err := strconv.ParseInt(match[1], 10, 64)
+		if err == nil {
+			return agent.RuntimeErrorRateLimited, utils.ToPointer(time.Unix(seconds, 0))
+		}
+	}
+
+	if match := limitResetsPattern.FindStringSubmatch(message); match != nil {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+
+		local := now
+		if zone := strings.TrimSpace(match[4]); zone != "" {
+			if location, err := time.LoadLocation(zone); err == nil {
+				local = now.In(location)
+			}
+		}
+
+		return agent.RuntimeErrorRateLimited, utils.ToPointer(utils.NextTimeOfDay(local, utils.ClockHour(hour, strings.ToLower(match[3])), minute))
+	}
+
+	if rateLimitPattern.MatchString(message) {
+		return agent.RuntimeErrorRateLimited, nil
+	}
+
+	if authPattern.MatchString(message) {
+		return agent.RuntimeErrorAuth, nil
+	}
+
+	return agent.RuntimeErrorUnknown, nil
+}
@@ -0,0 +1,99 @@
+package claude
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	t.Run("usage limit with epoch", func(t *testing.T) {
+		class, retryAt := classifyError("Claude AI usage limit reached|1741622400", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.True(t, retryAt.Equal(time.Unix(1741622400, 0)))
+	})
+
+	t.Run("limit resets at clock time", func(t *testing.T) {
+		class, retryAt := classifyError("5-hour limit reached ∙ resets 3pm", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.True(t, retryAt.Equal(time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("limit resets with time zone", func(t *testing.T) {
+		class, retryAt := classifyError("Session limit reached ∙ resets 9:30am (UTC)", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.True(t, retryAt.Equal(time.Date(2025, 3, 11, 9, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("rate limit without reset", func(t *testing.T) {
+		class, retryAt := classifyError(`API Error: 429 {"type":"error","error":{"type":"rate_limit_error"}}`, now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		assert.Nil(t, retryAt)
+	})
+
+	t.Run("weekly limit without reset", func(t *testing.T) {
+		class, _ := classifyError("Opus weekly limit reached", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+	})
+
+	t.Run("context and turn limits", func(t *testing.T) {
+		class, _ := classifyError("Context limit reached · /compact or /clear to continue", now)
+		assert.Equal(t, agent.RuntimeErrorUnknown, class)
+
+		class, _ = classifyError("Error: Reached max turns (10): maximum turn limit reached", now)
+		assert.Equal(t, agent.RuntimeErrorUnknown, class)
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		class, retryAt := classifyError("Invalid API key · Please run /login", now)
+		assert.Equal(t, agent.RuntimeErrorAuth, class)
+		assert.Nil(t, retryAt)
+	})
+
+	t.Run("other failure", func(t *testing.T) {
+		class, retryAt := classifyError("Error: Input must be provided either through stdin or as a prompt argument", now)
+		assert.Equal(t, agent.RuntimeErrorUnknown, class)
+		assert.Nil(t, retryAt)
+	})
+}
+
+func TestInstance_RuntimeErrorClass(t *testing.T) {
+	t.Run("response mentioning rate limits", func(t *testing.T) {
+		instance := &Instance{}
+		instance.result.Response = "Added a rate limit to the API client; the usage limit is reached after 100 calls."
+
+		err := instance.runtimeError(errors.New("exit status 1"))
+
+		var runtimeErr *agent.RuntimeExecutionError
+		require.ErrorAs(t, err, &runtimeErr)
+		assert.Equal(t, agent.RuntimeErrorUnknown, runtimeErr.Class)
+		assert.Nil(t, runtimeErr.RetryAt)
+	})
+
+	t.Run("usage limit error result", func(t *testing.T) {
+		instance := &Instance{
+			stdout: io.NopCloser(strings.NewReader(`{"type":"result","subtype":"success","is_error":true,"result":"Claude AI usage limit reached|1741622400"}` + "\n")),
+		}
+		require.NoError(t, instance.watchClaudeEvents(io.Discard))
+
+		instance.stderr.WriteString("request failed")
+		err := instance.runtimeError(errors.New("exit status 1"))
+
+		var runtimeErr *agent.RuntimeExecutionError
+		require.ErrorAs(t, err, &runtimeErr)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, runtimeErr.Class)
+		require.NotNil(t, runtimeErr.RetryAt)
+		assert.True(t, runtimeErr.RetryAt.Equal(time.Unix(1741622400, 0)))
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
@@ -16,8 +18,18 @@ import (
 
 var semverPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
 
+// supportedVersions is the CLI version range whose verbose stream-json output the instance parser understands.
+const supportedVersions = ">=1.0.0, <3.0.0"
+
 const Claude = agent.RuntimeKind("claude")
 
+// knownModels is a static fallback list of well-known Claude models. It is not read from the CLI,
+// so it may lag behind the models the CLI accepts, and any other model is passed through unchanged.
+var knownModels = []string{"sonnet", "opus", "haiku", "claude-sonnet-4-5", "claude-opus-4-1", "claude-haiku-4-5"}
+
+// homeEnvironmentVariable points Claude at the agent home directory instead of ~/.claude.
+const homeEnvironmentVariable = "CLAUDE_CONFIG_DIR"
+
 type Runtime struct {
 }
 
@@ -26,6 +38,8 @@ func NewRuntime() *Runtime {
 }
 
 func (runtime *Runtime) Execute(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, agentConfig agent.Config) (agent.RuntimeInstance, error) {
+	executionInput.SystemPrompt = agentConfig.ResolveSystemPrompt(executionInput)
+
 	logDir, err := cli.ResolveRuntimeLogDir()
 	if err != nil {
 		return nil, err
@@ -36,7 +50,12 @@ func (runtime *Runtime) Execute(ctx context.Context, executionId agent.Execution
 		return nil, fmt.Errorf("convert runtime config: %w", err)
 	}
 
-	instance, err := newInstance(ctx, executionId, executionInput, *runtimeConfig, agentConfig.Runtime.Feature, logDir)
+	environment, err := agentConfig.Environment.Resolve(os.Environ(), homeEnvironmentVariable)
+	if err != nil {
+		return nil, fmt.Errorf("resolve environment: %w", err)
+	}
+
+	instance, err := newInstance(ctx, executionId, executionInput, *runtimeConfig, agentConfig.Runtime.Feature, environment, logDir)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +90,41 @@ func (runtime *Runtime) GetDefaultFeatures(ctx context.Context) (agent.RuntimeFe
 	}, nil
 }
 
+func (runtime *Runtime) ValidateConfig(ctx context.Context, runtimeConfig agent.RuntimeConfig) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	config, err := decodeConfig(runtimeConfig)
+	if err != nil {
+		return err
+	}
+
+	return config.Validate()
+}
+
+func (runtime *Runtime) GetCapabilities(ctx context.Context) (agent.RuntimeCapabilities, error) {
+	return agent.RuntimeCapabilities{
+		Resume:              true,
+		Fork:                true,
+		AttachmentMimeTypes: []string{},
+		WebSearchToggle:     true,
+		NetworkAccessToggle: false,
+		ModelOverride:       true,
+		StructuredOutput:    false,
+		SystemPrompt:        true,
+	}, nil
+}
+
+// ListModels returns knownModels, because the Claude CLI cannot report the models it accepts.
+func (runtime *Runtime) ListModels(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(knownModels), nil
+}
+
 func (runtime *Runtime) GetInfo(ctx context.Context) (agent.RuntimeInfo, error) {
 	if err := ctx.Err(); err != nil {
 		return agent.RuntimeInfo{}, err
@@ -91,5 +145,5 @@ func (runtime *Runtime) GetInfo(ctx context.Context) (agent.RuntimeInfo, error)
 		return agent.RuntimeInfo{}, fmt.Errorf("parse claude version from output: %s", strings.TrimSpace(string(output)))
 	}
 
-	return agent.RuntimeInfo{Version: version}, nil
+	return agent.RuntimeInfo{Version: version, SupportedVersions: supportedVersions}, nil
 }
@@ -0,0 +1,42 @@
+package codex
+
+import (
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// codexItem is a thread item reported by `codex exec --json`.
+type codexItem struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Command string `json:"command,omitempty"`
+	Changes []struct {
+		Path string `json:"path"`
+		Kind string `json:"kind"`
+	} `json:"changes,omitempty"`
+	Server string `json:"server,omitempty"`
+	Tool   string `json:"tool,omitempty"`
+	Query  string `json:"query,omitempty"`
+}
+
+// itemActivity describes a completed Codex thread item as agent activity.
+// Items that are not tool calls, commands or file changes produce no activity.
+func itemActivity(item codexItem, now time.Time) []agent.RuntimeActivityEvent {
+	switch item.Type {
+	case "command_execution":
+		return []agent.RuntimeActivityEvent{{Timestamp: now, Activity: agent.RuntimeActivityCommand, Name: item.Type, Detail: item.Command}}
+	case "file_change":
+		activities := make([]agent.RuntimeActivityEvent, 0, len(item.Changes))
+		for _, change := range item.Changes {
+			activities = append(activities, agent.RuntimeActivityEvent{Timestamp: now, Activity: agent.RuntimeActivityEdit, Name: change.Kind, Detail: change.Path})
+		}
+		return activities
+	case "mcp_tool_call":
+		return []agent.RuntimeActivityEvent{{Timestamp: now, Activity: agent.RuntimeActivityTool, Name: item.Server + "." + item.Tool}}
+	case "web_search":
+		return []agent.RuntimeActivityEvent{{Timestamp: now, Activity: agent.RuntimeActivityTool, Name: item.Type, Detail: item.Query}}
+	default:
+		return nil
+	}
+}
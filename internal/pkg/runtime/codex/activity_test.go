@@ -0,0 +1,44 @@
+package codex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemActivity(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	decode := func(t *testing.T, payload string) codexItem {
+		var item codexItem
+		require.NoError(t, json.Unmarshal([]byte(payload), &item))
+		return item
+	}
+
+	t.Run("command", func(t *testing.T) {
+		activities := itemActivity(decode(t, `{"type":"command_execution","command":"bash -lc 'go test ./...'","exit_code":0}`), now)
+		assert.Equal(t, []agent.RuntimeActivityEvent{{Timestamp: now, Activity: agent.RuntimeActivityCommand, Name: "command_execution", Detail: "bash -lc 'go test ./...'"}}, activities)
+	})
+
+	t.Run("file changes", func(t *testing.T) {
+		activities := itemActivity(decode(t, `{"type":"file_change","changes":[{"path":"/repo/a.go","kind":"update"},{"path":"/repo/b.go","kind":"add"}]}`), now)
+		require.Len(t, activities, 2)
+		assert.Equal(t, agent.RuntimeActivityEdit, activities[1].Activity)
+		assert.Equal(t, "add", activities[1].Name)
+		assert.Equal(t, "/repo/b.go", activities[1].Detail)
+	})
+
+	t.Run("mcp tool", func(t *testing.T) {
+		activities := itemActivity(decode(t, `{"type":"mcp_tool_call","server":"docs","tool":"search"}`), now)
+		require.Len(t, activities, 1)
+		assert.Equal(t, "docs.search", activities[0].Name)
+	})
+
+	t.Run("agent message", func(t *testing.T) {
+		assert.Empty(t, itemActivity(decode(t, `{"type":"agent_message","text":"Done."}`), now))
+	})
+}
@@ -2,19 +2,26 @@ package codex
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 )
 
 type arguments struct {
 	flags           map[string]bool
 	values          map[string]string
+	multiValues     map[string][]string
 	configOverrides map[string]string
+	resumeThreadID  string
 }
 
 func defaultArguments() *arguments {
 	argument := &arguments{
 		flags:           map[string]bool{},
 		values:          map[string]string{},
+		multiValues:     map[string][]string{},
 		configOverrides: map[string]string{},
 	}
 
@@ -36,17 +43,49 @@ func (arguments *arguments) SetValue(name string, value any) error {
 	return nil
 }
 
+func (arguments *arguments) AddValue(name string, value any) error {
+	valueStr, err := arguments.valueToString(value)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(arguments.multiValues[name], valueStr) {
+		arguments.multiValues[name] = append(arguments.multiValues[name], valueStr)
+	}
+
+	return nil
+}
+
 func (arguments *arguments) SetConfigOverride(key string, value any) error {
 	valueStr, err := arguments.valueToString(value)
 	if err != nil {
 		return err
 	}
 
-	arguments.values[key] = valueStr
+	arguments.configOverrides[key] = valueStr
 
 	return nil
 }
 
+func (arguments *arguments) SetResume(threadID agent.ConversationID) error {
+	if strings.TrimSpace(string(threadID)) == "" {
+		return fmt.Errorf("empty string")
+	}
+
+	arguments.resumeThreadID = string(threadID)
+
+	return nil
+}
+
+// Positionals returns the trailing positional arguments; the prompt is always read from stdin.
+func (arguments *arguments) Positionals() []string {
+	if arguments.resumeThreadID != "" {
+		return []string{"resume", arguments.resumeThreadID, "-"}
+	}
+
+	return []string{"-"}
+}
+
 func (arguments *arguments) valueToString(value any) (string, error) {
 	switch value := (value).(type) {
 	case string:
@@ -62,24 +101,33 @@ func (arguments *arguments) valueToString(value any) (string, error) {
 		} else {
 			return "false", nil
 		}
+
+	case int:
+		return fmt.Sprintf("%d", value), nil
 	}
 
-	return "", fmt.Errorf("unsupported type %t", value)
+	return "", fmt.Errorf("unsupported type %T", value)
 }
 
 func (arguments *arguments) ToList() []string {
 	var list []string
 
-	for flag := range arguments.flags {
+	for _, flag := range slices.Sorted(maps.Keys(arguments.flags)) {
 		list = append(list, fmt.Sprintf("--%s", flag))
 	}
 
-	for key, value := range arguments.values {
-		list = append(list, fmt.Sprintf("--%s=\"%s\"", key, value))
+	for _, key := range slices.Sorted(maps.Keys(arguments.values)) {
+		list = append(list, fmt.Sprintf("--%s=%s", key, arguments.values[key]))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(arguments.multiValues)) {
+		for _, value := range arguments.multiValues[key] {
+			list = append(list, fmt.Sprintf("--%s=%s", key, value))
+		}
 	}
 
-	for key, value := range arguments.configOverrides {
-		list = append(list, fmt.Sprintf("--config=\"%s=%s\"", key, value))
+	for _, key := range slices.Sorted(maps.Keys(arguments.configOverrides)) {
+		list = append(list, fmt.Sprintf("--config=%s=%s", key, arguments.configOverrides[key]))
 	}
 
 	return list
@@ -2,7 +2,12 @@ package codex
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/mcuadros/go-defaults"
 
@@ -12,10 +17,122 @@ import (
 // Config defines runtime options for Codex execution.
 type Config struct {
 	// RequireWorkspaceRepository enforces that codex workdir must be a GIT repository.
-	RequireWorkspaceRepository bool `json:"requireWorkspaceRepository" default:"true"`
+	// Codex requires a repository when the value is not set.
+	RequireWorkspaceRepository *bool `json:"requireWorkspaceRepository,omitempty"`
+
+	// SandboxMode selects the sandbox policy for commands (read-only, workspace-write, danger-full-access).
+	SandboxMode string `json:"sandboxMode,omitempty"`
+
+	// ApprovalPolicy selects when Codex asks for approval (untrusted, on-failure, on-request, never).
+	ApprovalPolicy string `json:"approvalPolicy,omitempty"`
+
+	// Profile selects a configuration profile from the Codex config.toml.
+	Profile string `json:"profile,omitempty"`
+
+	// ReasoningEffort sets the model reasoning effort (minimal, low, medium, high).
+	ReasoningEffort string `json:"reasoningEffort,omitempty"`
+
+	// OutputLastMessage is a file path where Codex writes its last message.
+	OutputLastMessage string `json:"outputLastMessage,omitempty"`
+
+	// ConfigOverrides are passed as -c key=value overrides and parsed by Codex as TOML.
+	// Strings are passed verbatim; booleans, numbers, lists and maps are rendered as TOML literals.
+	ConfigOverrides map[string]any `json:"configOverrides,omitempty"`
 }
 
-func applyRuntimeConfigArguments(runtimeArguments *arguments, config agent.RuntimeConfig) error {
+var (
+	sandboxModes     = []string{"read-only", "workspace-write", "danger-full-access"}
+	approvalPolicies = []string{"untrusted", "on-failure", "on-request", "never"}
+	reasoningEfforts = []string{"minimal", "low", "medium", "high"}
+)
+
+// Validate checks whether the Codex runtime configuration is well-formed.
+func (config Config) Validate() error {
+	if config.SandboxMode != "" && !slices.Contains(sandboxModes, config.SandboxMode) {
+		return fmt.Errorf("%w: %s", ErrSandboxModeInvalid, config.SandboxMode)
+	}
+
+	if config.ApprovalPolicy != "" && !slices.Contains(approvalPolicies, config.ApprovalPolicy) {
+		return fmt.Errorf("%w: %s", ErrApprovalPolicyInvalid, config.ApprovalPolicy)
+	}
+
+	if config.ReasoningEffort != "" && !slices.Contains(reasoningEfforts, config.ReasoningEffort) {
+		return fmt.Errorf("%w: %s", ErrReasoningEffortInvalid, config.ReasoningEffort)
+	}
+
+	for key, value := range config.ConfigOverrides {
+		if strings.TrimSpace(key) == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("%w: %q", ErrConfigOverrideKeyInvalid, key)
+		}
+
+		if _, err := tomlValue(value, false); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrConfigOverrideValueInvalid, key, err)
+		}
+	}
+
+	return nil
+}
+
+// tomlValue renders a config override value as a TOML literal.
+// Top-level strings are passed verbatim so that they may hold any TOML value, nested strings are quoted.
+func tomlValue(value any, nested bool) (string, error) {
+	switch value := value.(type) {
+	case string:
+		if !nested {
+			return value, nil
+		}
+
+		// A JSON string literal is a valid TOML basic string.
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(quoted), nil
+
+	case bool:
+		return strconv.FormatBool(value), nil
+
+	case int:
+		return strconv.Itoa(value), nil
+
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			rendered, err := tomlValue(item, true)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, rendered)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+
+	case map[string]any:
+		fields := make([]string, 0, len(value))
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			rendered, err := tomlValue(value[key], true)
+			if err != nil {
+				return "", err
+			}
+
+			quotedKey, err := json.Marshal(key)
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, string(quotedKey)+" = "+rendered)
+		}
+		return "{" + strings.Join(fields, ", ") + "}", nil
+	}
+
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
+
+func decodeConfig(config agent.RuntimeConfig) (Config, error) {
 	var codexConfig Config
 
 	switch typed := config.(type) {
@@ -30,20 +147,80 @@ func applyRuntimeConfigArguments(runtimeArguments *arguments, config agent.Runti
 	default:
 		payload, err := json.Marshal(config)
 		if err != nil {
-			return fmt.Errorf("marshal codex runtime config: %w", err)
+			return Config{}, fmt.Errorf("marshal codex runtime config: %w", err)
 		}
 
 		if err := json.Unmarshal(payload, &codexConfig); err != nil {
-			return fmt.Errorf("unmarshal codex runtime config: %w", err)
+			return Config{}, fmt.Errorf("unmarshal codex runtime config: %w", err)
 		}
 	}
 
 	defaults.SetDefaults(&codexConfig)
 
-	if !codexConfig.RequireWorkspaceRepository {
+	return codexConfig, nil
+}
+
+func applyRuntimeConfigArguments(runtimeArguments *arguments, config agent.RuntimeConfig) error {
+	codexConfig, err := decodeConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := codexConfig.Validate(); err != nil {
+		return err
+	}
+
+	if codexConfig.RequireWorkspaceRepository != nil && !*codexConfig.RequireWorkspaceRepository {
 		runtimeArguments.SetFlag("skip-git-repo-check")
 	}
 
+	for _, key := range slices.Sorted(maps.Keys(codexConfig.ConfigOverrides)) {
+		value, err := tomlValue(codexConfig.ConfigOverrides[key], false)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrConfigOverrideValueInvalid, key, err)
+		}
+
+		err = runtimeArguments.SetConfigOverride(key, value)
+		if err != nil {
+			return fmt.Errorf("set config override %s: %w", key, err)
+		}
+	}
+
+	if codexConfig.SandboxMode != "" {
+		err = runtimeArguments.SetValue("sandbox", codexConfig.SandboxMode)
+		if err != nil {
+			return fmt.Errorf("set sandbox mode: %w", err)
+		}
+	}
+
+	if codexConfig.ApprovalPolicy != "" {
+		err = runtimeArguments.SetConfigOverride("approval_policy", codexConfig.ApprovalPolicy)
+		if err != nil {
+			return fmt.Errorf("set approval policy: %w", err)
+		}
+	}
+
+	if codexConfig.Profile != "" {
+		err = runtimeArguments.SetValue("profile", codexConfig.Profile)
+		if err != nil {
+			return fmt.Errorf("set profile: %w", err)
+		}
+	}
+
+	if codexConfig.ReasoningEffort != "" {
+		err = runtimeArguments.SetConfigOverride("model_reasoning_effort", codexConfig.ReasoningEffort)
+		if err != nil {
+			return fmt.Errorf("set reasoning effort: %w", err)
+		}
+	}
+
+	if codexConfig.OutputLastMessage != "" {
+		err = runtimeArguments.SetValue("output-last-message", codexConfig.OutputLastMessage)
+		if err != nil {
+			return fmt.Errorf("set output last message: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -77,5 +254,49 @@ func applyExecutionInputArguments(runtimeArguments *arguments, executionInput ag
 		}
 	}
 
+	if executionInput.ConversationID != nil {
+		err = runtimeArguments.SetResume(*executionInput.ConversationID)
+		if err != nil {
+			return fmt.Errorf("set resume: %w", err)
+		}
+	}
+
+	if systemPrompt := strings.TrimSpace(executionInput.SystemPrompt); systemPrompt != "" {
+		// Override values are parsed as TOML; a JSON string literal is a valid TOML basic string.
+		quoted, err := json.Marshal(systemPrompt)
+		if err != nil {
+			return fmt.Errorf("quote system prompt: %w", err)
+		}
+
+		err = runtimeArguments.SetConfigOverride("developer_instructions", string(quoted))
+		if err != nil {
+			return fmt.Errorf("set system prompt: %w", err)
+		}
+	}
+
+	for _, attachment := range executionInput.Attachments {
+		err = runtimeArguments.AddValue("image", attachment.Path)
+		if err != nil {
+			return fmt.Errorf("add image attachment: %w", err)
+		}
+	}
+
 	return nil
 }
+
+var (
+	// ErrSandboxModeInvalid indicates the configured sandbox mode is not supported by Codex.
+	ErrSandboxModeInvalid = errors.New("codex sandbox mode invalid")
+
+	// ErrApprovalPolicyInvalid indicates the configured approval policy is not supported by Codex.
+	ErrApprovalPolicyInvalid = errors.New("codex approval policy invalid")
+
+	// ErrReasoningEffortInvalid indicates the configured reasoning effort is not supported by Codex.
+	ErrReasoningEffortInvalid = errors.New("codex reasoning effort invalid")
+
+	// ErrConfigOverrideKeyInvalid indicates a config override key is empty or malformed.
+	ErrConfigOverrideKeyInvalid = errors.New("codex config override key invalid")
+
+	// ErrConfigOverrideValueInvalid indicates a config override value cannot be rendered as TOML.
+	ErrConfigOverrideValueInvalid = errors.New("codex config override value invalid")
+)
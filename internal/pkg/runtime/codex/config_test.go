@@ -0,0 +1,86 @@
+package codex
+
+import (
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRuntimeConfigArguments(t *testing.T) {
+	t.Run("default config", func(t *testing.T) {
+		args := defaultArguments()
+		require.NoError(t, applyRuntimeConfigArguments(args, nil))
+		assert.Empty(t, args.ToList())
+	})
+
+	t.Run("full config", func(t *testing.T) {
+		args := defaultArguments()
+		config := map[string]any{
+			"requireWorkspaceRepository": false,
+			"sandboxMode":                "workspace-write",
+			"approvalPolicy":             "never",
+			"profile":                    "ci",
+			"reasoningEffort":            "high",
+			"outputLastMessage":          "/tmp/last.txt",
+			"configOverrides": map[string]any{
+				"model_verbosity":                        "low",
+				"shell_environment_policy.inherit":       "core",
+				"model_context_window":                   float64(200000),
+				"features.web_search":                    true,
+				"sandbox_workspace_write.writable_roots": []any{"/tmp", "/var/cache"},
+				"shell_environment_policy.set":           map[string]any{"CI": "1"},
+			},
+		}
+
+		require.NoError(t, applyRuntimeConfigArguments(args, config))
+		require.NoError(t, applyRuntimeFeaturesArguments(args, agent.RuntimeFeatures{
+			EnableWebSearch:     utils.ToPointer(true),
+			EnableNetworkAccess: utils.ToPointer(false),
+		}))
+
+		assert.Equal(t, []string{
+			"--skip-git-repo-check",
+			"--output-last-message=/tmp/last.txt",
+			"--profile=ci",
+			"--sandbox=workspace-write",
+			"--config=approval_policy=never",
+			"--config=features.web_search=true",
+			"--config=features.web_search_request=true",
+			"--config=model_context_window=200000",
+			"--config=model_reasoning_effort=high",
+			"--config=model_verbosity=low",
+			"--config=sandbox_workspace_write.network_access=false",
+			"--config=sandbox_workspace_write.writable_roots=[\"/tmp\", \"/var/cache\"]",
+			"--config=shell_environment_policy.inherit=core",
+			"--config=shell_environment_policy.set={\"CI\" = \"1\"}",
+		}, args.ToList())
+	})
+
+	t.Run("invalid sandbox mode", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{SandboxMode: "none"})
+		require.ErrorIs(t, err, ErrSandboxModeInvalid)
+	})
+
+	t.Run("invalid approval policy", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{ApprovalPolicy: "always"})
+		require.ErrorIs(t, err, ErrApprovalPolicyInvalid)
+	})
+
+	t.Run("invalid reasoning effort", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{ReasoningEffort: "extreme"})
+		require.ErrorIs(t, err, ErrReasoningEffortInvalid)
+	})
+
+	t.Run("invalid override key", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{ConfigOverrides: map[string]any{"a=b": "c"}})
+		require.ErrorIs(t, err, ErrConfigOverrideKeyInvalid)
+	})
+
+	t.Run("invalid override value", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{ConfigOverrides: map[string]any{"model": nil}})
+		require.ErrorIs(t, err, ErrConfigOverrideValueInvalid)
+	})
+}
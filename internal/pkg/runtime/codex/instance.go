@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/process"
 )
 
@@ -31,19 +32,28 @@ type Instance struct {
 
 	stderr strings.Builder
 
+	// failure is the error text Codex reported in its event stream.
+	failure string
+
 	closers []io.Closer
 }
 
 type codexEvent struct {
 	Type     string `json:"type"`
 	ThreadID string `json:"thread_id"`
-	Item     struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"item"`
+	Message  string `json:"message"`
+	Error    struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Item  codexItem `json:"item"`
+	Usage *struct {
+		InputTokens       int64 `json:"input_tokens"`
+		CachedInputTokens int64 `json:"cached_input_tokens"`
+		OutputTokens      int64 `json:"output_tokens"`
+	} `json:"usage,omitempty"`
 }
 
-func newInstance(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, runtimeConfig Config, runtimeFeatures agent.RuntimeFeatures, logDir string) (*Instance, error) {
+func newInstance(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, runtimeConfig Config, runtimeFeatures agent.RuntimeFeatures, environment []string, logDir string) (*Instance, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -73,18 +83,31 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 	// Force JSON stream output for parsing
 	runtimeArguments.SetFlag("json")
 
+	sessionLogDir, err := cli.CreateRuntimeLogDir(logDir, Codex, executionId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Codex reads the output schema from a file, so it is stored next to the session logs.
+	if len(executionInput.OutputSchema) > 0 {
+		schemaPath := filepath.Join(sessionLogDir, "output-schema.json")
+		if err := os.WriteFile(schemaPath, executionInput.OutputSchema, 0644); err != nil {
+			return nil, fmt.Errorf("write output schema: %w", err)
+		}
+
+		if err := runtimeArguments.SetValue("output-schema", schemaPath); err != nil {
+			return nil, fmt.Errorf("set output schema: %w", err)
+		}
+	}
+
 	instanceArgumentsList := slices.Concat(
 		[]string{"exec"},
 		runtimeArguments.ToList(),
+		runtimeArguments.Positionals(),
 	)
 
-	if executionInput.ConversationID != nil {
-		instanceArgumentsList = append(instanceArgumentsList, "resume", string(*executionInput.ConversationID), "-")
-	} else {
-		instanceArgumentsList = append(instanceArgumentsList, "-")
-	}
-
 	cmd := exec.CommandContext(ctx, path, instanceArgumentsList...)
+	cmd.Env = environment
 	if executionInput.WorkingDirectory != nil && strings.TrimSpace(*executionInput.WorkingDirectory) != "" {
 		cmd.Dir = *executionInput.WorkingDirectory
 	} else {
@@ -95,18 +118,24 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 		cmd.Dir = workingDir
 	}
 
+	if executionInput.ConversationID != nil {
+		codexHome, err := resolveCodexHome(environment)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validateThread(ctx, codexHome, *executionInput.ConversationID, cmd.Dir); err != nil {
+			return nil, fmt.Errorf("validate codex thread: %w", err)
+		}
+	}
+
 	instance := &Instance{
 		cmd:    cmd,
-		events: make(chan agent.RuntimeEvent, 2),
+		events: make(chan agent.RuntimeEvent, 10),
 		done:   make(chan struct{}),
 	}
 
 	// Setup logging
-	sessionLogDir := filepath.Join(logDir, "codex", string(executionId), time.Now().Format("2006-01-02_15-04-05"))
-	if err := os.MkdirAll(sessionLogDir, 0755); err != nil {
-		return nil, fmt.Errorf("create session log directory: %w", err)
-	}
-
 	stdinLog, err := os.Create(filepath.Join(sessionLogDir, "stdin.log"))
 	if err != nil {
 		return nil, fmt.Errorf("create stdin log: %w", err)
@@ -222,6 +251,30 @@ func (instance *Instance) watchCodexEvents(stdoutLog io.Writer) error {
 		case "item.completed":
 			if event.Item.Type == "agent_message" {
 				instance.result.Response = event.Item.Text
+				instance.emitRuntimeEvent(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: event.Item.Text})
+			}
+			for _, activity := range itemActivity(event.Item, time.Now()) {
+				instance.emitRuntimeEvent(activity)
+			}
+		case "turn.completed":
+			if event.Usage != nil {
+				usage := agent.RuntimeUsage{
+					InputTokens:       event.Usage.InputTokens,
+					CachedInputTokens: event.Usage.CachedInputTokens,
+					OutputTokens:      event.Usage.OutputTokens,
+				}
+				if instance.result.Usage != nil {
+					usage = instance.result.Usage.Add(usage)
+				}
+				instance.result.Usage = &usage
+			}
+		case "error":
+			if event.Message != "" {
+				instance.failure = event.Message
+			}
+		case "turn.failed":
+			if event.Error.Message != "" {
+				instance.failure = event.Error.Message
 			}
 		}
 	}
@@ -234,7 +287,10 @@ func (instance *Instance) watchCodexEvents(stdoutLog io.Writer) error {
 }
 
 func (instance *Instance) runtimeError(err error) error {
-	message := strings.TrimSpace(instance.stderr.String())
+	message := strings.TrimSpace(instance.failure)
+	if message == "" {
+		message = strings.TrimSpace(instance.stderr.String())
+	}
 	if message == "" {
 		message = err.Error()
 	}
@@ -243,6 +299,7 @@ func (instance *Instance) runtimeError(err error) error {
 		Message: message,
 		Cause:   err,
 	}
+	runtimeErr.Class, runtimeErr.RetryAt = classifyError(message, time.Now())
 
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
@@ -0,0 +1,64 @@
+package codex
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+)
+
+var (
+	// tryAgainInPattern matches "try again in 2 hours 5 minutes" and similar relative resets.
+	tryAgainInPattern = regexp.MustCompile(`(?i)try again in\s+((?:\d+\s*(?:days?|hours?|hrs?|minutes?|mins?|seconds?|secs?)[\s,]*(?:and\s+)?)+)`)
+
+	durationPartPattern = regexp.MustCompile(`(?i)(\d+)\s*(d|h|m|s)`)
+
+	// tryAgainAtPattern matches "try again at 3:04 PM".
+	tryAgainAtPattern = regexp.MustCompile(`(?i)try again at\s+(\d{1,2}):(\d{2})\s*(am|pm)?`)
+
+	rateLimitPattern = regexp.MustCompile(`(?i)usage limit|rate limit|429 Too Many Requests`)
+
+	authPattern = regexp.MustCompile(`(?i)not logged in|codex login|401 Unauthorized|invalid api key|refresh token`)
+)
+
+// classifyError recognizes Codex usage limit and authentication messages and extracts the reset time when present.
+func classifyError(message string, now time.Time) (agent.RuntimeErrorClass, *time.Time) {
+	if match := tryAgainInPattern.FindStringSubmatch(message); match != nil {
+		var wait time.Duration
+		for _, part := range durationPartPattern.FindAllStringSubmatch(match[1], -1) {
+			value, _ := strconv.Atoi(part[1])
+			switch strings.ToLower(part[2]) {
+			case "d":
+				wait += time.Duration(value) * 24 * time.Hour
+			case "h":
+				wait += time.Duration(value) * time.Hour
+			case "m":
+				wait += time.Duration(value) * time.Minute
+			case "s":
+				wait += time.Duration(value) * time.Second
+			}
+		}
+
+		return agent.RuntimeErrorRateLimited, utils.ToPointer(now.Add(wait))
+	}
+
+	if match := tryAgainAtPattern.FindStringSubmatch(message); match != nil {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+
+		return agent.RuntimeErrorRateLimited, utils.ToPointer(utils.NextTimeOfDay(now, utils.ClockHour(hour, strings.ToLower(match[3])), minute))
+	}
+
+	if rateLimitPattern.MatchString(message) {
+		return agent.RuntimeErrorRateLimited, nil
+	}
+
+	if authPattern.MatchString(message) {
+		return agent.RuntimeErrorAuth, nil
+	}
+
+	return agent.RuntimeErrorUnknown, nil
+}
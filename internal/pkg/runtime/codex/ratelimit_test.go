@@ -0,0 +1,46 @@
+package codex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	t.Run("try again in duration", func(t *testing.T) {
+		class, retryAt := classifyError("You've hit your usage limit. Upgrade to Pro, or try again in 1 day 2 hours 5 minutes.", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.Equal(t, now.Add(26*time.Hour+5*time.Minute), *retryAt)
+	})
+
+	t.Run("try again at clock time", func(t *testing.T) {
+		class, retryAt := classifyError("You've hit your usage limit. Try again at 3:04 PM.", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.Equal(t, time.Date(2025, 3, 10, 15, 4, 0, 0, time.UTC), *retryAt)
+	})
+
+	t.Run("rate limit without reset", func(t *testing.T) {
+		class, retryAt := classifyError("stream error: exceeded retry limit, last status: 429 Too Many Requests", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		assert.Nil(t, retryAt)
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		class, retryAt := classifyError("unexpected status 401 Unauthorized: Your refresh token has expired", now)
+		assert.Equal(t, agent.RuntimeErrorAuth, class)
+		assert.Nil(t, retryAt)
+	})
+
+	t.Run("other failure", func(t *testing.T) {
+		class, retryAt := classifyError("Not inside a trusted directory", now)
+		assert.Equal(t, agent.RuntimeErrorUnknown, class)
+		assert.Nil(t, retryAt)
+	})
+}
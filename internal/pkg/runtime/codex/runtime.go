@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
@@ -16,8 +18,18 @@ import (
 
 var semverPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
 
+// supportedVersions is the CLI version range whose exec --json event stream the instance parser understands.
+const supportedVersions = ">=0.44.0, <1.0.0"
+
 const Codex = agent.RuntimeKind("codex")
 
+// knownModels is a static fallback list of well-known Codex models. It is not read from the CLI,
+// so it may lag behind the models the CLI accepts, and any other model is passed through unchanged.
+var knownModels = []string{"gpt-5-codex", "gpt-5", "gpt-5-codex-mini"}
+
+// homeEnvironmentVariable points Codex at the agent home directory instead of ~/.codex.
+const homeEnvironmentVariable = "CODEX_HOME"
+
 type Runtime struct {
 }
 
@@ -26,6 +38,8 @@ func NewRuntime() *Runtime {
 }
 
 func (runtime *Runtime) Execute(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, agentConfig agent.Config) (agent.RuntimeInstance, error) {
+	executionInput.SystemPrompt = agentConfig.ResolveSystemPrompt(executionInput)
+
 	logDir, err := cli.ResolveRuntimeLogDir()
 	if err != nil {
 		return nil, err
@@ -36,7 +50,12 @@ func (runtime *Runtime) Execute(ctx context.Context, executionId agent.Execution
 		return nil, fmt.Errorf("convert runtime config: %w", err)
 	}
 
-	instance, err := newInstance(ctx, executionId, executionInput, *runtimeConfig, agentConfig.Runtime.Feature, logDir)
+	environment, err := agentConfig.Environment.Resolve(os.Environ(), homeEnvironmentVariable)
+	if err != nil {
+		return nil, fmt.Errorf("resolve environment: %w", err)
+	}
+
+	instance, err := newInstance(ctx, executionId, executionInput, *runtimeConfig, agentConfig.Runtime.Feature, environment, logDir)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +81,7 @@ func (runtime *Runtime) Discovery(ctx context.Context) (bool, error) {
 
 func (runtime *Runtime) GetDefaultConfig(ctx context.Context) (agent.RuntimeConfig, error) {
 	return Config{
-		RequireWorkspaceRepository: false,
+		RequireWorkspaceRepository: utils.ToPointer(false),
 	}, nil
 }
 
@@ -73,6 +92,40 @@ func (runtime *Runtime) GetDefaultFeatures(ctx context.Context) (agent.RuntimeFe
 	}, nil
 }
 
+func (runtime *Runtime) ValidateConfig(ctx context.Context, runtimeConfig agent.RuntimeConfig) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	config, err := decodeConfig(runtimeConfig)
+	if err != nil {
+		return err
+	}
+
+	return config.Validate()
+}
+
+func (runtime *Runtime) GetCapabilities(ctx context.Context) (agent.RuntimeCapabilities, error) {
+	return agent.RuntimeCapabilities{
+		Resume:              true,
+		AttachmentMimeTypes: []string{"image/png", "image/jpeg", "image/gif", "image/webp"},
+		WebSearchToggle:     true,
+		NetworkAccessToggle: true,
+		ModelOverride:       true,
+		StructuredOutput:    true,
+		SystemPrompt:        true,
+	}, nil
+}
+
+// ListModels returns knownModels, because the Codex CLI cannot report the models it accepts.
+func (runtime *Runtime) ListModels(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(knownModels), nil
+}
+
 func (runtime *Runtime) GetInfo(ctx context.Context) (agent.RuntimeInfo, error) {
 	if err := ctx.Err(); err != nil {
 		return agent.RuntimeInfo{}, err
@@ -93,5 +146,5 @@ func (runtime *Runtime) GetInfo(ctx context.Context) (agent.RuntimeInfo, error)
 		return agent.RuntimeInfo{}, fmt.Errorf("parse codex version from output: %s", strings.TrimSpace(string(output)))
 	}
 
-	return agent.RuntimeInfo{Version: version}, nil
+	return agent.RuntimeInfo{Version: version, SupportedVersions: supportedVersions}, nil
 }
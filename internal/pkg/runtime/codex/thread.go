@@ -0,0 +1,163 @@
+package codex
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/process"
+)
+
+const defaultCodexHome = "~/.codex"
+
+// threadMeta describes a Codex thread recorded in its rollout file.
+type threadMeta struct {
+	ID string
+
+	WorkingDirectory string
+}
+
+type rolloutSessionMeta struct {
+	Type    string `json:"type"`
+	Payload struct {
+		ID  string `json:"id"`
+		Cwd string `json:"cwd"`
+	} `json:"payload"`
+}
+
+// resolveCodexHome returns the Codex home directory seen by a process with the given environment.
+// CODEX_HOME wins; otherwise ~/.codex is resolved against the environment's HOME.
+func resolveCodexHome(environment []string) (string, error) {
+	home, _ := process.LookupEnvironment(environment, homeEnvironmentVariable)
+	if strings.TrimSpace(home) == "" {
+		home = defaultCodexHome
+		if userHome, ok := process.LookupEnvironment(environment, "HOME"); ok && strings.TrimSpace(userHome) != "" {
+			home = filepath.Join(userHome, ".codex")
+		}
+	}
+
+	expanded, err := homedir.Expand(home)
+	if err != nil {
+		return "", fmt.Errorf("expand codex home: %w", err)
+	}
+
+	return expanded, nil
+}
+
+// findThread locates the rollout file of the given thread in the Codex sessions directory.
+func findThread(ctx context.Context, codexHome string, threadID agent.ConversationID) (threadMeta, error) {
+	sessionsDir := filepath.Join(codexHome, "sessions")
+	suffix := string(threadID) + ".jsonl"
+
+	var rolloutPath string
+	err := filepath.WalkDir(sessionsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), suffix) {
+			rolloutPath = path
+			return fs.SkipAll
+		}
+
+		return nil
+	})
+	if err != nil {
+		return threadMeta{}, fmt.Errorf("scan codex sessions: %w", err)
+	}
+
+	if rolloutPath == "" {
+		return threadMeta{}, fmt.Errorf("%w: %s", ErrThreadNotFound, threadID)
+	}
+
+	return readThreadMeta(rolloutPath, threadID)
+}
+
+func readThreadMeta(rolloutPath string, threadID agent.ConversationID) (threadMeta, error) {
+	file, err := os.Open(rolloutPath)
+	if err != nil {
+		return threadMeta{}, fmt.Errorf("open codex rollout: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	meta := threadMeta{ID: string(threadID)}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return threadMeta{}, fmt.Errorf("read codex rollout: %w", err)
+		}
+		return meta, nil
+	}
+
+	var sessionMeta rolloutSessionMeta
+	if err := json.Unmarshal(scanner.Bytes(), &sessionMeta); err != nil {
+		return meta, nil
+	}
+
+	if sessionMeta.Type == "session_meta" {
+		meta.WorkingDirectory = sessionMeta.Payload.Cwd
+	}
+
+	return meta, nil
+}
+
+// validateThread checks that the thread exists and was started in the given working directory.
+// Threads recorded without a working directory are accepted.
+func validateThread(ctx context.Context, codexHome string, threadID agent.ConversationID, workingDir string) error {
+	meta, err := findThread(ctx, codexHome, threadID)
+	if err != nil {
+		return err
+	}
+
+	if meta.WorkingDirectory == "" {
+		return nil
+	}
+
+	if !samePath(meta.WorkingDirectory, workingDir) {
+		return fmt.Errorf("%w: thread %s belongs to %s", ErrThreadWorkingDirectoryMismatch, threadID, meta.WorkingDirectory)
+	}
+
+	return nil
+}
+
+func samePath(a, b string) bool {
+	return canonicalPath(a) == canonicalPath(b)
+}
+
+func canonicalPath(path string) string {
+	cleaned := filepath.Clean(path)
+
+	resolved, err := filepath.EvalSymlinks(cleaned)
+	if err != nil {
+		return cleaned
+	}
+
+	return resolved
+}
+
+var (
+	// ErrThreadNotFound indicates the Codex thread to resume does not exist.
+	ErrThreadNotFound = errors.New("codex thread not found")
+
+	// ErrThreadWorkingDirectoryMismatch indicates the Codex thread was started in a different working directory.
+	ErrThreadWorkingDirectoryMismatch = errors.New("codex thread working directory mismatch")
+)
@@ -0,0 +1,104 @@
+package codex
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRollout(t *testing.T, codexHome string, threadID string, firstLine string) {
+	t.Helper()
+
+	dir := filepath.Join(codexHome, "sessions", "2025", "01", "02")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rollout-2025-01-02T03-04-05-"+threadID+".jsonl"), []byte(firstLine+"\n"), 0644))
+}
+
+func TestValidateThread(t *testing.T) {
+	ctx := context.Background()
+	codexHome := t.TempDir()
+	workingDir := t.TempDir()
+	otherDir := t.TempDir()
+
+	threadID := "0199a213-81c0-7800-8aa1-bbab2a035a53"
+	writeRollout(t, codexHome, threadID, `{"timestamp":"2025-01-02T03:04:05Z","type":"session_meta","payload":{"id":"`+threadID+`","cwd":"`+workingDir+`"}}`)
+
+	legacyThreadID := "0199a213-81c0-7800-8aa1-bbab2a035a54"
+	writeRollout(t, codexHome, legacyThreadID, `{"id":"`+legacyThreadID+`","timestamp":"2025-01-02T03:04:05Z"}`)
+
+	t.Run("same working directory", func(t *testing.T) {
+		err := validateThread(ctx, codexHome, agent.ConversationID(threadID), workingDir)
+		require.NoError(t, err)
+	})
+
+	t.Run("different working directory", func(t *testing.T) {
+		err := validateThread(ctx, codexHome, agent.ConversationID(threadID), otherDir)
+		require.ErrorIs(t, err, ErrThreadWorkingDirectoryMismatch)
+	})
+
+	t.Run("missing thread", func(t *testing.T) {
+		err := validateThread(ctx, codexHome, agent.ConversationID("0199a213-81c0-7800-8aa1-000000000000"), workingDir)
+		require.ErrorIs(t, err, ErrThreadNotFound)
+	})
+
+	t.Run("missing sessions directory", func(t *testing.T) {
+		err := validateThread(ctx, t.TempDir(), agent.ConversationID(threadID), workingDir)
+		require.ErrorIs(t, err, ErrThreadNotFound)
+	})
+
+	t.Run("thread without working directory", func(t *testing.T) {
+		err := validateThread(ctx, codexHome, agent.ConversationID(legacyThreadID), otherDir)
+		require.NoError(t, err)
+	})
+}
+
+func TestResolveCodexHome(t *testing.T) {
+	home, err := resolveCodexHome([]string{"HOME=/home/me", "CODEX_HOME=/srv/codex-work"})
+	require.NoError(t, err)
+	assert.Equal(t, "/srv/codex-work", home)
+
+	home, err = resolveCodexHome([]string{"HOME=/home/me"})
+	require.NoError(t, err)
+	assert.Equal(t, "/home/me/.codex", home)
+}
+
+func TestApplyExecutionInputArguments(t *testing.T) {
+	t.Run("new thread", func(t *testing.T) {
+		args := defaultArguments()
+		require.NoError(t, applyExecutionInputArguments(args, agent.ExecutionInput{}))
+		assert.Equal(t, []string{"-"}, args.Positionals())
+	})
+
+	t.Run("resume thread", func(t *testing.T) {
+		args := defaultArguments()
+		threadID := agent.ConversationID("thread-1")
+		require.NoError(t, applyExecutionInputArguments(args, agent.ExecutionInput{ConversationID: &threadID}))
+		assert.Equal(t, []string{"resume", "thread-1", "-"}, args.Positionals())
+	})
+
+	t.Run("empty thread id", func(t *testing.T) {
+		threadID := agent.ConversationID(" ")
+		err := applyExecutionInputArguments(defaultArguments(), agent.ExecutionInput{ConversationID: &threadID})
+		require.Error(t, err)
+	})
+	t.Run("image attachments", func(t *testing.T) {
+		args := defaultArguments()
+		input := agent.ExecutionInput{Attachments: []agent.ExecutionInputAttachment{
+			{MimeType: "image/png", Path: "/tmp/b.png"},
+			{MimeType: "image/jpeg", Path: "/tmp/a.jpg"},
+		}}
+		require.NoError(t, applyExecutionInputArguments(args, input))
+		assert.Equal(t, []string{"--image=/tmp/b.png", "--image=/tmp/a.jpg"}, args.ToList())
+	})
+	t.Run("system prompt", func(t *testing.T) {
+		args := defaultArguments()
+		input := agent.ExecutionInput{SystemPrompt: "Write \"tests\".\nOnly tests."}
+		require.NoError(t, applyExecutionInputArguments(args, input))
+		assert.Equal(t, []string{`--config=developer_instructions="Write \"tests\".\nOnly tests."`}, args.ToList())
+	})
+}
@@ -0,0 +1,43 @@
+package gemini
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// toolActivity describes a Gemini CLI tool call as agent activity.
+func toolActivity(name string, parameters json.RawMessage, now time.Time) agent.RuntimeActivityEvent {
+	var fields map[string]any
+	_ = json.Unmarshal(parameters, &fields)
+
+	activity := agent.RuntimeActivityEvent{
+		Timestamp: now,
+		Activity:  agent.RuntimeActivityTool,
+		Name:      name,
+	}
+
+	switch name {
+	case "run_shell_command":
+		activity.Activity = agent.RuntimeActivityCommand
+		activity.Detail = stringField(fields, "command")
+	case "write_file", "replace":
+		activity.Activity = agent.RuntimeActivityEdit
+		activity.Detail = stringField(fields, "file_path", "absolute_path")
+	default:
+		activity.Detail = stringField(fields, "absolute_path", "file_path", "path", "pattern", "url", "query")
+	}
+
+	return activity
+}
+
+func stringField(fields map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
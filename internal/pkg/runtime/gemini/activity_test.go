@@ -0,0 +1,38 @@
+package gemini
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolActivity(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		tool       string
+		parameters string
+		activity   agent.RuntimeActivityKind
+		detail     string
+	}{
+		{name: "command", tool: "run_shell_command", parameters: `{"command":"npm test"}`, activity: agent.RuntimeActivityCommand, detail: "npm test"},
+		{name: "write", tool: "write_file", parameters: `{"file_path":"/repo/a.ts","content":"x"}`, activity: agent.RuntimeActivityEdit, detail: "/repo/a.ts"},
+		{name: "replace", tool: "replace", parameters: `{"file_path":"/repo/b.ts"}`, activity: agent.RuntimeActivityEdit, detail: "/repo/b.ts"},
+		{name: "read", tool: "read_file", parameters: `{"absolute_path":"/repo/c.ts"}`, activity: agent.RuntimeActivityTool, detail: "/repo/c.ts"},
+		{name: "missing parameters", tool: "google_web_search", activity: agent.RuntimeActivityTool},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity := toolActivity(tt.tool, json.RawMessage(tt.parameters), now)
+
+			assert.Equal(t, tt.activity, activity.Activity)
+			assert.Equal(t, tt.tool, activity.Name)
+			assert.Equal(t, tt.detail, activity.Detail)
+		})
+	}
+}
@@ -2,20 +2,26 @@ package gemini
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
 )
 
 type arguments struct {
-	flags  map[string]bool
-	values map[string]string
+	flags       map[string]bool
+	values      map[string]string
+	multiValues map[string][]string
+	env         map[string]string
 }
 
 func defaultArguments() *arguments {
 	return &arguments{
-		flags:  map[string]bool{},
-		values: map[string]string{},
+		flags:       map[string]bool{},
+		values:      map[string]string{},
+		multiValues: map[string][]string{},
+		env:         map[string]string{},
 	}
 }
 
@@ -33,6 +39,40 @@ func (a *arguments) SetValue(name string, value any) error {
 	return nil
 }
 
+func (a *arguments) AddValue(name string, value any) error {
+	valueStr, err := a.valueToString(value)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(a.multiValues[name], valueStr) {
+		return nil
+	}
+
+	a.multiValues[name] = append(a.multiValues[name], valueStr)
+	return nil
+}
+
+func (a *arguments) SetEnv(name string, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("empty string")
+	}
+
+	a.env[name] = value
+	return nil
+}
+
+// Env returns additional environment variables in NAME=value form.
+func (a *arguments) Env() []string {
+	var env []string
+
+	for _, name := range slices.Sorted(maps.Keys(a.env)) {
+		env = append(env, fmt.Sprintf("%s=%s", name, a.env[name]))
+	}
+
+	return env
+}
+
 func (a *arguments) valueToString(value any) (string, error) {
 	switch v := value.(type) {
 	case string:
@@ -60,12 +100,18 @@ func (a *arguments) valueToString(value any) (string, error) {
 func (a *arguments) ToList() []string {
 	var list []string
 
-	for flag := range a.flags {
+	for _, flag := range slices.Sorted(maps.Keys(a.flags)) {
 		list = append(list, fmt.Sprintf("--%s", flag))
 	}
 
-	for key, value := range a.values {
-		list = append(list, fmt.Sprintf("--%s=%s", key, value))
+	for _, key := range slices.Sorted(maps.Keys(a.values)) {
+		list = append(list, fmt.Sprintf("--%s=%s", key, a.values[key]))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(a.multiValues)) {
+		for _, value := range a.multiValues[key] {
+			list = append(list, fmt.Sprintf("--%s=%s", key, value))
+		}
 	}
 
 	return list
@@ -2,7 +2,10 @@ package gemini
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/mcuadros/go-defaults"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
@@ -10,10 +13,51 @@ import (
 
 // Config defines runtime options for Gemini execution.
 type Config struct {
-	// No specific configuration needed for Gemini at the moment
+	// ApprovalMode selects how Gemini approves tool calls (default, auto_edit, yolo).
+	ApprovalMode string `json:"approvalMode,omitempty"`
+
+	// IncludeDirectories adds directories outside the working directory to the workspace.
+	IncludeDirectories []string `json:"includeDirectories,omitempty"`
+
+	// AllowedTools lists tools Gemini may use without confirmation.
+	AllowedTools []string `json:"allowedTools,omitempty"`
+
+	// Extensions lists the extensions to enable. All installed extensions are used when empty.
+	Extensions []string `json:"extensions,omitempty"`
+
+	// Checkpointing enables checkpoints of file edits.
+	Checkpointing bool `json:"checkpointing,omitempty"`
+
+	// Sandbox runs tools inside the Gemini sandbox.
+	Sandbox bool `json:"sandbox,omitempty"`
+
+	// SandboxImage selects the container image used by the sandbox.
+	SandboxImage string `json:"sandboxImage,omitempty"`
+
+	// SandboxProfile selects the macOS Seatbelt profile used by the sandbox.
+	SandboxProfile string `json:"sandboxProfile,omitempty"`
 }
 
-func applyRuntimeConfigArguments(args *arguments, config agent.RuntimeConfig) error {
+var approvalModes = []string{"default", "auto_edit", "yolo"}
+
+// Validate checks whether the Gemini runtime configuration is well-formed.
+func (config Config) Validate() error {
+	if config.ApprovalMode != "" && !slices.Contains(approvalModes, config.ApprovalMode) {
+		return fmt.Errorf("%w: %s", ErrApprovalModeInvalid, config.ApprovalMode)
+	}
+
+	for _, list := range [][]string{config.IncludeDirectories, config.AllowedTools, config.Extensions} {
+		for _, value := range list {
+			if strings.TrimSpace(value) == "" {
+				return ErrConfigValueEmpty
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeConfig(config agent.RuntimeConfig) (Config, error) {
 	var geminiConfig Config
 
 	switch typed := config.(type) {
@@ -28,16 +72,73 @@ func applyRuntimeConfigArguments(args *arguments, config agent.RuntimeConfig) er
 	default:
 		payload, err := json.Marshal(config)
 		if err != nil {
-			return fmt.Errorf("marshal gemini runtime config: %w", err)
+			return Config{}, fmt.Errorf("marshal gemini runtime config: %w", err)
 		}
 
 		if err := json.Unmarshal(payload, &geminiConfig); err != nil {
-			return fmt.Errorf("unmarshal gemini runtime config: %w", err)
+			return Config{}, fmt.Errorf("unmarshal gemini runtime config: %w", err)
 		}
 	}
 
 	defaults.SetDefaults(&geminiConfig)
 
+	return geminiConfig, nil
+}
+
+func applyRuntimeConfigArguments(args *arguments, config agent.RuntimeConfig) error {
+	geminiConfig, err := decodeConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := geminiConfig.Validate(); err != nil {
+		return err
+	}
+
+	if geminiConfig.ApprovalMode != "" {
+		if err = args.SetValue("approval-mode", geminiConfig.ApprovalMode); err != nil {
+			return fmt.Errorf("set approval mode: %w", err)
+		}
+	}
+
+	for _, dir := range geminiConfig.IncludeDirectories {
+		if err = args.AddValue("include-directories", dir); err != nil {
+			return fmt.Errorf("add include directory: %w", err)
+		}
+	}
+
+	for _, tool := range geminiConfig.AllowedTools {
+		if err = args.AddValue("allowed-tools", tool); err != nil {
+			return fmt.Errorf("add allowed tool: %w", err)
+		}
+	}
+
+	for _, extension := range geminiConfig.Extensions {
+		if err = args.AddValue("extensions", extension); err != nil {
+			return fmt.Errorf("add extension: %w", err)
+		}
+	}
+
+	if geminiConfig.Checkpointing {
+		args.SetFlag("checkpointing")
+	}
+
+	if geminiConfig.Sandbox {
+		args.SetFlag("sandbox")
+	}
+
+	if geminiConfig.SandboxImage != "" {
+		if err = args.SetValue("sandbox-image", geminiConfig.SandboxImage); err != nil {
+			return fmt.Errorf("set sandbox image: %w", err)
+		}
+	}
+
+	if geminiConfig.SandboxProfile != "" {
+		if err = args.SetEnv("SEATBELT_PROFILE", geminiConfig.SandboxProfile); err != nil {
+			return fmt.Errorf("set sandbox profile: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -75,3 +176,11 @@ func applyExecutionInputArguments(args *arguments, executionInput agent.Executio
 
 	return nil
 }
+
+var (
+	// ErrApprovalModeInvalid indicates the configured approval mode is not supported by Gemini.
+	ErrApprovalModeInvalid = errors.New("gemini approval mode invalid")
+
+	// ErrConfigValueEmpty indicates a configured list contains an empty value.
+	ErrConfigValueEmpty = errors.New("gemini config value empty")
+)
@@ -0,0 +1,68 @@
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRuntimeConfigArguments(t *testing.T) {
+	t.Run("empty config", func(t *testing.T) {
+		args := defaultArguments()
+		require.NoError(t, applyRuntimeConfigArguments(args, nil))
+		assert.Empty(t, args.ToList())
+		assert.Empty(t, args.Env())
+	})
+
+	t.Run("full config", func(t *testing.T) {
+		args := defaultArguments()
+		config := map[string]any{
+			"approvalMode":       "auto_edit",
+			"includeDirectories": []any{"/srv/docs", "/srv/specs"},
+			"allowedTools":       []any{"ShellTool(git status)"},
+			"extensions":         []any{"conductor"},
+			"checkpointing":      true,
+			"sandbox":            true,
+			"sandboxImage":       "ghcr.io/acme/sandbox:1",
+			"sandboxProfile":     "restrictive-closed",
+		}
+
+		require.NoError(t, applyRuntimeConfigArguments(args, config))
+		require.NoError(t, applyRuntimeFeaturesArguments(args, agent.RuntimeFeatures{EnableNetworkAccess: utils.ToPointer(false)}))
+
+		assert.Equal(t, []string{
+			"--checkpointing",
+			"--sandbox",
+			"--approval-mode=auto_edit",
+			"--sandbox-image=ghcr.io/acme/sandbox:1",
+			"--allowed-tools=ShellTool(git status)",
+			"--extensions=conductor",
+			"--include-directories=/srv/docs",
+			"--include-directories=/srv/specs",
+		}, args.ToList())
+		assert.Equal(t, []string{"SEATBELT_PROFILE=restrictive-closed"}, args.Env())
+	})
+
+	t.Run("invalid approval mode", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{ApprovalMode: "auto"})
+		require.ErrorIs(t, err, ErrApprovalModeInvalid)
+	})
+
+	t.Run("empty extension", func(t *testing.T) {
+		err := applyRuntimeConfigArguments(defaultArguments(), Config{Extensions: []string{""}})
+		require.ErrorIs(t, err, ErrConfigValueEmpty)
+	})
+}
+
+func TestRuntime_ValidateConfig(t *testing.T) {
+	runtime := NewRuntime()
+	ctx := context.Background()
+
+	require.NoError(t, runtime.ValidateConfig(ctx, map[string]any{"approvalMode": "yolo"}))
+	require.ErrorIs(t, runtime.ValidateConfig(ctx, map[string]any{"approvalMode": "never"}), ErrApprovalModeInvalid)
+	require.Error(t, runtime.ValidateConfig(ctx, map[string]any{"approvalMode": 1}))
+}
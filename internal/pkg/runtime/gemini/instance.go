@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/process"
 )
 
@@ -35,14 +36,20 @@ type Instance struct {
 
 // geminiEvent represents the structure of JSON events emitted by the Gemini CLI.
 type geminiEvent struct {
-	Type      string `json:"type"`
-	SessionID string `json:"session_id,omitempty"`
-	Role      string `json:"role,omitempty"`
-	Content   string `json:"content,omitempty"`
-	Delta     bool   `json:"delta,omitempty"`
+	Type       string          `json:"type"`
+	SessionID  string          `json:"session_id,omitempty"`
+	Role       string          `json:"role,omitempty"`
+	Content    string          `json:"content,omitempty"`
+	Delta      bool            `json:"delta,omitempty"`
+	ToolName   string          `json:"tool_name,omitempty"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+	Stats      *struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"stats,omitempty"`
 }
 
-func newInstance(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, runtimeConfig Config, runtimeFeatures agent.RuntimeFeatures, logDir string) (*Instance, error) {
+func newInstance(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, runtimeConfig Config, runtimeFeatures agent.RuntimeFeatures, environment []string, logDir string) (*Instance, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -79,6 +86,8 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 
 	cmd := exec.CommandContext(ctx, path, instanceArgumentsList...)
 	
+	cmd.Env = append(environment, runtimeArguments.Env()...)
+
 	if executionInput.WorkingDirectory != nil && strings.TrimSpace(*executionInput.WorkingDirectory) != "" {
 		cmd.Dir = *executionInput.WorkingDirectory
 	} else {
@@ -96,9 +105,9 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 	}
 
 	// Setup logging
-	sessionLogDir := filepath.Join(logDir, "gemini", string(executionId), time.Now().Format("2006-01-02_15-04-05"))
-	if err := os.MkdirAll(sessionLogDir, 0755); err != nil {
-		return nil, fmt.Errorf("create session log directory: %w", err)
+	sessionLogDir, err := cli.CreateRuntimeLogDir(logDir, Gemini, executionId, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	stdinLog, err := os.Create(filepath.Join(sessionLogDir, "stdin.log"))
@@ -120,7 +129,7 @@ func newInstance(ctx context.Context, executionId agent.ExecutionID, executionIn
 	instance.closers = append(instance.closers, stderrLog)
 
 	// Pipe Prompt to Stdin and log it
-	cmd.Stdin = io.TeeReader(strings.NewReader(executionInput.Prompt), stdinLog)
+	cmd.Stdin = io.TeeReader(strings.NewReader(resolvePrompt(executionInput)), stdinLog)
 
 	// Capture stdout for parsing
 	pipe, err := cmd.StdoutPipe()
@@ -224,6 +233,16 @@ func (instance *Instance) watchGeminiEvents(stdoutLog io.Writer) error {
 			if event.Role == "assistant" {
 				// We append content. 
 				instance.result.Response += event.Content
+				instance.emitRuntimeEvent(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: event.Content})
+			}
+		case "tool_use":
+			instance.emitRuntimeEvent(toolActivity(event.ToolName, event.Parameters, time.Now()))
+		case "result":
+			if event.Stats != nil {
+				instance.result.Usage = &agent.RuntimeUsage{
+					InputTokens:  event.Stats.InputTokens,
+					OutputTokens: event.Stats.OutputTokens,
+				}
 			}
 		}
 	}
@@ -245,6 +264,7 @@ func (instance *Instance) runtimeError(err error) error {
 		Message: message,
 		Cause:   err,
 	}
+	runtimeErr.Class, runtimeErr.RetryAt = classifyError(message, time.Now())
 
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
@@ -266,4 +286,16 @@ func (instance *Instance) emitRuntimeEvent(event agent.RuntimeEvent) {
 	default:
 		slog.Warn("Runtime event dropped because the channel is full.", slog.String("eventKind", string(event.Kind())))
 	}
+}
+
+// resolvePrompt puts the system prompt in front of the prompt.
+// Gemini can only replace its built-in system prompt, which would drop its tool-use instructions, so the system prompt
+// travels with the prompt instead.
+func resolvePrompt(executionInput agent.ExecutionInput) string {
+	systemPrompt := strings.TrimSpace(executionInput.SystemPrompt)
+	if systemPrompt == "" {
+		return executionInput.Prompt
+	}
+
+	return systemPrompt + "\n\n" + executionInput.Prompt
 }
\ No newline at end of file
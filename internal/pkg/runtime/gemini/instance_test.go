@@ -0,0 +1,13 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePrompt(t *testing.T) {
+	assert.Equal(t, "Fix the bug.", resolvePrompt(agent.ExecutionInput{Prompt: "Fix the bug."}))
+	assert.Equal(t, "You are a reviewer.\n\nFix the bug.", resolvePrompt(agent.ExecutionInput{Prompt: "Fix the bug.", SystemPrompt: " You are a reviewer.\n"}))
+}
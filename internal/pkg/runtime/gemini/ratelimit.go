@@ -0,0 +1,39 @@
+package gemini
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+)
+
+var (
+	// retryDelayPattern matches "Please retry in 23.5s." and the "retryDelay": "23s" field of API errors.
+	retryDelayPattern = regexp.MustCompile(`(?i)(?:retry in\s+|"retryDelay"\s*:\s*")(\d+(?:\.\d+)?)\s*s`)
+
+	rateLimitPattern = regexp.MustCompile(`(?i)quota exceeded|resource_exhausted|rate limit|\b429\b`)
+
+	authPattern = regexp.MustCompile(`(?i)set an auth method|unauthenticated|api key not valid|invalid api key`)
+)
+
+// classifyError recognizes Gemini quota and authentication errors and extracts the retry delay when present.
+func classifyError(message string, now time.Time) (agent.RuntimeErrorClass, *time.Time) {
+	if !rateLimitPattern.MatchString(message) {
+		if authPattern.MatchString(message) {
+			return agent.RuntimeErrorAuth, nil
+		}
+
+		return agent.RuntimeErrorUnknown, nil
+	}
+
+	if match := retryDelayPattern.FindStringSubmatch(message); match != nil {
+		seconds, err := strconv.ParseFloat(match[1], 64)
+		if err == nil {
+			return agent.RuntimeErrorRateLimited, utils.ToPointer(now.Add(time.Duration(seconds * float64(time.Second))))
+		}
+	}
+
+	return agent.RuntimeErrorRateLimited, nil
+}
@@ -0,0 +1,46 @@
+package gemini
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	t.Run("quota with retry hint", func(t *testing.T) {
+		class, retryAt := classifyError("Quota exceeded for quota metric 'Gemini 2.5 Pro Requests'. Please retry in 23.5s.", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.Equal(t, now.Add(23500*time.Millisecond), *retryAt)
+	})
+
+	t.Run("api error with retry delay", func(t *testing.T) {
+		class, retryAt := classifyError(`{"error":{"code":429,"status":"RESOURCE_EXHAUSTED","details":[{"retryDelay": "41s"}]}}`, now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		require.NotNil(t, retryAt)
+		assert.Equal(t, now.Add(41*time.Second), *retryAt)
+	})
+
+	t.Run("quota without reset", func(t *testing.T) {
+		class, retryAt := classifyError("RESOURCE_EXHAUSTED", now)
+		assert.Equal(t, agent.RuntimeErrorRateLimited, class)
+		assert.Nil(t, retryAt)
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		class, retryAt := classifyError("Please set an Auth method in your settings.json", now)
+		assert.Equal(t, agent.RuntimeErrorAuth, class)
+		assert.Nil(t, retryAt)
+	})
+
+	t.Run("other failure", func(t *testing.T) {
+		class, retryAt := classifyError("Error when talking to Gemini API", now)
+		assert.Equal(t, agent.RuntimeErrorUnknown, class)
+		assert.Nil(t, retryAt)
+	})
+}
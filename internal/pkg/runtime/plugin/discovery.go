@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// Discover scans dirs for runtime plugin executables and returns their paths keyed by runtime kind.
+// Directories are scanned in order and the first executable found for a kind wins.
+// Missing or unreadable directories are skipped.
+func Discover(ctx context.Context, dirs []string) (map[agent.RuntimeKind]string, error) {
+	plugins := map[agent.RuntimeKind]string{}
+
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, ExecutablePrefix) {
+				continue
+			}
+
+			kind := agent.RuntimeKind(strings.TrimSuffix(strings.TrimPrefix(name, ExecutablePrefix), filepath.Ext(name)))
+			if kind == "" {
+				continue
+			}
+
+			if _, found := plugins[kind]; found {
+				continue
+			}
+
+			path := filepath.Join(dir, name)
+			if !isExecutable(path) {
+				continue
+			}
+
+			plugins[kind] = path
+		}
+	}
+
+	return plugins, nil
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if info.IsDir() {
+		return false
+	}
+
+	return info.Mode().Perm()&0111 != 0
+}
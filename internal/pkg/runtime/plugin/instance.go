@@ -0,0 +1,231 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+)
+
+type Instance struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+
+	events chan agent.RuntimeEvent
+	done   chan struct{}
+
+	result agent.RuntimeResult
+	err    error
+
+	stderr strings.Builder
+
+	closers []io.Closer
+}
+
+func newInstance(ctx context.Context, kind agent.RuntimeKind, path string, params ExecuteParams, environment []string, logDir string) (*Instance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	payload, err := encodeRequest(MethodExecute, params)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = environment
+
+	if params.Input.WorkingDirectory != nil && strings.TrimSpace(*params.Input.WorkingDirectory) != "" {
+		cmd.Dir = *params.Input.WorkingDirectory
+	} else {
+		workingDir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("resolve working directory: %w", err)
+		}
+		cmd.Dir = workingDir
+	}
+
+	instance := &Instance{
+		cmd:    cmd,
+		events: make(chan agent.RuntimeEvent, 10),
+		done:   make(chan struct{}),
+	}
+
+	// The logs are closed by run once the plugin started, and here on every earlier failure.
+	started := false
+	defer func() {
+		if !started {
+			instance.closeLogs()
+		}
+	}()
+
+	sessionLogDir, err := cli.CreateRuntimeLogDir(logDir, kind, params.ExecutionID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	stdinLog, err := os.Create(filepath.Join(sessionLogDir, "stdin.log"))
+	if err != nil {
+		return nil, fmt.Errorf("create stdin log: %w", err)
+	}
+	instance.closers = append(instance.closers, stdinLog)
+
+	stdoutLog, err := os.Create(filepath.Join(sessionLogDir, "stdout.log"))
+	if err != nil {
+		return nil, fmt.Errorf("create stdout log: %w", err)
+	}
+	instance.closers = append(instance.closers, stdoutLog)
+
+	stderrLog, err := os.Create(filepath.Join(sessionLogDir, "stderr.log"))
+	if err != nil {
+		return nil, fmt.Errorf("create stderr log: %w", err)
+	}
+	instance.closers = append(instance.closers, stderrLog)
+
+	cmd.Stdin = io.TeeReader(bytes.NewReader(payload), stdinLog)
+
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("capture plugin stdout: %w", err)
+	}
+	instance.stdout = pipe
+
+	cmd.Stderr = io.MultiWriter(&instance.stderr, stderrLog)
+
+	if err := instance.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start plugin %s: %w", kind, err)
+	}
+	started = true
+
+	go instance.run(stdoutLog)
+
+	return instance, nil
+}
+
+func (instance *Instance) run(stdoutLog io.Writer) {
+	defer close(instance.done)
+	defer close(instance.events)
+	defer instance.closeLogs()
+
+	response, readErr := readResponse(io.TeeReader(instance.stdout, stdoutLog), instance.handleNotification)
+	if readErr != nil {
+		_, _ = io.Copy(io.Discard, instance.stdout)
+	}
+
+	waitErr := instance.cmd.Wait()
+
+	if readErr != nil {
+		if waitErr != nil {
+			instance.err = instance.runtimeError(waitErr)
+			return
+		}
+
+		instance.err = &agent.RuntimeExecutionError{
+			Message: readErr.Error(),
+			Cause:   readErr,
+		}
+		return
+	}
+
+	if response.Error != nil {
+		instance.err = response.Error.toRuntimeError(MethodExecute)
+		return
+	}
+
+	if err := json.Unmarshal(response.Result, &instance.result); err != nil {
+		instance.err = &agent.RuntimeExecutionError{
+			Message: fmt.Sprintf("unmarshal plugin result: %s", err),
+			Cause:   err,
+		}
+		return
+	}
+
+	if waitErr != nil {
+		instance.err = instance.runtimeError(waitErr)
+	}
+}
+
+// closeLogs closes the log files of the instance.
+func (instance *Instance) closeLogs() {
+	for _, closer := range instance.closers {
+		_ = closer.Close()
+	}
+}
+
+func (instance *Instance) Events() <-chan agent.RuntimeEvent {
+	return instance.events
+}
+
+func (instance *Instance) Wait(ctx context.Context) (agent.RuntimeResult, error) {
+	select {
+	case <-instance.done:
+		return instance.result, instance.err
+	case <-ctx.Done():
+		return agent.RuntimeResult{}, ctx.Err()
+	}
+}
+
+func (instance *Instance) handleNotification(msg message) {
+	if msg.Method != MethodEvent {
+		slog.Debug("Skipping unknown notification from runtime plugin", slog.String("method", msg.Method))
+		return
+	}
+
+	var envelope agent.RuntimeEventEnvelope
+	if err := json.Unmarshal(msg.Params, &envelope); err != nil {
+		slog.Warn("Failed to unmarshal runtime event envelope from runtime plugin", slog.Any("error", err))
+		return
+	}
+
+	event, err := envelope.Decode()
+	if err != nil {
+		slog.Debug("Skipping runtime event from runtime plugin", slog.String("eventKind", string(envelope.Kind)), slog.Any("error", err))
+		return
+	}
+
+	instance.emitRuntimeEvent(event)
+}
+
+func (instance *Instance) runtimeError(err error) error {
+	message := strings.TrimSpace(instance.stderr.String())
+	if message == "" {
+		message = err.Error()
+	}
+
+	runtimeErr := &agent.RuntimeExecutionError{
+		Message: message,
+		Cause:   err,
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		runtimeErr.ExitCode = &code
+	}
+
+	return runtimeErr
+}
+
+func (instance *Instance) emitRuntimeEvent(event agent.RuntimeEvent) {
+	if instance.events == nil {
+		return
+	}
+
+	select {
+	case instance.events <- event:
+		slog.Debug("Runtime event emitted.", slog.String("eventKind", string(event.Kind())))
+	default:
+		slog.Warn("Runtime event dropped because the channel is full.", slog.String("eventKind", string(event.Kind())))
+	}
+}
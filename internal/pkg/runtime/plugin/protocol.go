@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// ExecutablePrefix is the file name prefix of runtime plugin executables.
+// The remaining part of the file name is used as the runtime kind.
+const ExecutablePrefix = "briefkit-runtime-"
+
+const protocolVersion = "2.0"
+
+const errorCodeMethodNotFound = -32601
+
+// Method names supported by the runtime plugin protocol.
+const (
+	// MethodDiscovery maps onto agent.Runtime.Discovery and returns a boolean.
+	MethodDiscovery = "discovery"
+
+	// MethodInfo maps onto agent.Runtime.GetInfo and returns agent.RuntimeInfo.
+	MethodInfo = "info"
+
+	// MethodDefaultConfig maps onto agent.Runtime.GetDefaultConfig and returns a JSON object.
+	MethodDefaultConfig = "defaultConfig"
+
+	// MethodDefaultFeatures maps onto agent.Runtime.GetDefaultFeatures and returns agent.RuntimeFeatures.
+	MethodDefaultFeatures = "defaultFeatures"
+
+	// MethodValidateConfig maps onto agent.Runtime.ValidateConfig and returns null.
+	// Plugins that do not implement it accept every configuration.
+	MethodValidateConfig = "validateConfig"
+
+	// MethodCapabilities maps onto agent.Runtime.GetCapabilities and returns agent.RuntimeCapabilities.
+	// Plugins that do not implement it report no capabilities.
+	MethodCapabilities = "capabilities"
+
+	// MethodModels maps onto agent.Runtime.ListModels and returns a list of model identifiers.
+	// Plugins that do not implement it report no models.
+	MethodModels = "models"
+
+	// MethodExecute maps onto agent.Runtime.Execute and returns agent.RuntimeResult.
+	// Runtime events are streamed as MethodEvent notifications before the final response.
+	MethodExecute = "execute"
+
+	// MethodEvent is the notification sent by the plugin for every runtime event.
+	// Its params carry an agent.RuntimeEventEnvelope.
+	MethodEvent = "event"
+)
+
+// ValidateConfigParams are the params of the MethodValidateConfig request.
+type ValidateConfigParams struct {
+	// Config is the runtime-specific configuration to validate.
+	Config agent.RuntimeConfig `json:"config"`
+}
+
+// ExecuteParams are the params of the MethodExecute request.
+type ExecuteParams struct {
+	// ExecutionID identifies the execution being run.
+	ExecutionID agent.ExecutionID `json:"executionId"`
+
+	// Input is the execution input to run.
+	Input agent.ExecutionInput `json:"input"`
+
+	// Config is the agent configuration snapshot of the execution.
+	Config agent.Config `json:"config"`
+}
+
+// ErrorData carries runtime specific details of a failed request.
+type ErrorData struct {
+	// ExitCode is the exit code of the underlying agent process, when available.
+	ExitCode *int `json:"exitCode,omitempty"`
+
+	// Class categorizes the failure, for example "rate-limited".
+	Class agent.RuntimeErrorClass `json:"class,omitempty"`
+
+	// RetryAt is when the failure condition clears, for example a rate limit reset.
+	RetryAt *time.Time `json:"retryAt,omitempty"`
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// message is either a response to a request or a notification sent by the plugin.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *ErrorData `json:"data,omitempty"`
+}
+
+// Error returns the error message reported by the plugin.
+func (err *responseError) Error() string {
+	return fmt.Sprintf("plugin error %d: %s", err.Code, err.Message)
+}
+
+func (err *responseError) toRuntimeError(method string) error {
+	runtimeErr := &agent.RuntimeExecutionError{
+		Message: err.Message,
+		Cause:   fmt.Errorf("plugin %s: %w", method, err),
+	}
+
+	if err.Data != nil {
+		runtimeErr.ExitCode = err.Data.ExitCode
+		runtimeErr.Class = err.Data.Class
+		runtimeErr.RetryAt = err.Data.RetryAt
+	}
+
+	return runtimeErr
+}
+
+func (msg message) isNotification() bool {
+	return msg.ID == nil && msg.Method != ""
+}
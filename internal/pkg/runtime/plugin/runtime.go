@@ -0,0 +1,289 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
+)
+
+// homeEnvironmentVariable passes the agent home directory to the plugin.
+const homeEnvironmentVariable = "BRIEFKIT_RUNTIME_HOME"
+
+// DefaultCallTimeout bounds every plugin call other than execute unless the runtime is created with another limit.
+const DefaultCallTimeout = 30 * time.Second
+
+// callWaitDelay is how long a call waits for the output of a killed plugin to close,
+// so that children of the plugin holding its stdout cannot keep the call running.
+const callWaitDelay = time.Second
+
+// Runtime is an agent.Runtime backed by an external plugin executable speaking
+// JSON-RPC 2.0 over stdio. Every call spawns the plugin, writes a single request
+// line to its stdin and reads newline-delimited messages from its stdout.
+type Runtime struct {
+	kind        agent.RuntimeKind
+	path        string
+	callTimeout time.Duration
+}
+
+// RuntimeOption configures a plugin runtime.
+type RuntimeOption func(*Runtime)
+
+// WithCallTimeout limits how long the plugin may take to answer a call other than execute.
+// A zero or negative timeout keeps DefaultCallTimeout.
+func WithCallTimeout(timeout time.Duration) RuntimeOption {
+	return func(runtime *Runtime) {
+		if timeout > 0 {
+			runtime.callTimeout = timeout
+		}
+	}
+}
+
+// NewRuntime creates a runtime backed by the plugin executable at path.
+func NewRuntime(kind agent.RuntimeKind, path string, options ...RuntimeOption) *Runtime {
+	runtime := &Runtime{
+		kind:        kind,
+		path:        path,
+		callTimeout: DefaultCallTimeout,
+	}
+
+	for _, option := range options {
+		option(runtime)
+	}
+
+	return runtime
+}
+
+// Execute starts the plugin with an execute request and streams its events.
+func (runtime *Runtime) Execute(ctx context.Context, executionId agent.ExecutionID, executionInput agent.ExecutionInput, agentConfig agent.Config) (agent.RuntimeInstance, error) {
+	executionInput.SystemPrompt = agentConfig.ResolveSystemPrompt(executionInput)
+
+	logDir, err := cli.ResolveRuntimeLogDir()
+	if err != nil {
+		return nil, err
+	}
+
+	params := ExecuteParams{
+		ExecutionID: executionId,
+		Input:       executionInput,
+		Config:      agentConfig,
+	}
+
+	environment, err := agentConfig.Environment.Resolve(os.Environ(), homeEnvironmentVariable)
+	if err != nil {
+		return nil, fmt.Errorf("resolve environment: %w", err)
+	}
+
+	instance, err := newInstance(ctx, runtime.kind, runtime.path, params, environment, logDir)
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Discovery asks the plugin whether its underlying agent is available.
+func (runtime *Runtime) Discovery(ctx context.Context) (bool, error) {
+	var found bool
+	if err := runtime.call(ctx, MethodDiscovery, nil, &found); err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// GetDefaultConfig returns the default runtime configuration reported by the plugin.
+func (runtime *Runtime) GetDefaultConfig(ctx context.Context) (agent.RuntimeConfig, error) {
+	var config map[string]any
+	if err := runtime.call(ctx, MethodDefaultConfig, nil, &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// GetDefaultFeatures returns the default runtime features reported by the plugin.
+func (runtime *Runtime) GetDefaultFeatures(ctx context.Context) (agent.RuntimeFeatures, error) {
+	var features agent.RuntimeFeatures
+	if err := runtime.call(ctx, MethodDefaultFeatures, nil, &features); err != nil {
+		return agent.RuntimeFeatures{}, err
+	}
+
+	return features, nil
+}
+
+// ValidateConfig asks the plugin to validate its runtime configuration.
+func (runtime *Runtime) ValidateConfig(ctx context.Context, config agent.RuntimeConfig) error {
+	var result json.RawMessage
+	err := runtime.call(ctx, MethodValidateConfig, ValidateConfigParams{Config: config}, &result)
+	if err != nil && !isMethodNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// GetCapabilities returns the capabilities reported by the plugin.
+func (runtime *Runtime) GetCapabilities(ctx context.Context) (agent.RuntimeCapabilities, error) {
+	var capabilities agent.RuntimeCapabilities
+	err := runtime.call(ctx, MethodCapabilities, nil, &capabilities)
+	if err != nil {
+		if isMethodNotFound(err) {
+			return agent.RuntimeCapabilities{}, nil
+		}
+		return agent.RuntimeCapabilities{}, err
+	}
+
+	return capabilities, nil
+}
+
+// ListModels returns the model identifiers reported by the plugin.
+func (runtime *Runtime) ListModels(ctx context.Context) ([]string, error) {
+	var models []string
+	err := runtime.call(ctx, MethodModels, nil, &models)
+	if err != nil {
+		if isMethodNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// GetInfo returns the runtime metadata reported by the plugin.
+func (runtime *Runtime) GetInfo(ctx context.Context) (agent.RuntimeInfo, error) {
+	var info agent.RuntimeInfo
+	if err := runtime.call(ctx, MethodInfo, nil, &info); err != nil {
+		return agent.RuntimeInfo{}, err
+	}
+
+	return info, nil
+}
+
+// call runs the plugin for a single request and decodes its result.
+// Returns ErrCallTimeout when the plugin does not answer within the call timeout.
+func (runtime *Runtime) call(ctx context.Context, method string, params any, result any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, runtime.callTimeout)
+	defer cancel()
+
+	payload, err := encodeRequest(method, params)
+	if err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	var stderr strings.Builder
+
+	cmd := exec.CommandContext(callCtx, runtime.path)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = callWaitDelay
+
+	runErr := cmd.Run()
+	if errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("%w: plugin %s %s after %s", ErrCallTimeout, runtime.kind, method, runtime.callTimeout)
+	}
+
+	response, err := readResponse(&stdout, nil)
+	if err != nil {
+		if runErr != nil {
+			return fmt.Errorf("run plugin %s: %w: %s", runtime.kind, runErr, strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("plugin %s %s: %w", runtime.kind, method, err)
+	}
+
+	if response.Error != nil {
+		return response.Error.toRuntimeError(method)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unmarshal plugin %s %s result: %w", runtime.kind, method, err)
+	}
+
+	return nil
+}
+
+func isMethodNotFound(err error) bool {
+	var responseErr *responseError
+	return errors.As(err, &responseErr) && responseErr.Code == errorCodeMethodNotFound
+}
+
+func encodeRequest(method string, params any) ([]byte, error) {
+	payload, err := json.Marshal(request{
+		JSONRPC: protocolVersion,
+		ID:      1,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal plugin %s request: %w", method, err)
+	}
+
+	return append(payload, '\n'), nil
+}
+
+// readResponse scans newline-delimited messages until the response is found.
+// Notifications are passed to onNotification when it is not nil.
+func readResponse(reader io.Reader, onNotification func(message)) (message, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "{") {
+			slog.Debug("Skipping non-JSON line from runtime plugin", slog.String("line", line))
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			slog.Warn("Failed to unmarshal JSON candidate from runtime plugin", slog.String("line", line), slog.Any("error", err))
+			continue
+		}
+
+		if msg.isNotification() {
+			if onNotification != nil {
+				onNotification(msg)
+			}
+			continue
+		}
+
+		if msg.ID != nil {
+			return msg, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return message{}, fmt.Errorf("read plugin output: %w", err)
+	}
+
+	return message{}, ErrResponseMissing
+}
+
+var (
+	// ErrResponseMissing indicates the plugin exited without sending a response.
+	ErrResponseMissing = errors.New("plugin response missing")
+
+	// ErrCallTimeout indicates the plugin did not answer a call within the call timeout.
+	ErrCallTimeout = errors.New("plugin call timed out")
+)
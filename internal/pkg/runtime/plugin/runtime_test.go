@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPluginScript = `#!/bin/sh
+read request
+case "$request" in
+  *'"method":"discovery"'*)
+    echo '{"jsonrpc":"2.0","id":1,"result":true}' ;;
+  *'"method":"info"'*)
+    echo 'plugin banner'
+    echo '{"jsonrpc":"2.0","id":1,"result":{"version":"1.2.3"}}' ;;
+  *'"method":"defaultConfig"'*)
+    echo '{"jsonrpc":"2.0","id":1,"result":{"mode":"fast"}}' ;;
+  *'"method":"capabilities"'*)
+    sleep 10 ;;
+  *'"method":"defaultFeatures"'*)
+    echo '{"jsonrpc":"2.0","id":1,"result":{"enableWebSearch":true,"enableNetworkAccess":null}}' ;;
+  *'"prompt":"fail"'*)
+    echo '{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"agent crashed","data":{"exitCode":3}}}' ;;
+  *'"method":"execute"'*)
+    echo '{"jsonrpc":"2.0","method":"event","params":{"kind":"runtime-started","payload":{"timestamp":"2025-01-02T03:04:05Z"}}}'
+    echo '{"jsonrpc":"2.0","method":"event","params":{"kind":"runtime-finished","payload":{"timestamp":"2025-01-02T03:04:06Z"}}}'
+    echo '{"jsonrpc":"2.0","id":1,"result":{"response":"hello","conversationId":"conv-1"}}' ;;
+  *)
+    echo '{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}' ;;
+esac
+`
+
+func writeTestPlugin(t *testing.T, dir string, kind string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, ExecutablePrefix+kind)
+	require.NoError(t, os.WriteFile(path, []byte(testPluginScript), 0755))
+
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+
+	firstPath := writeTestPlugin(t, first, "acme")
+	writeTestPlugin(t, second, "acme")
+	secondPath := writeTestPlugin(t, second, "other")
+	require.NoError(t, os.WriteFile(filepath.Join(second, ExecutablePrefix+"plain"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(second, "unrelated"), []byte("x"), 0755))
+
+	plugins, err := Discover(context.Background(), []string{"", filepath.Join(first, "missing"), first, second})
+	require.NoError(t, err)
+	assert.Equal(t, map[agent.RuntimeKind]string{
+		"acme":  firstPath,
+		"other": secondPath,
+	}, plugins)
+}
+
+func TestRuntime(t *testing.T) {
+	ctx := context.Background()
+	path := writeTestPlugin(t, t.TempDir(), "acme")
+	runtime := NewRuntime("acme", path)
+
+	t.Run("discovery", func(t *testing.T) {
+		found, err := runtime.Discovery(ctx)
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("info", func(t *testing.T) {
+		info, err := runtime.GetInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", info.Version)
+	})
+
+	t.Run("default config", func(t *testing.T) {
+		config, err := runtime.GetDefaultConfig(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"mode": "fast"}, config)
+	})
+
+	t.Run("default features", func(t *testing.T) {
+		features, err := runtime.GetDefaultFeatures(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, utils.ToPointer(true), features.EnableWebSearch)
+		assert.Nil(t, features.EnableNetworkAccess)
+	})
+
+	t.Run("call timeout", func(t *testing.T) {
+		started := time.Now()
+		_, err := NewRuntime("acme", path, WithCallTimeout(100*time.Millisecond)).GetCapabilities(ctx)
+		require.ErrorIs(t, err, ErrCallTimeout)
+		assert.Less(t, time.Since(started), 5*time.Second)
+	})
+}
+
+func TestRuntime_Execute(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("BRIEFKIT_RUNTIME_LOG_DIR", t.TempDir())
+	path := writeTestPlugin(t, t.TempDir(), "acme")
+	runtime := NewRuntime("acme", path)
+	workingDir := t.TempDir()
+
+	t.Run("streams events and returns result", func(t *testing.T) {
+		instance, err := runtime.Execute(ctx, agent.NewExecutionID(), agent.ExecutionInput{Prompt: "hi", WorkingDirectory: &workingDir}, agent.Config{})
+		require.NoError(t, err)
+
+		var kinds []agent.RuntimeEventKind
+		for event := range instance.Events() {
+			kinds = append(kinds, event.Kind())
+		}
+		assert.Equal(t, []agent.RuntimeEventKind{agent.RuntimeEventStarted, agent.RuntimeEventFinished}, kinds)
+
+		result, err := instance.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.RuntimeResult{Response: "hello", ConversationID: "conv-1"}, result)
+	})
+
+	t.Run("maps error response", func(t *testing.T) {
+		instance, err := runtime.Execute(ctx, agent.NewExecutionID(), agent.ExecutionInput{Prompt: "fail", WorkingDirectory: &workingDir}, agent.Config{})
+		require.NoError(t, err)
+
+		_, err = instance.Wait(ctx)
+		var runtimeErr *agent.RuntimeExecutionError
+		require.ErrorAs(t, err, &runtimeErr)
+		assert.Equal(t, "agent crashed", runtimeErr.Message)
+		require.NotNil(t, runtimeErr.ExitCode)
+		assert.Equal(t, 3, *runtimeErr.ExitCode)
+	})
+}
@@ -2,12 +2,18 @@ package runtime
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"sort"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/cli"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/runtime/claude"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/runtime/codex"
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/runtime/gemini"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/runtime/plugin"
 )
 
 type Registry struct {
@@ -24,6 +30,35 @@ func NewRegistry() *Registry {
 	}
 }
 
+// LoadPlugins registers external runtime plugins found in pluginDir and on PATH.
+// Plugins never replace built-in runtimes; a plugin in pluginDir takes precedence over one on PATH.
+// Every plugin gets the call timeout configured for its kind.
+func (registry *Registry) LoadPlugins(ctx context.Context, pluginDir string) error {
+	dirs := append([]string{pluginDir}, filepath.SplitList(os.Getenv("PATH"))...)
+
+	plugins, err := plugin.Discover(ctx, dirs)
+	if err != nil {
+		return fmt.Errorf("discover runtime plugins: %w", err)
+	}
+
+	for kind, path := range plugins {
+		if _, builtin := registry.runtime[kind]; builtin {
+			slog.Warn("Runtime plugin ignored because it shadows a built-in runtime.", slog.String("runtimeKind", string(kind)), slog.String("path", path))
+			continue
+		}
+
+		timeout, err := cli.ResolveRuntimePluginTimeout(kind)
+		if err != nil {
+			return fmt.Errorf("load runtime plugin %s: %w", kind, err)
+		}
+
+		registry.runtime[kind] = plugin.NewRuntime(kind, path, plugin.WithCallTimeout(timeout))
+		slog.Debug("Runtime plugin loaded.", slog.String("runtimeKind", string(kind)), slog.String("path", path))
+	}
+
+	return nil
+}
+
 func (registry Registry) Get(ctx context.Context, kind agent.RuntimeKind) (agent.Runtime, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -70,6 +70,22 @@ func (r *ConfigRepository) Update(ctx context.Context, id agent.AgentID, config
 	return nil
 }
 
+// Delete removes the agent config file.
+func (r *ConfigRepository) Delete(ctx context.Context, id agent.AgentID) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.fs.Remove(r.configFilePath(id)); err != nil {
+		if os.IsNotExist(err) {
+			return agent.ErrAgentConfigNotFound
+		}
+		return fmt.Errorf("delete agent config %s: %w", id, err)
+	}
+
+	return nil
+}
+
 // List returns the identifiers of all available agent configs.
 func (r *ConfigRepository) List(ctx context.Context) ([]agent.AgentID, error) {
 	entries, err := afero.ReadDir(r.fs, r.basePath)
@@ -35,11 +35,7 @@ func TestConfigRepository_UpdateGet(t *testing.T) {
 
 	id := agent.AgentID("codex-1")
 	config := agent.Config{
-		Runtime: struct {
-			Kind    agent.RuntimeKind     `json:"kind"`
-			Config  agent.RuntimeConfig   `json:"config"`
-			Feature agent.RuntimeFeatures `json:"feature,omitempty"`
-		}{
+		Runtime: agent.ConfigRuntime{
 			Kind: agent.RuntimeKind("codex"),
 			Config: map[string]any{
 				"path": "/bin/codex",
@@ -70,11 +66,7 @@ func TestConfigRepository_Exists(t *testing.T) {
 
 	id := agent.AgentID("codex")
 	config := agent.Config{
-		Runtime: struct {
-			Kind    agent.RuntimeKind     `json:"kind"`
-			Config  agent.RuntimeConfig   `json:"config"`
-			Feature agent.RuntimeFeatures `json:"feature,omitempty"`
-		}{
+		Runtime: agent.ConfigRuntime{
 			Kind: agent.RuntimeKind("codex"),
 			Config: map[string]any{
 				"path": "/bin/codex",
@@ -122,6 +114,32 @@ func TestConfigRepository_Get(t *testing.T) {
 	})
 }
 
+func TestConfigRepository_Delete(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	basePath := "/tmp/test-agents"
+	repo, err := NewConfigRepository(basePath, memFs)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Update(ctx, "codex", agent.Config{Runtime: agent.ConfigRuntime{Kind: "codex"}}))
+
+	t.Run("existing config", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, "codex"))
+
+		exists, err := repo.Exists(ctx, "codex")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("missing config", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(ctx, "codex"), agent.ErrAgentConfigNotFound)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(ctx, "Codex"), agent.ErrAgentIDInvalid)
+	})
+}
+
 func TestConfigRepository_List(t *testing.T) {
 	memFs := afero.NewMemMapFs()
 	basePath := "/tmp/test-agents"
@@ -134,20 +152,12 @@ func TestConfigRepository_List(t *testing.T) {
 	assert.Empty(t, ids)
 
 	require.NoError(t, repo.Update(ctx, agent.AgentID("codex"), agent.Config{
-		Runtime: struct {
-			Kind    agent.RuntimeKind     `json:"kind"`
-			Config  agent.RuntimeConfig   `json:"config"`
-			Feature agent.RuntimeFeatures `json:"feature,omitempty"`
-		}{
+		Runtime: agent.ConfigRuntime{
 			Kind: agent.RuntimeKind("codex"),
 		},
 	}))
 	require.NoError(t, repo.Update(ctx, agent.AgentID("claude-code"), agent.Config{
-		Runtime: struct {
-			Kind    agent.RuntimeKind     `json:"kind"`
-			Config  agent.RuntimeConfig   `json:"config"`
-			Feature agent.RuntimeFeatures `json:"feature,omitempty"`
-		}{
+		Runtime: agent.ConfigRuntime{
 			Kind: agent.RuntimeKind("claude-code"),
 		},
 	}))
@@ -2,10 +2,10 @@ package fs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"time"
 
 	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
@@ -14,7 +14,10 @@ import (
 
 const (
 	executionAgentConfigFileName = "agent.json"
+	executionEventsFileName      = "events.ndjson"
 	executionInputFileName       = "input.json"
+	executionLockFileName        = "execution.lock"
+	executionMetadataFileName    = "metadata.json"
 	executionResultFileName      = "result.json"
 	executionStatusFileName      = "status.json"
 )
@@ -40,11 +43,19 @@ func NewExecutionRepository(basePath string, fs afero.Fs) (*Repository, error) {
 }
 
 // Create persists a new execution and returns its identifier.
-func (r *Repository) Create(ctx context.Context, input agent.ExecutionInput, agentConfig agent.Config) (agent.ExecutionID, error) {
+func (r *Repository) Create(ctx context.Context, input agent.ExecutionInput, agentConfig agent.Config, options ...agent.ExecutionOption) (agent.ExecutionID, error) {
 	if err := input.Validate(); err != nil {
 		return agent.EmptyExecutionID, err
 	}
 
+	if input.Model != nil {
+		model, err := agentConfig.Models.Resolve(*input.Model)
+		if err != nil {
+			return agent.EmptyExecutionID, err
+		}
+		input.Model = &model
+	}
+
 	id := agent.NewExecutionID()
 	executionPath := filepath.Join(r.basePath, string(id))
 
@@ -62,6 +73,11 @@ func (r *Repository) Create(ctx context.Context, input agent.ExecutionInput, age
 		return agent.EmptyExecutionID, err
 	}
 
+	metadataFilePath := filepath.Join(executionPath, executionMetadataFileName)
+	if err := writeJSON(r.fs, metadataFilePath, agent.NewExecutionMetadata(options...)); err != nil {
+		return agent.EmptyExecutionID, err
+	}
+
 	now := time.Now()
 	status := agent.ExecutionStatus{
 		CreatedAt: now,
@@ -74,6 +90,10 @@ func (r *Repository) Create(ctx context.Context, input agent.ExecutionInput, age
 		return agent.EmptyExecutionID, err
 	}
 
+	if err := r.execution(id).index(ctx); err != nil {
+		return agent.EmptyExecutionID, err
+	}
+
 	return id, nil
 }
 
@@ -102,48 +122,109 @@ func (r *Repository) Get(ctx context.Context, id agent.ExecutionID) (agent.Execu
 		return nil, agent.ErrExecutionNotFound
 	}
 
-	return &Execution{
-		id:       id,
-		basePath: r.basePath,
-		fs:       r.fs,
-	}, nil
+	return r.execution(id), nil
 }
 
-// Find returns execution identifiers matching the provided filters.
-func (r *Repository) Find(ctx context.Context, filters ...agent.ExecutionFilter) ([]agent.ExecutionID, error) {
-	_ = filters
+// Delete removes the execution directory. Its index entries are dropped by Find and RebuildIndex.
+// The execution lock is held while the status is checked, so a runner cannot start it in between.
+func (r *Repository) Delete(ctx context.Context, id agent.ExecutionID, options ...agent.ExecutionDeleteOption) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
 
-	entries, err := afero.ReadDir(r.fs, r.basePath)
+	exists, err := r.Exists(ctx, id)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []agent.ExecutionID{}, nil
+		return err
+	}
+
+	if !exists {
+		return agent.ErrExecutionNotFound
+	}
+
+	// An execution without a status was never completely created and has no runner to wait for.
+	execution := r.execution(id)
+	unlock, err := execution.lock()
+	if err != nil && !errors.Is(err, agent.ErrExecutionNotFound) {
+		return err
+	}
+
+	if err == nil {
+		defer unlock()
+
+		status, err := execution.GetStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		if status.State.IsActive() && !agent.NewExecutionDeleteOptions(options...).Force {
+			return fmt.Errorf("%w: %s", agent.ErrExecutionActive, status.State)
 		}
+	}
+
+	if err := r.fs.RemoveAll(filepath.Join(r.basePath, string(id))); err != nil {
+		return fmt.Errorf("delete execution %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// Find returns execution identifiers matching the provided filters, in the query sort order.
+// Filters are answered from the execution index; executions missing from it are indexed on the way.
+func (r *Repository) Find(ctx context.Context, filters ...agent.ExecutionFilter) ([]agent.ExecutionID, error) {
+	query := agent.NewExecutionQuery(filters...)
+	if err := query.Validate(); err != nil {
 		return nil, err
 	}
 
-	ids := make([]agent.ExecutionID, 0, len(entries))
-	for _, entry := range entries {
+	ids, err := r.listIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := r.readIndex(ctx, len(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]agent.ExecutionRecord, 0, len(ids))
+	for _, id := range ids {
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
 
-		if !entry.IsDir() {
+		if record, ok := index[id]; ok {
+			records = append(records, record)
 			continue
 		}
 
-		id := agent.ExecutionID(entry.Name())
-		if err := id.Validate(); err != nil {
+		isDir, err := afero.IsDir(r.fs, filepath.Join(r.basePath, string(id)))
+		if err != nil || !isDir {
 			continue
 		}
 
-		ids = append(ids, id)
-	}
+		if !query.NeedsRecords() {
+			records = append(records, agent.ExecutionRecord{ID: id})
+			continue
+		}
+
+		execution := r.execution(id)
+		record, err := execution.record(ctx)
+		if err != nil {
+			if errors.Is(err, agent.ErrExecutionNotFound) {
+				// Create writes the status last, so the execution is still being created.
+				continue
+			}
+			return nil, fmt.Errorf("load execution %s: %w", id, err)
+		}
 
-	sort.Slice(ids, func(i, j int) bool {
-		return ids[i] < ids[j]
-	})
+		if err := execution.appendIndex(record); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
 
-	return ids, nil
+	return query.Apply(records)
 }
 
 // Execution is an implementation of agent.Execution for the file system store.
@@ -165,14 +246,48 @@ func (e *Execution) agentConfigFilePath() string {
 	return filepath.Join(e.executionDirPath(), executionAgentConfigFileName)
 }
 
+func (e *Execution) metadataFilePath() string {
+	return filepath.Join(e.executionDirPath(), executionMetadataFileName)
+}
+
 func (e *Execution) resultFilePath() string {
 	return filepath.Join(e.executionDirPath(), executionResultFileName)
 }
 
+func (e *Execution) eventsFilePath() string {
+	return filepath.Join(e.executionDirPath(), executionEventsFileName)
+}
+
 func (e *Execution) statusFilePath() string {
 	return filepath.Join(e.executionDirPath(), executionStatusFileName)
 }
 
+func (e *Execution) lockFilePath() string {
+	return filepath.Join(e.executionDirPath(), executionLockFileName)
+}
+
+// lock takes the execution lock that serializes status and result writers across processes.
+// Temp files found while holding the lock were left by a writer that died mid-write, so they are removed.
+func (e *Execution) lock() (func(), error) {
+	if err := e.ensureExists(); err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockFile(e.fs, e.lockFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filePath := range []string{e.statusFilePath(), e.resultFilePath()} {
+		if err := e.fs.Remove(filePath + "~"); err != nil && !os.IsNotExist(err) {
+			unlock()
+			return nil, fmt.Errorf("remove stale temp file: %w", err)
+		}
+	}
+
+	return unlock, nil
+}
+
 // GetInput returns the stored input for the execution.
 func (e *Execution) GetInput(ctx context.Context) (agent.ExecutionInput, error) {
 	return readJSON[agent.ExecutionInput](e.fs, e.inputFilePath())
@@ -192,6 +307,20 @@ func (e *Execution) GetAgentConfig(ctx context.Context) (agent.Config, error) {
 	return readJSON[agent.Config](e.fs, e.agentConfigFilePath())
 }
 
+// GetMetadata returns the stored metadata for the execution.
+func (e *Execution) GetMetadata(ctx context.Context) (agent.ExecutionMetadata, error) {
+	exists, err := hasJSON(e.fs, e.metadataFilePath())
+	if err != nil {
+		return agent.ExecutionMetadata{}, err
+	}
+
+	if !exists {
+		return agent.ExecutionMetadata{}, nil
+	}
+
+	return readJSON[agent.ExecutionMetadata](e.fs, e.metadataFilePath())
+}
+
 // GetResult returns the stored result for the execution.
 func (e *Execution) GetResult(ctx context.Context) (agent.ExecutionResult, error) {
 	exists, err := hasJSON(e.fs, e.resultFilePath())
@@ -213,6 +342,12 @@ func (e *Execution) HasResult(ctx context.Context) (bool, error) {
 
 // SetResult stores the result for the execution.
 func (e *Execution) SetResult(ctx context.Context, result agent.ExecutionResult) error {
+	unlock, err := e.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	status, err := e.GetStatus(ctx)
 	if err != nil {
 		return err
@@ -223,6 +358,7 @@ func (e *Execution) SetResult(ctx context.Context, result agent.ExecutionResult)
 	}
 
 	now := time.Now()
+	status.Revision++
 	status.State = agent.ExecutionSucceeded
 	status.FinishedAt = &now
 	status.UpdatedAt = now
@@ -230,7 +366,7 @@ func (e *Execution) SetResult(ctx context.Context, result agent.ExecutionResult)
 		return err
 	}
 
-	return nil
+	return e.index(ctx)
 }
 
 // GetStatus returns the lifecycle status for the execution.
@@ -238,12 +374,68 @@ func (e *Execution) GetStatus(ctx context.Context) (agent.ExecutionStatus, error
 	return readJSON[agent.ExecutionStatus](e.fs, e.statusFilePath())
 }
 
-// UpdateStatus stores the lifecycle status for the execution.
+// UpdateStatus stores the lifecycle status for the execution with the next revision.
 func (e *Execution) UpdateStatus(ctx context.Context, status agent.ExecutionStatus) error {
+	unlock, err := e.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := e.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Revision != current.Revision {
+		return fmt.Errorf("%w: revision %d, stored revision %d", agent.ErrExecutionStatusConflict, status.Revision, current.Revision)
+	}
+
+	status.Revision++
 	status.UpdatedAt = time.Now()
 	if err := writeJSON(e.fs, e.statusFilePath(), status); err != nil {
 		return err
 	}
 
+	return e.index(ctx)
+}
+
+// AppendEvent adds a runtime event to the end of the execution event log.
+func (e *Execution) AppendEvent(ctx context.Context, envelope agent.RuntimeEventEnvelope) error {
+	if err := e.ensureExists(); err != nil {
+		return err
+	}
+
+	return appendNDJSON(e.fs, e.eventsFilePath(), envelope)
+}
+
+// GetEvents returns the runtime events recorded for the execution, skipping the first offset events.
+func (e *Execution) GetEvents(ctx context.Context, offset int) ([]agent.RuntimeEventEnvelope, error) {
+	if err := e.ensureExists(); err != nil {
+		return nil, err
+	}
+
+	events, err := readNDJSON[agent.RuntimeEventEnvelope](e.fs, e.eventsFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	if offset >= len(events) {
+		return []agent.RuntimeEventEnvelope{}, nil
+	}
+
+	return events[max(offset, 0):], nil
+}
+
+func (e *Execution) ensureExists() error {
+	exists, err := hasJSON(e.fs, e.statusFilePath())
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return agent.ErrExecutionNotFound
+	}
+
 	return nil
 }
@@ -0,0 +1,237 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/spf13/afero"
+)
+
+// executionIndexFileName is the execution index in the repository base path. Every line is the
+// agent.ExecutionRecord of an execution after a change; the last line of an execution wins.
+const executionIndexFileName = "index.ndjson"
+
+// executionIndexLockFileName serializes appending to the execution index with replacing it.
+const executionIndexLockFileName = "index.lock"
+
+// Reading the execution index rebuilds it once it holds more than indexCompactionFactor entries per stored execution
+// plus indexCompactionSlack, so superseded entries and entries of deleted executions do not pile up.
+const (
+	indexCompactionFactor = 4
+	indexCompactionSlack  = 100
+)
+
+func (r *Repository) indexFilePath() string {
+	return filepath.Join(r.basePath, executionIndexFileName)
+}
+
+// RebuildIndex recreates the execution index from the stored executions, dropping superseded entries.
+// Find calls it when the index grew well past the stored executions; call it directly after editing execution files.
+func (r *Repository) RebuildIndex(ctx context.Context) error {
+	// Entries appended while the executions are read would be lost by the rename, so appending waits for the rebuild.
+	unlock, err := lockFile(r.fs, filepath.Join(r.basePath, executionIndexLockFileName))
+	if err != nil {
+		return fmt.Errorf("rebuild execution index: %w", err)
+	}
+	defer unlock()
+
+	ids, err := r.listIDs()
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		isDir, err := afero.IsDir(r.fs, filepath.Join(r.basePath, string(id)))
+		if err != nil || !isDir {
+			continue
+		}
+
+		record, err := r.execution(id).record(ctx)
+		if err != nil {
+			if errors.Is(err, agent.ErrExecutionNotFound) {
+				continue
+			}
+			return fmt.Errorf("load execution %s: %w", id, err)
+		}
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("rebuild execution index: failed to marshal %s: %w", id, err)
+		}
+		content = append(append(content, line...), '\n')
+	}
+
+	tmpFile, err := afero.TempFile(r.fs, r.basePath, executionIndexFileName+".*~")
+	if err != nil {
+		return fmt.Errorf("rebuild execution index: failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		_ = r.fs.Remove(tmpPath)
+		return fmt.Errorf("rebuild execution index: failed to write %s: %w", tmpPath, err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		_ = r.fs.Remove(tmpPath)
+		return fmt.Errorf("rebuild execution index: failed to sync %s: %w", tmpPath, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		_ = r.fs.Remove(tmpPath)
+		return fmt.Errorf("rebuild execution index: failed to close %s: %w", tmpPath, err)
+	}
+
+	if err := r.fs.Rename(tmpPath, r.indexFilePath()); err != nil {
+		_ = r.fs.Remove(tmpPath)
+		return fmt.Errorf("rebuild execution index: failed to rename %s: %w", tmpPath, err)
+	}
+
+	return nil
+}
+
+// readIndex returns the latest indexed record of every execution.
+// A missing or unreadable index is rebuilt first, as is an index with far more entries than the stored executions.
+func (r *Repository) readIndex(ctx context.Context, executions int) (map[agent.ExecutionID]agent.ExecutionRecord, error) {
+	exists, err := afero.Exists(r.fs, r.indexFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	var records []agent.ExecutionRecord
+	if exists {
+		records, err = readNDJSON[agent.ExecutionRecord](r.fs, r.indexFilePath())
+	}
+
+	compact := err == nil && len(records) > indexCompactionFactor*executions+indexCompactionSlack
+	if compact {
+		slog.Debug("Compacting the execution index.", slog.Int("entries", len(records)), slog.Int("executions", executions))
+	}
+
+	if !exists || err != nil || compact {
+		if err := r.RebuildIndex(ctx); err != nil {
+			return nil, err
+		}
+
+		records, err = readNDJSON[agent.ExecutionRecord](r.fs, r.indexFilePath())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	index := make(map[agent.ExecutionID]agent.ExecutionRecord, len(records))
+	for _, record := range records {
+		index[record.ID] = record
+	}
+
+	return index, nil
+}
+
+// listIDs returns the names in the base path that are valid execution identifiers, without reading file details.
+func (r *Repository) listIDs() ([]agent.ExecutionID, error) {
+	dir, err := r.fs.Open(r.basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []agent.ExecutionID{}, nil
+		}
+		return nil, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]agent.ExecutionID, 0, len(names))
+	for _, name := range names {
+		id := agent.ExecutionID(name)
+		if err := id.Validate(); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) execution(id agent.ExecutionID) *Execution {
+	return &Execution{
+		id:       id,
+		basePath: r.basePath,
+		fs:       r.fs,
+	}
+}
+
+func (e *Execution) indexFilePath() string {
+	return filepath.Join(e.basePath, executionIndexFileName)
+}
+
+// index appends the current record of the execution to the execution index.
+func (e *Execution) index(ctx context.Context) error {
+	record, err := e.record(ctx)
+	if err != nil {
+		return fmt.Errorf("update execution index: %w", err)
+	}
+
+	return e.appendIndex(record)
+}
+
+func (e *Execution) appendIndex(record agent.ExecutionRecord) error {
+	unlock, err := lockFile(e.fs, filepath.Join(e.basePath, executionIndexLockFileName))
+	if err != nil {
+		return fmt.Errorf("update execution index: %w", err)
+	}
+	defer unlock()
+
+	if err := appendNDJSON(e.fs, e.indexFilePath(), record); err != nil {
+		return fmt.Errorf("update execution index: %w", err)
+	}
+
+	return nil
+}
+
+// record collects the queryable data of the execution from its files.
+func (e *Execution) record(ctx context.Context) (agent.ExecutionRecord, error) {
+	status, err := e.GetStatus(ctx)
+	if err != nil {
+		return agent.ExecutionRecord{}, err
+	}
+
+	input, err := e.GetInput(ctx)
+	if err != nil {
+		return agent.ExecutionRecord{}, err
+	}
+
+	agentConfig, err := e.GetAgentConfig(ctx)
+	if err != nil && !errors.Is(err, agent.ErrExecutionAgentConfigNotFound) {
+		return agent.ExecutionRecord{}, err
+	}
+
+	metadata, err := e.GetMetadata(ctx)
+	if err != nil {
+		return agent.ExecutionRecord{}, err
+	}
+
+	var result *agent.ExecutionResult
+	if executionResult, err := e.GetResult(ctx); err == nil {
+		result = &executionResult
+	} else if !errors.Is(err, agent.ErrExecutionNoResult) {
+		return agent.ExecutionRecord{}, err
+	}
+
+	return agent.NewExecutionRecord(e.id, input, agentConfig, metadata, status, result), nil
+}
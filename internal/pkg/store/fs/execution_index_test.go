@@ -0,0 +1,208 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIndexedExecution(t *testing.T, repo *Repository) agent.ExecutionID {
+	t.Helper()
+
+	workingDir := "/app"
+	input := agent.ExecutionInput{
+		Prompt:           "test prompt",
+		Timeout:          utils.Duration(5 * time.Minute),
+		WorkingDirectory: &workingDir,
+	}
+
+	id, err := repo.Create(context.Background(), input, sampleAgentConfig, agent.WithAgentID("codex"))
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestRepository_Index(t *testing.T) {
+	t.Run("records create, status and result changes", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		id := newIndexedExecution(t, repo)
+		execution, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+
+		status, err := execution.GetStatus(ctx)
+		require.NoError(t, err)
+		status.State = agent.ExecutionRunning
+		require.NoError(t, execution.UpdateStatus(ctx, status))
+		require.NoError(t, execution.SetResult(ctx, agent.ExecutionResult{ConversationID: "conv-1"}))
+
+		records, err := readNDJSON[agent.ExecutionRecord](memFs, filepath.Join(basePath, executionIndexFileName))
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, agent.ExecutionCreated, records[0].State)
+		assert.Equal(t, agent.ExecutionRunning, records[1].State)
+		assert.Equal(t, agent.ExecutionSucceeded, records[2].State)
+		assert.Equal(t, agent.AgentID("codex"), records[2].AgentID)
+		assert.Equal(t, agent.RuntimeKind("codex"), records[2].RuntimeKind)
+		assert.Equal(t, []agent.ConversationID{"conv-1"}, records[2].ConversationIDs)
+	})
+
+	t.Run("find answers from the index until it is rebuilt", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		id := newIndexedExecution(t, repo)
+
+		statusFilePath := filepath.Join(basePath, string(id), executionStatusFileName)
+		status, err := readJSON[agent.ExecutionStatus](memFs, statusFilePath)
+		require.NoError(t, err)
+		status.State = agent.ExecutionFailed
+		require.NoError(t, writeJSON(memFs, statusFilePath, status))
+
+		ids, err := repo.Find(ctx, agent.FilterByState(agent.ExecutionFailed))
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		require.NoError(t, repo.RebuildIndex(ctx))
+
+		ids, err = repo.Find(ctx, agent.FilterByState(agent.ExecutionFailed))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{id}, ids)
+
+		records, err := readNDJSON[agent.ExecutionRecord](memFs, filepath.Join(basePath, executionIndexFileName))
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("indexes executions missing from the index", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		first := newIndexedExecution(t, repo)
+		second := newIndexedExecution(t, repo)
+
+		indexFilePath := filepath.Join(basePath, executionIndexFileName)
+		records, err := readNDJSON[agent.ExecutionRecord](memFs, indexFilePath)
+		require.NoError(t, err)
+		require.NoError(t, memFs.Remove(indexFilePath))
+		require.NoError(t, appendNDJSON(memFs, indexFilePath, records[0]))
+
+		ids, err := repo.Find(ctx, agent.FilterByAgentID("codex"))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []agent.ExecutionID{first, second}, ids)
+
+		records, err = readNDJSON[agent.ExecutionRecord](memFs, indexFilePath)
+		require.NoError(t, err)
+		assert.Len(t, records, 2)
+	})
+
+	t.Run("rebuilds a missing or corrupt index", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		id := newIndexedExecution(t, repo)
+		indexFilePath := filepath.Join(basePath, executionIndexFileName)
+
+		require.NoError(t, afero.WriteFile(memFs, indexFilePath, []byte("{\"id\":\n"), 0644))
+		ids, err := repo.Find(ctx, agent.FilterByState(agent.ExecutionCreated))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{id}, ids)
+
+		require.NoError(t, memFs.Remove(indexFilePath))
+		ids, err = repo.Find(ctx, agent.FilterByState(agent.ExecutionCreated))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{id}, ids)
+
+		exists, err := afero.Exists(memFs, indexFilePath)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("skips an entry still being appended", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		id := newIndexedExecution(t, repo)
+		indexFilePath := filepath.Join(basePath, executionIndexFileName)
+
+		file, err := memFs.OpenFile(indexFilePath, os.O_WRONLY|os.O_APPEND, 0644)
+		require.NoError(t, err)
+		_, err = file.Write([]byte("{\"id\":"))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		ids, err := repo.Find(ctx, agent.FilterByState(agent.ExecutionCreated))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{id}, ids)
+
+		content, err := afero.ReadFile(memFs, indexFilePath)
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(string(content), "{\"id\":"), "index is not rebuilt")
+	})
+
+	t.Run("compacts an index of mostly superseded entries", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		kept := newIndexedExecution(t, repo)
+		deleted := newIndexedExecution(t, repo)
+		require.NoError(t, repo.Delete(ctx, deleted, agent.ForceDelete()))
+
+		indexFilePath := filepath.Join(basePath, executionIndexFileName)
+		records, err := readNDJSON[agent.ExecutionRecord](memFs, indexFilePath)
+		require.NoError(t, err)
+
+		// One stored execution tolerates indexCompactionFactor+indexCompactionSlack entries.
+		for len(records) < indexCompactionFactor+indexCompactionSlack {
+			require.NoError(t, appendNDJSON(memFs, indexFilePath, records[0]))
+			records = append(records, records[0])
+		}
+
+		ids, err := repo.Find(ctx, agent.FilterByAgentID("codex"))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{kept}, ids)
+
+		records, err = readNDJSON[agent.ExecutionRecord](memFs, indexFilePath)
+		require.NoError(t, err)
+		assert.Len(t, records, indexCompactionFactor+indexCompactionSlack, "index is not compacted at the limit")
+
+		require.NoError(t, appendNDJSON(memFs, indexFilePath, records[0]))
+
+		ids, err = repo.Find(ctx, agent.FilterByAgentID("codex"))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{kept}, ids)
+
+		records, err = readNDJSON[agent.ExecutionRecord](memFs, indexFilePath)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, kept, records[0].ID)
+	})
+}
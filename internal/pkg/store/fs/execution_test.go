@@ -2,6 +2,7 @@ package fs
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 	"testing"
 	"time"
@@ -14,11 +15,7 @@ import (
 )
 
 var sampleAgentConfig = agent.Config{
-	Runtime: struct {
-		Kind    agent.RuntimeKind     `json:"kind"`
-		Config  agent.RuntimeConfig   `json:"config"`
-		Feature agent.RuntimeFeatures `json:"feature,omitempty"`
-	}{
+	Runtime: agent.ConfigRuntime{
 		Kind: agent.RuntimeKind("codex"),
 		Config: map[string]any{
 			"path": "/bin/agent",
@@ -108,6 +105,50 @@ func TestRepository_Create(t *testing.T) {
 		assert.Equal(t, agent.EmptyExecutionID, id)
 		assert.ErrorIs(t, err, agent.ErrExecutionPromptRequired)
 	})
+
+	t.Run("model alias", func(t *testing.T) {
+		config := sampleAgentConfig
+		config.Models = agent.ConfigModels{
+			Allowed: []string{"gpt-5-codex"},
+			Aliases: map[string]string{"deep": "gpt-5-codex"},
+		}
+
+		aliasInput := input
+		aliasInput.Model = utils.ToPointer("deep")
+		id, err := repo.Create(ctx, aliasInput, config)
+		require.NoError(t, err)
+
+		retrievedInput, err := readJSON[agent.ExecutionInput](memFs, filepath.Join(basePath, string(id), executionInputFileName))
+		require.NoError(t, err)
+		require.NotNil(t, retrievedInput.Model)
+		assert.Equal(t, "gpt-5-codex", *retrievedInput.Model)
+
+		unknownInput := input
+		unknownInput.Model = utils.ToPointer("gpt-5-codx")
+		id, err = repo.Create(ctx, unknownInput, config)
+		assert.Equal(t, agent.EmptyExecutionID, id)
+		assert.ErrorIs(t, err, agent.ErrExecutionModelNotAllowed)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		parentID := agent.NewExecutionID()
+		id, err := repo.Create(ctx, input, sampleAgentConfig, agent.WithAgentID("codex-fallback"), agent.WithFallbackOf(parentID))
+		require.NoError(t, err)
+
+		execution, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+
+		metadata, err := execution.GetMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.AgentID("codex-fallback"), metadata.AgentID)
+		require.NotNil(t, metadata.FallbackOf)
+		assert.Equal(t, parentID, *metadata.FallbackOf)
+
+		require.NoError(t, memFs.Remove(filepath.Join(basePath, string(id), executionMetadataFileName)))
+		metadata, err = execution.GetMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.ExecutionMetadata{}, metadata)
+	})
 }
 
 func TestRepository_Exists(t *testing.T) {
@@ -208,6 +249,68 @@ func TestRepository_Get(t *testing.T) {
 	})
 }
 
+func TestRepository_Delete(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	basePath := "/tmp/test-executions"
+	repo, err := NewExecutionRepository(basePath, memFs)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	id := newIndexedExecution(t, repo)
+	kept := newIndexedExecution(t, repo)
+
+	t.Run("existing execution", func(t *testing.T) {
+		// A created execution is about to be picked up by its runner.
+		require.ErrorIs(t, repo.Delete(ctx, id), agent.ErrExecutionActive)
+
+		execution, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		status, err := execution.GetStatus(ctx)
+		require.NoError(t, err)
+		status.State = agent.ExecutionSucceeded
+		require.NoError(t, execution.UpdateStatus(ctx, status))
+
+		require.NoError(t, repo.Delete(ctx, id))
+
+		exists, err := repo.Exists(ctx, id)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		ids, err := repo.Find(ctx, agent.FilterByAgentID("codex"))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{kept}, ids)
+	})
+
+	t.Run("active execution", func(t *testing.T) {
+		execution, err := repo.Get(ctx, kept)
+		require.NoError(t, err)
+		status, err := execution.GetStatus(ctx)
+		require.NoError(t, err)
+		status.State = agent.ExecutionRunning
+		require.NoError(t, execution.UpdateStatus(ctx, status))
+
+		require.ErrorIs(t, repo.Delete(ctx, kept), agent.ErrExecutionActive)
+
+		exists, err := repo.Exists(ctx, kept)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		require.NoError(t, repo.Delete(ctx, kept, agent.ForceDelete()))
+
+		exists, err = repo.Exists(ctx, kept)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("non-existing execution", func(t *testing.T) {
+		require.ErrorIs(t, repo.Delete(ctx, id), agent.ErrExecutionNotFound)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		require.ErrorIs(t, repo.Delete(ctx, "invalid"), agent.ErrExecutionIDInvalid)
+	})
+}
+
 func TestRepository_Find(t *testing.T) {
 	t.Run("empty base path returns empty slice", func(t *testing.T) {
 		memFs := afero.NewMemMapFs()
@@ -252,6 +355,86 @@ func TestRepository_Find(t *testing.T) {
 		assert.Equal(t, agent.ExecutionID("00000000-0000-0000-0000-000000000001"), ids[0])
 		assert.Equal(t, agent.ExecutionID("00000000-0000-0000-0000-000000000002"), ids[1])
 	})
+
+	t.Run("filters and sorts by stored data", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+		ctx := context.Background()
+		workingDir := "/app"
+
+		input := agent.ExecutionInput{
+			Prompt:           "test prompt",
+			Timeout:          utils.Duration(5 * time.Minute),
+			WorkingDirectory: &workingDir,
+		}
+
+		first, err := repo.Create(ctx, input, sampleAgentConfig, agent.WithAgentID("codex"), agent.WithLabels(map[string]string{"team": "payments"}))
+		require.NoError(t, err)
+
+		second, err := repo.Create(ctx, input, sampleAgentConfig, agent.WithAgentID("codex"))
+		require.NoError(t, err)
+
+		third, err := repo.Create(ctx, input, sampleAgentConfig, agent.WithAgentID("gemini"))
+		require.NoError(t, err)
+
+		for i, id := range []agent.ExecutionID{first, second, third} {
+			execution, err := repo.Get(ctx, id)
+			require.NoError(t, err)
+			status, err := execution.GetStatus(ctx)
+			require.NoError(t, err)
+			status.CreatedAt = time.Date(2025, 1, 1, 0, i, 0, 0, time.UTC)
+			require.NoError(t, execution.UpdateStatus(ctx, status))
+		}
+
+		execution, err := repo.Get(ctx, second)
+		require.NoError(t, err)
+		require.NoError(t, execution.SetResult(ctx, agent.ExecutionResult{ConversationID: "conv-1"}))
+
+		ids, err := repo.Find(ctx, agent.FilterByAgentID("codex"), agent.SortExecutions(agent.ExecutionSortCreatedDesc))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{second, first}, ids)
+
+		ids, err = repo.Find(ctx, agent.FilterByState(agent.ExecutionSucceeded), agent.FilterByConversationID("conv-1"))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{second}, ids)
+
+		ids, err = repo.Find(ctx, agent.FilterByLabel("team", "payments"), agent.FilterByRuntimeKind("codex"), agent.FilterByWorkingDirectory("/app"))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{first}, ids)
+
+		ids, err = repo.Find(ctx, agent.SortExecutions(agent.ExecutionSortCreatedAsc), agent.LimitExecutions(1), agent.ExecutionsAfter(agent.ExecutionCursor{CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ID: first}))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{second}, ids)
+
+		// The cursor is a position, so paging carries on after its execution is deleted.
+		ids, err = repo.Find(ctx, agent.SortExecutions(agent.ExecutionSortCreatedAsc), agent.ExecutionsAfter(agent.ExecutionCursor{CreatedAt: time.Date(2025, 1, 1, 0, 1, 30, 0, time.UTC), ID: agent.NewExecutionID()}))
+		require.NoError(t, err)
+		assert.Equal(t, []agent.ExecutionID{third}, ids)
+	})
+
+	t.Run("skips executions that are still being created", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		basePath := "/tmp/test-executions"
+		repo, err := NewExecutionRepository(basePath, memFs)
+		require.NoError(t, err)
+
+		err = memFs.MkdirAll(filepath.Join(basePath, "00000000-0000-0000-0000-000000000001"), 0755)
+		require.NoError(t, err)
+
+		ids, err := repo.Find(context.Background(), agent.FilterByState(agent.ExecutionCreated))
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("rejects an invalid query", func(t *testing.T) {
+		repo, err := NewExecutionRepository("/tmp/test-executions", afero.NewMemMapFs())
+		require.NoError(t, err)
+
+		_, err = repo.Find(context.Background(), agent.SortExecutions("newest"))
+		require.ErrorIs(t, err, agent.ErrExecutionSortInvalid)
+	})
 }
 
 func TestExecution_GetSetResult(t *testing.T) {
@@ -364,5 +547,141 @@ func TestExecution_UpdateStatus(t *testing.T) {
 		updatedStatus, err := exec.GetStatus(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, agent.ExecutionStarted, updatedStatus.State)
+		assert.Equal(t, status.Revision+1, updatedStatus.Revision)
+	})
+
+	t.Run("rejects a status read before the last update", func(t *testing.T) {
+		stale, err := exec.GetStatus(ctx)
+		require.NoError(t, err)
+
+		status := stale
+		status.State = agent.ExecutionRunning
+		require.NoError(t, exec.UpdateStatus(ctx, status))
+
+		stale.State = agent.ExecutionFailed
+		err = exec.UpdateStatus(ctx, stale)
+		require.ErrorIs(t, err, agent.ErrExecutionStatusConflict)
+
+		updatedStatus, err := exec.GetStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.ExecutionRunning, updatedStatus.State)
+	})
+
+	t.Run("recovers from a stale temp file", func(t *testing.T) {
+		statusFilePath := filepath.Join(basePath, string(id), executionStatusFileName)
+		require.NoError(t, afero.WriteFile(memFs, statusFilePath+"~", []byte("{\"state\":"), 0644))
+
+		status, err := exec.GetStatus(ctx)
+		require.NoError(t, err)
+		status.State = agent.ExecutionSucceeded
+		require.NoError(t, exec.UpdateStatus(ctx, status))
+
+		updatedStatus, err := exec.GetStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.ExecutionSucceeded, updatedStatus.State)
+
+		exists, err := afero.Exists(memFs, statusFilePath+"~")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("non-existing execution", func(t *testing.T) {
+		err := repo.execution(agent.NewExecutionID()).UpdateStatus(ctx, agent.ExecutionStatus{})
+		require.ErrorIs(t, err, agent.ErrExecutionNotFound)
+	})
+}
+
+func TestExecution_UpdateStatusConcurrently(t *testing.T) {
+	repo, err := NewExecutionRepository(t.TempDir(), afero.NewOsFs())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	id := newIndexedExecution(t, repo)
+
+	const writers = 8
+	errs := make(chan error, writers)
+	for range writers {
+		go func() {
+			execution, err := repo.Get(ctx, id)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			for {
+				status, err := execution.GetStatus(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				status.Attempts++
+				err = execution.UpdateStatus(ctx, status)
+				if !errors.Is(err, agent.ErrExecutionStatusConflict) {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	for range writers {
+		require.NoError(t, <-errs)
+	}
+
+	execution, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+	status, err := execution.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, writers, status.Attempts)
+	assert.Equal(t, int64(writers), status.Revision)
+}
+
+func TestExecution_Events(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	repo, err := NewExecutionRepository("/tmp/test-executions", memFs)
+	require.NoError(t, err)
+	ctx := context.Background()
+	workingDir := "/app"
+
+	input := agent.ExecutionInput{
+		Prompt:           "test prompt",
+		Timeout:          utils.Duration(5 * time.Minute),
+		WorkingDirectory: &workingDir,
+	}
+
+	id, err := repo.Create(ctx, input, sampleAgentConfig)
+	require.NoError(t, err)
+	exec, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+
+	t.Run("no events", func(t *testing.T) {
+		events, err := exec.GetEvents(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("append and read from offset", func(t *testing.T) {
+		for _, text := range []string{"first", "second", "third"} {
+			envelope, err := agent.NewRuntimeEventEnvelope(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: text})
+			require.NoError(t, err)
+			require.NoError(t, exec.AppendEvent(ctx, envelope))
+		}
+
+		events, err := exec.GetEvents(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+
+		events, err = exec.GetEvents(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		event, err := events[0].Decode()
+		require.NoError(t, err)
+		assert.Equal(t, "third", event.(agent.RuntimeMessageEvent).Text)
+
+		events, err = exec.GetEvents(ctx, 5)
+		require.NoError(t, err)
+		assert.Empty(t, events)
 	})
 }
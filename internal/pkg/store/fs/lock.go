@@ -0,0 +1,41 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// processLocks serializes lock holders within the process, keyed by lock file path.
+// File locks alone do not cover in-memory file systems.
+var processLocks sync.Map
+
+// lockFile takes an exclusive advisory lock on the file, creating it when missing, and returns the function releasing it.
+// On the OS file system the lock is shared with other processes.
+func lockFile(fs afero.Fs, filePath string) (func(), error) {
+	value, _ := processLocks.LoadOrStore(filePath, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+
+	file, err := fs.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		mutex.Unlock()
+		return nil, fmt.Errorf("lock file: failed to open %s: %w", filePath, err)
+	}
+
+	if osFile, ok := file.(*os.File); ok {
+		if err := flock(osFile); err != nil {
+			file.Close()
+			mutex.Unlock()
+			return nil, fmt.Errorf("lock file: failed to lock %s: %w", filePath, err)
+		}
+	}
+
+	return func() {
+		// Closing the file releases the file lock.
+		file.Close()
+		mutex.Unlock()
+	}, nil
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package fs
+
+import "os"
+
+// flock is a no-op where flock is unavailable; locks then only hold within the process.
+func flock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func flock(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
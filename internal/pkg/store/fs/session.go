@@ -0,0 +1,166 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/spf13/afero"
+)
+
+const (
+	sessionFileName           = "session.json"
+	sessionTranscriptFileName = "transcript.ndjson"
+)
+
+// SessionRepository is an implementation of agent.SessionRepository that stores
+// sessions and their transcripts on the file system.
+type SessionRepository struct {
+	basePath string
+	fs       afero.Fs
+}
+
+// NewSessionRepository creates a new file system-based session repository and
+// ensures the base path exists.
+func NewSessionRepository(basePath string, fs afero.Fs) (*SessionRepository, error) {
+	if err := fs.MkdirAll(basePath, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create session repository path: %w", err)
+	}
+
+	return &SessionRepository{
+		basePath: basePath,
+		fs:       fs,
+	}, nil
+}
+
+// Create persists a new session and returns its identifier.
+func (r *SessionRepository) Create(ctx context.Context, session agent.Session) (agent.SessionID, error) {
+	if err := session.AgentID.Validate(); err != nil {
+		return "", err
+	}
+
+	session.ID = agent.NewSessionID()
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = session.CreatedAt
+
+	if err := r.fs.MkdirAll(r.sessionDirPath(session.ID), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	if err := writeJSON(r.fs, r.sessionFilePath(session.ID), session); err != nil {
+		return "", err
+	}
+
+	return session.ID, nil
+}
+
+// Exists reports whether a session with the given identifier exists.
+func (r *SessionRepository) Exists(ctx context.Context, id agent.SessionID) (bool, error) {
+	if err := id.Validate(); err != nil {
+		return false, err
+	}
+
+	return afero.Exists(r.fs, r.sessionFilePath(id))
+}
+
+// Get loads the session with the given identifier.
+func (r *SessionRepository) Get(ctx context.Context, id agent.SessionID) (agent.Session, error) {
+	if err := r.ensureExists(ctx, id); err != nil {
+		return agent.Session{}, err
+	}
+
+	return readJSON[agent.Session](r.fs, r.sessionFilePath(id))
+}
+
+// Update stores the session.
+func (r *SessionRepository) Update(ctx context.Context, session agent.Session) error {
+	if err := r.ensureExists(ctx, session.ID); err != nil {
+		return err
+	}
+
+	session.UpdatedAt = time.Now()
+
+	return writeJSON(r.fs, r.sessionFilePath(session.ID), session)
+}
+
+// List returns the identifiers of all sessions.
+func (r *SessionRepository) List(ctx context.Context) ([]agent.SessionID, error) {
+	entries, err := afero.ReadDir(r.fs, r.basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []agent.SessionID{}, nil
+		}
+		return nil, err
+	}
+
+	ids := make([]agent.SessionID, 0, len(entries))
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !entry.IsDir() {
+			continue
+		}
+
+		id := agent.SessionID(entry.Name())
+		if err := id.Validate(); err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids, nil
+}
+
+// AppendTranscript adds a turn to the end of the session transcript.
+func (r *SessionRepository) AppendTranscript(ctx context.Context, id agent.SessionID, entry agent.TranscriptEntry) error {
+	if err := r.ensureExists(ctx, id); err != nil {
+		return err
+	}
+
+	return appendNDJSON(r.fs, r.transcriptFilePath(id), entry)
+}
+
+// GetTranscript returns the session transcript, oldest turn first.
+func (r *SessionRepository) GetTranscript(ctx context.Context, id agent.SessionID) ([]agent.TranscriptEntry, error) {
+	if err := r.ensureExists(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return readNDJSON[agent.TranscriptEntry](r.fs, r.transcriptFilePath(id))
+}
+
+func (r *SessionRepository) ensureExists(ctx context.Context, id agent.SessionID) error {
+	exists, err := r.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return agent.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) sessionDirPath(id agent.SessionID) string {
+	return filepath.Join(r.basePath, string(id))
+}
+
+func (r *SessionRepository) sessionFilePath(id agent.SessionID) string {
+	return filepath.Join(r.sessionDirPath(id), sessionFileName)
+}
+
+func (r *SessionRepository) transcriptFilePath(id agent.SessionID) string {
+	return filepath.Join(r.sessionDirPath(id), sessionTranscriptFileName)
+}
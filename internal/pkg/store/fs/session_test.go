@@ -0,0 +1,84 @@
+package fs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewSessionRepository("/tmp/test-sessions", afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	workingDir := "/app"
+	id, err := repo.Create(ctx, agent.Session{AgentID: "claude-code", WorkingDirectory: &workingDir})
+	require.NoError(t, err)
+	require.NoError(t, id.Validate())
+
+	t.Run("get and update", func(t *testing.T) {
+		session, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, agent.AgentID("claude-code"), session.AgentID)
+		assert.Nil(t, session.ConversationID)
+
+		conversationID := agent.ConversationID("conversation-1")
+		session.ConversationID = &conversationID
+		require.NoError(t, repo.Update(ctx, session))
+
+		session, err = repo.Get(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, session.ConversationID)
+		assert.Equal(t, conversationID, *session.ConversationID)
+	})
+
+	t.Run("missing session", func(t *testing.T) {
+		_, err := repo.Get(ctx, agent.NewSessionID())
+		require.ErrorIs(t, err, agent.ErrSessionNotFound)
+
+		_, err = repo.Get(ctx, "invalid")
+		require.ErrorIs(t, err, agent.ErrSessionIDInvalid)
+	})
+
+	t.Run("transcript", func(t *testing.T) {
+		transcript, err := repo.GetTranscript(ctx, id)
+		require.NoError(t, err)
+		assert.Empty(t, transcript)
+
+		now := time.Now().UTC()
+		first := agent.TranscriptEntry{TurnID: agent.NewTurnID(), ExecutionID: agent.NewExecutionID(), Prompt: "first", Response: "one", State: agent.ExecutionSucceeded, CreatedAt: now, FinishedAt: now}
+		second := agent.TranscriptEntry{TurnID: agent.NewTurnID(), ExecutionID: agent.NewExecutionID(), Prompt: "second", Error: "boom", State: agent.ExecutionFailed, CreatedAt: now, FinishedAt: now}
+		require.NoError(t, repo.AppendTranscript(ctx, id, first))
+		require.NoError(t, repo.AppendTranscript(ctx, id, second))
+
+		transcript, err = repo.GetTranscript(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, []agent.TranscriptEntry{first, second}, transcript)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		ids, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []agent.SessionID{id}, ids)
+	})
+
+	t.Run("continue session", func(t *testing.T) {
+		input := agent.ExecutionInput{Prompt: "next", Timeout: utils.Duration(time.Minute)}
+
+		continued, err := agent.ContinueSession(ctx, repo, id, "claude-code", input)
+		require.NoError(t, err)
+		require.NotNil(t, continued.WorkingDirectory)
+		assert.Equal(t, workingDir, *continued.WorkingDirectory)
+		require.NotNil(t, continued.ConversationID)
+		assert.Equal(t, agent.ConversationID("conversation-1"), *continued.ConversationID)
+
+		_, err = agent.ContinueSession(ctx, repo, id, "codex", input)
+		require.ErrorIs(t, err, agent.ErrSessionAgentMismatch)
+	})
+}
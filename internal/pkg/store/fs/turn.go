@@ -0,0 +1,205 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/spf13/afero"
+)
+
+const (
+	turnRequestFileName  = "request.json"
+	turnResponseFileName = "response.json"
+	turnStatusFileName   = "status.json"
+)
+
+// TurnRepository is an implementation of agent.TurnRepository that stores
+// turn data on the file system.
+type TurnRepository struct {
+	basePath string
+	fs       afero.Fs
+}
+
+// NewTurnRepository creates a new file system-based turn repository and
+// ensures the base path exists.
+func NewTurnRepository(basePath string, fs afero.Fs) (*TurnRepository, error) {
+	if err := fs.MkdirAll(basePath, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create turn repository path: %w", err)
+	}
+
+	return &TurnRepository{
+		basePath: basePath,
+		fs:       fs,
+	}, nil
+}
+
+// Create persists a new turn and returns its identifier.
+func (r *TurnRepository) Create(ctx context.Context, request agent.TurnRequest) (agent.TurnID, error) {
+	if err := request.SessionID.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := request.ExecutionID.Validate(); err != nil {
+		return "", err
+	}
+
+	id := agent.NewTurnID()
+	turnPath := filepath.Join(r.basePath, string(id))
+	if err := r.fs.MkdirAll(turnPath, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	if err := writeJSON(r.fs, filepath.Join(turnPath, turnRequestFileName), request); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	status := agent.TurnStatus{
+		CreatedAt: now,
+		UpdatedAt: now,
+		State:     agent.ExecutionCreated,
+	}
+	if err := writeJSON(r.fs, filepath.Join(turnPath, turnStatusFileName), status); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Get loads the turn handle for the given identifier.
+func (r *TurnRepository) Get(ctx context.Context, id agent.TurnID) (agent.Turn, error) {
+	if err := id.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := afero.Exists(r.fs, filepath.Join(r.basePath, string(id)))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, agent.ErrTurnNotFound
+	}
+
+	return &Turn{
+		id:       id,
+		basePath: r.basePath,
+		fs:       r.fs,
+	}, nil
+}
+
+// List returns the identifiers of all turns.
+func (r *TurnRepository) List(ctx context.Context) ([]agent.TurnID, error) {
+	entries, err := afero.ReadDir(r.fs, r.basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []agent.TurnID{}, nil
+		}
+		return nil, err
+	}
+
+	ids := make([]agent.TurnID, 0, len(entries))
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !entry.IsDir() {
+			continue
+		}
+
+		id := agent.TurnID(entry.Name())
+		if err := id.Validate(); err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids, nil
+}
+
+// Turn is an implementation of agent.Turn for the file system store.
+type Turn struct {
+	id       agent.TurnID
+	basePath string
+	fs       afero.Fs
+}
+
+func (t *Turn) filePath(name string) string {
+	return filepath.Join(t.basePath, string(t.id), name)
+}
+
+// GetRequest returns the stored request for the turn.
+func (t *Turn) GetRequest(ctx context.Context) (agent.TurnRequest, error) {
+	return readTurnJSON[agent.TurnRequest](t.fs, t.filePath(turnRequestFileName))
+}
+
+// GetResponse returns the stored response for the turn.
+func (t *Turn) GetResponse(ctx context.Context) (agent.TurnResponse, error) {
+	exists, err := hasJSON(t.fs, t.filePath(turnResponseFileName))
+	if err != nil {
+		return agent.TurnResponse{}, err
+	}
+
+	if !exists {
+		return agent.TurnResponse{}, agent.ErrTurnNoResponse
+	}
+
+	return readTurnJSON[agent.TurnResponse](t.fs, t.filePath(turnResponseFileName))
+}
+
+// SetResponse stores the response and marks the turn as succeeded.
+func (t *Turn) SetResponse(ctx context.Context, response agent.TurnResponse) error {
+	status, err := t.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := writeJSON(t.fs, t.filePath(turnResponseFileName), response); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status.State = agent.ExecutionSucceeded
+	status.FinishedAt = &now
+	status.UpdatedAt = now
+
+	return writeJSON(t.fs, t.filePath(turnStatusFileName), status)
+}
+
+// GetStatus returns the lifecycle status for the turn.
+func (t *Turn) GetStatus(ctx context.Context) (agent.TurnStatus, error) {
+	return readTurnJSON[agent.TurnStatus](t.fs, t.filePath(turnStatusFileName))
+}
+
+// UpdateStatus stores the lifecycle status for the turn.
+func (t *Turn) UpdateStatus(ctx context.Context, status agent.TurnStatus) error {
+	status.UpdatedAt = time.Now()
+
+	return writeJSON(t.fs, t.filePath(turnStatusFileName), status)
+}
+
+func readTurnJSON[T any](fs afero.Fs, filePath string) (T, error) {
+	exists, err := hasJSON(fs, filePath)
+	if err != nil {
+		var result T
+		return result, err
+	}
+
+	if !exists {
+		var result T
+		return result, fmt.Errorf("read json: %w", agent.ErrTurnNotFound)
+	}
+
+	return readJSON[T](fs, filePath)
+}
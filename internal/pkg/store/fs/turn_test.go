@@ -0,0 +1,62 @@
+package fs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTurnRepository(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewTurnRepository("/tmp/test-turns", afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	request := agent.TurnRequest{
+		SessionID:   agent.NewSessionID(),
+		ExecutionID: agent.NewExecutionID(),
+		Prompt:      "Review the diff.",
+	}
+
+	_, err = repo.Create(ctx, agent.TurnRequest{ExecutionID: request.ExecutionID})
+	require.ErrorIs(t, err, agent.ErrSessionIDInvalid)
+
+	id, err := repo.Create(ctx, request)
+	require.NoError(t, err)
+
+	turn, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+
+	storedRequest, err := turn.GetRequest(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, request, storedRequest)
+
+	status, err := turn.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ExecutionCreated, status.State)
+
+	_, err = turn.GetResponse(ctx)
+	require.ErrorIs(t, err, agent.ErrTurnNoResponse)
+
+	response := agent.TurnResponse{AgentID: "codex", ConversationID: "thread-1", Response: "Looks good."}
+	require.NoError(t, turn.SetResponse(ctx, response))
+
+	storedResponse, err := turn.GetResponse(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, response, storedResponse)
+
+	status, err = turn.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ExecutionSucceeded, status.State)
+	assert.NotNil(t, status.FinishedAt)
+
+	ids, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []agent.TurnID{id}, ids)
+
+	_, err = repo.Get(ctx, agent.NewTurnID())
+	require.ErrorIs(t, err, agent.ErrTurnNotFound)
+}
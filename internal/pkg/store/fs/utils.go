@@ -1,6 +1,8 @@
 package fs
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -25,7 +27,7 @@ func writeJSON(fs afero.Fs, filePath string, data interface{}) error {
 		return fmt.Errorf("write json: temp file %s already exists: %w", tmpPath, os.ErrExist)
 	}
 
-	if err := afero.WriteFile(fs, tmpPath, b, 0644); err != nil {
+	if err := writeFileSynced(fs, tmpPath, b); err != nil {
 		return fmt.Errorf("write json: failed to write temp file %s: %w", tmpPath, err)
 	}
 
@@ -51,7 +53,7 @@ func writeYAML(fs afero.Fs, filePath string, data interface{}) error {
 		return fmt.Errorf("write yaml: temp file %s already exists: %w", tmpPath, os.ErrExist)
 	}
 
-	if err := afero.WriteFile(fs, tmpPath, b, 0644); err != nil {
+	if err := writeFileSynced(fs, tmpPath, b); err != nil {
 		return fmt.Errorf("write yaml: failed to write temp file %s: %w", tmpPath, err)
 	}
 
@@ -62,6 +64,26 @@ func writeYAML(fs afero.Fs, filePath string, data interface{}) error {
 	return nil
 }
 
+// writeFileSynced writes the file and flushes it to disk, so a rename over the target never exposes a partial file after a crash.
+func writeFileSynced(fs afero.Fs, filePath string, data []byte) error {
+	file, err := fs.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
 func readJSON[T any](fs afero.Fs, filePath string) (T, error) {
 	var result T
 	b, err := afero.ReadFile(fs, filePath)
@@ -97,3 +119,59 @@ func readYAML[T any](fs afero.Fs, filePath string) (T, error) {
 func hasJSON(fs afero.Fs, filePath string) (bool, error) {
 	return afero.Exists(fs, filePath)
 }
+
+func appendNDJSON(fs afero.Fs, filePath string, data interface{}) error {
+	line, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("append ndjson: failed to marshal for %s: %w", filePath, err)
+	}
+
+	file, err := fs.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("append ndjson: failed to open %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("append ndjson: failed to write %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+func readNDJSON[T any](fs afero.Fs, filePath string) ([]T, error) {
+	b, err := afero.ReadFile(fs, filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []T{}, nil
+		}
+		return nil, fmt.Errorf("read ndjson: failed to read %s: %w", filePath, err)
+	}
+
+	// A line without its terminating newline is still being appended by another writer and is left for the next read.
+	if end := bytes.LastIndexByte(b, '\n'); end+1 < len(b) {
+		b = b[:end+1]
+	}
+
+	items := []T{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("read ndjson: failed to unmarshal from %s: %w", filePath, err)
+		}
+		items = append(items, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ndjson: failed to scan %s: %w", filePath, err)
+	}
+
+	return items, nil
+}
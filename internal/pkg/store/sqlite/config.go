@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// ConfigRepository is an implementation of agent.ConfigRepository that stores
+// agent configurations in a SQLite database.
+type ConfigRepository struct {
+	db *sql.DB
+}
+
+// NewConfigRepository creates a new SQLite-based config repository on an opened database.
+func NewConfigRepository(db *sql.DB) *ConfigRepository {
+	return &ConfigRepository{db: db}
+}
+
+// Exists reports whether an agent config with the given identifier exists.
+func (r *ConfigRepository) Exists(ctx context.Context, id agent.AgentID) (bool, error) {
+	if err := id.Validate(); err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM agent_configs WHERE id = ?)", string(id)).Scan(&exists); err != nil {
+		return false, fmt.Errorf("query agent config: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Get loads the agent config for the given identifier.
+func (r *ConfigRepository) Get(ctx context.Context, id agent.AgentID) (agent.Config, error) {
+	if err := id.Validate(); err != nil {
+		return agent.Config{}, err
+	}
+
+	var encoded string
+	err := r.db.QueryRowContext(ctx, "SELECT config FROM agent_configs WHERE id = ?", string(id)).Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return agent.Config{}, agent.ErrAgentConfigNotFound
+	}
+	if err != nil {
+		return agent.Config{}, fmt.Errorf("query agent config: %w", err)
+	}
+
+	var config agent.Config
+	if err := json.Unmarshal([]byte(encoded), &config); err != nil {
+		return agent.Config{}, fmt.Errorf("decode agent config: %w", err)
+	}
+
+	return config, nil
+}
+
+// Update persists the agent config for the given identifier.
+func (r *ConfigRepository) Update(ctx context.Context, id agent.AgentID, config agent.Config) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	encoded, err := marshalJSON(config)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, "INSERT INTO agent_configs (id, config) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET config = excluded.config", string(id), encoded)
+	if err != nil {
+		return fmt.Errorf("update agent config: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the agent config.
+func (r *ConfigRepository) Delete(ctx context.Context, id agent.AgentID) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM agent_configs WHERE id = ?", string(id))
+	if err != nil {
+		return fmt.Errorf("delete agent config: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete agent config: %w", err)
+	}
+
+	if deleted == 0 {
+		return agent.ErrAgentConfigNotFound
+	}
+
+	return nil
+}
+
+// List returns the identifiers of all available agent configs.
+func (r *ConfigRepository) List(ctx context.Context) ([]agent.AgentID, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM agent_configs ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("query agent configs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []agent.AgentID{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan agent config: %w", err)
+		}
+		ids = append(ids, agent.AgentID(id))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query agent configs: %w", err)
+	}
+
+	return ids, nil
+}
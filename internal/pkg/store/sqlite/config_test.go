@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRepository(t *testing.T) {
+	repo := NewConfigRepository(openTestDB(t))
+	ctx := context.Background()
+
+	exists, err := repo.Exists(ctx, "codex")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = repo.Get(ctx, "codex")
+	require.ErrorIs(t, err, agent.ErrAgentConfigNotFound)
+
+	require.NoError(t, repo.Update(ctx, "codex", sampleAgentConfig))
+	require.NoError(t, repo.Update(ctx, "claude-code", agent.Config{Runtime: agent.ConfigRuntime{Kind: "claude"}}))
+
+	updated := sampleAgentConfig
+	updated.Fallback = []agent.AgentID{"claude-code"}
+	require.NoError(t, repo.Update(ctx, "codex", updated))
+
+	config, err := repo.Get(ctx, "codex")
+	require.NoError(t, err)
+	assert.Equal(t, agent.RuntimeKind("codex"), config.Runtime.Kind)
+	assert.Equal(t, []agent.AgentID{"claude-code"}, config.Fallback)
+
+	ids, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []agent.AgentID{"claude-code", "codex"}, ids)
+
+	require.NoError(t, repo.Delete(ctx, "claude-code"))
+	require.ErrorIs(t, repo.Delete(ctx, "claude-code"), agent.ErrAgentConfigNotFound)
+
+	ids, err = repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []agent.AgentID{"codex"}, ids)
+
+	_, err = repo.Get(ctx, "")
+	require.ErrorIs(t, err, agent.ErrAgentIDInvalid)
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations are the schema changes applied in order. The database user_version records how many ran.
+var migrations = []string{
+	`CREATE TABLE executions (
+		id TEXT PRIMARY KEY,
+		agent_id TEXT NOT NULL DEFAULT '',
+		runtime_kind TEXT NOT NULL DEFAULT '',
+		state TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		working_directory TEXT NOT NULL DEFAULT '',
+		input_conversation_id TEXT NOT NULL DEFAULT '',
+		result_conversation_id TEXT NOT NULL DEFAULT '',
+		input TEXT NOT NULL,
+		agent_config TEXT,
+		metadata TEXT NOT NULL,
+		status TEXT NOT NULL,
+		result TEXT
+	);
+	CREATE INDEX executions_created_at ON executions (created_at, id);
+	CREATE INDEX executions_agent_id ON executions (agent_id, created_at);
+	CREATE INDEX executions_state ON executions (state, created_at);
+	CREATE INDEX executions_input_conversation_id ON executions (input_conversation_id);
+	CREATE INDEX executions_result_conversation_id ON executions (result_conversation_id);
+
+	CREATE TABLE execution_labels (
+		execution_id TEXT NOT NULL REFERENCES executions (id) ON DELETE CASCADE,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (execution_id, key)
+	);
+	CREATE INDEX execution_labels_key ON execution_labels (key, value);
+
+	CREATE TABLE execution_events (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		execution_id TEXT NOT NULL REFERENCES executions (id) ON DELETE CASCADE,
+		kind TEXT NOT NULL,
+		payload TEXT NOT NULL
+	);
+	CREATE INDEX execution_events_execution_id ON execution_events (execution_id, seq);
+
+	CREATE TABLE agent_configs (
+		id TEXT PRIMARY KEY,
+		config TEXT NOT NULL
+	);`,
+}
+
+// Open opens the SQLite database at the path, creating it when missing, and migrates its schema.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create database directory: %w", err)
+	}
+
+	// Writers take the lock when their transaction begins, so concurrent runners wait for
+	// each other instead of failing to upgrade a read transaction.
+	dsn := "file:" + path + "?_txlock=immediate&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("migrate database: read schema version: %w", err)
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("migrate database: %w: version %d", ErrSchemaUnsupported, version)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("migrate database: apply migration %d: %w", i+1, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", len(migrations))); err != nil {
+		return fmt.Errorf("migrate database: write schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+var (
+	// ErrSchemaUnsupported indicates the database was migrated by a newer BriefKit version.
+	ErrSchemaUnsupported = errors.New("database schema unsupported")
+)
@@ -0,0 +1,531 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// Repository is an implementation of agent.ExecutionRepository that stores
+// execution data in a SQLite database.
+type Repository struct {
+	db *sql.DB
+}
+
+// NewExecutionRepository creates a new SQLite-based execution repository on an opened database.
+func NewExecutionRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create persists a new execution and returns its identifier.
+func (r *Repository) Create(ctx context.Context, input agent.ExecutionInput, agentConfig agent.Config, options ...agent.ExecutionOption) (agent.ExecutionID, error) {
+	if err := input.Validate(); err != nil {
+		return agent.EmptyExecutionID, err
+	}
+
+	if input.Model != nil {
+		model, err := agentConfig.Models.Resolve(*input.Model)
+		if err != nil {
+			return agent.EmptyExecutionID, err
+		}
+		input.Model = &model
+	}
+
+	id := agent.NewExecutionID()
+
+	now := time.Now()
+	status := agent.ExecutionStatus{
+		CreatedAt: now,
+		UpdatedAt: now,
+		State:     agent.ExecutionCreated,
+		Attempts:  0,
+	}
+
+	err := r.insert(ctx, executionRow{
+		id:          id,
+		input:       input,
+		agentConfig: &agentConfig,
+		metadata:    agent.NewExecutionMetadata(options...),
+		status:      status,
+	})
+	if err != nil {
+		return agent.EmptyExecutionID, err
+	}
+
+	return id, nil
+}
+
+// Exists reports whether an execution with the given identifier exists.
+func (r *Repository) Exists(ctx context.Context, id agent.ExecutionID) (bool, error) {
+	if err := id.Validate(); err != nil {
+		return false, err
+	}
+
+	return executionExists(ctx, r.db, id)
+}
+
+// Get loads the execution handle for the given identifier.
+func (r *Repository) Get(ctx context.Context, id agent.ExecutionID) (agent.Execution, error) {
+	if err := id.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := r.Exists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, agent.ErrExecutionNotFound
+	}
+
+	return &Execution{
+		id: id,
+		db: r.db,
+	}, nil
+}
+
+// Delete removes the execution. Its labels and events are removed with it.
+func (r *Repository) Delete(ctx context.Context, id agent.ExecutionID, options ...agent.ExecutionDeleteOption) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var state agent.ExecutionState
+	err = tx.QueryRowContext(ctx, "SELECT state FROM executions WHERE id = ?", string(id)).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return agent.ErrExecutionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("query execution state: %w", err)
+	}
+
+	if state.IsActive() && !agent.NewExecutionDeleteOptions(options...).Force {
+		return fmt.Errorf("%w: %s", agent.ErrExecutionActive, state)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM executions WHERE id = ?", string(id)); err != nil {
+		return fmt.Errorf("delete execution %s: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Import copies an execution, with its identifier, status, result and events, from another store.
+// Returns ErrExecutionExists when the execution is already stored.
+func (r *Repository) Import(ctx context.Context, id agent.ExecutionID, source agent.Execution) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	row := executionRow{id: id}
+
+	var err error
+	if row.input, err = source.GetInput(ctx); err != nil {
+		return fmt.Errorf("get input: %w", err)
+	}
+
+	agentConfig, err := source.GetAgentConfig(ctx)
+	if err == nil {
+		row.agentConfig = &agentConfig
+	} else if !errors.Is(err, agent.ErrExecutionAgentConfigNotFound) {
+		return fmt.Errorf("get agent config: %w", err)
+	}
+
+	if row.metadata, err = source.GetMetadata(ctx); err != nil {
+		return fmt.Errorf("get metadata: %w", err)
+	}
+
+	if row.status, err = source.GetStatus(ctx); err != nil {
+		return fmt.Errorf("get status: %w", err)
+	}
+
+	result, err := source.GetResult(ctx)
+	if err == nil {
+		row.result = &result
+	} else if !errors.Is(err, agent.ErrExecutionNoResult) {
+		return fmt.Errorf("get result: %w", err)
+	}
+
+	if row.events, err = source.GetEvents(ctx, 0); err != nil {
+		return fmt.Errorf("get events: %w", err)
+	}
+
+	exists, err := r.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrExecutionExists
+	}
+
+	return r.insert(ctx, row)
+}
+
+// executionRow is the stored data of one execution.
+type executionRow struct {
+	id          agent.ExecutionID
+	input       agent.ExecutionInput
+	agentConfig *agent.Config
+	metadata    agent.ExecutionMetadata
+	status      agent.ExecutionStatus
+	result      *agent.ExecutionResult
+	events      []agent.RuntimeEventEnvelope
+}
+
+func (r *Repository) insert(ctx context.Context, row executionRow) error {
+	var config agent.Config
+	if row.agentConfig != nil {
+		config = *row.agentConfig
+	}
+	record := agent.NewExecutionRecord(row.id, row.input, config, row.metadata, row.status, row.result)
+
+	input, err := marshalJSON(row.input)
+	if err != nil {
+		return err
+	}
+
+	var agentConfig *string
+	if row.agentConfig != nil {
+		encoded, err := marshalJSON(row.agentConfig)
+		if err != nil {
+			return err
+		}
+		agentConfig = &encoded
+	}
+
+	metadata, err := marshalJSON(row.metadata)
+	if err != nil {
+		return err
+	}
+
+	status, err := marshalJSON(row.status)
+	if err != nil {
+		return err
+	}
+
+	var result *string
+	var resultConversationID agent.ConversationID
+	if row.result != nil {
+		encoded, err := marshalJSON(row.result)
+		if err != nil {
+			return err
+		}
+		result = &encoded
+		resultConversationID = row.result.ConversationID
+	}
+
+	var inputConversationID agent.ConversationID
+	if row.input.ConversationID != nil {
+		inputConversationID = *row.input.ConversationID
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO executions (
+			id, agent_id, runtime_kind, state, created_at, updated_at, working_directory,
+			input_conversation_id, result_conversation_id, input, agent_config, metadata, status, result
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		string(row.id), string(record.AgentID), string(record.RuntimeKind), string(record.State),
+		record.CreatedAt.UnixNano(), record.UpdatedAt.UnixNano(), record.WorkingDirectory,
+		string(inputConversationID), string(resultConversationID), input, agentConfig, metadata, status, result,
+	)
+	if err != nil {
+		return fmt.Errorf("insert execution: %w", err)
+	}
+
+	for key, value := range row.metadata.Labels {
+		_, err := tx.ExecContext(ctx, "INSERT INTO execution_labels (execution_id, key, value) VALUES (?, ?, ?)", string(row.id), key, value)
+		if err != nil {
+			return fmt.Errorf("insert execution label: %w", err)
+		}
+	}
+
+	for _, envelope := range row.events {
+		if err := insertEvent(ctx, tx, row.id, envelope); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Execution is an implementation of agent.Execution for the SQLite store.
+type Execution struct {
+	id agent.ExecutionID
+	db *sql.DB
+}
+
+// GetInput returns the stored input for the execution.
+func (e *Execution) GetInput(ctx context.Context) (agent.ExecutionInput, error) {
+	return getColumn[agent.ExecutionInput](ctx, e.db, e.id, "input")
+}
+
+// GetAgentConfig returns the stored agent config for the execution.
+func (e *Execution) GetAgentConfig(ctx context.Context) (agent.Config, error) {
+	config, err := getColumn[*agent.Config](ctx, e.db, e.id, "agent_config")
+	if err != nil {
+		return agent.Config{}, err
+	}
+
+	if config == nil {
+		return agent.Config{}, agent.ErrExecutionAgentConfigNotFound
+	}
+
+	return *config, nil
+}
+
+// GetMetadata returns the stored metadata for the execution.
+func (e *Execution) GetMetadata(ctx context.Context) (agent.ExecutionMetadata, error) {
+	return getColumn[agent.ExecutionMetadata](ctx, e.db, e.id, "metadata")
+}
+
+// GetResult returns the stored result for the execution.
+func (e *Execution) GetResult(ctx context.Context) (agent.ExecutionResult, error) {
+	result, err := getColumn[*agent.ExecutionResult](ctx, e.db, e.id, "result")
+	if err != nil {
+		return agent.ExecutionResult{}, err
+	}
+
+	if result == nil {
+		return agent.ExecutionResult{}, agent.ErrExecutionNoResult
+	}
+
+	return *result, nil
+}
+
+// HasResult reports whether the execution has a stored result.
+func (e *Execution) HasResult(ctx context.Context) (bool, error) {
+	var hasResult bool
+	err := e.db.QueryRowContext(ctx, "SELECT result IS NOT NULL FROM executions WHERE id = ?", string(e.id)).Scan(&hasResult)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, agent.ErrExecutionNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("query execution result: %w", err)
+	}
+
+	return hasResult, nil
+}
+
+// SetResult stores the result for the execution and marks it succeeded in one transaction.
+func (e *Execution) SetResult(ctx context.Context, result agent.ExecutionResult) error {
+	encodedResult, err := marshalJSON(result)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := getColumn[agent.ExecutionStatus](ctx, tx, e.id, "status")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status.Revision++
+	status.State = agent.ExecutionSucceeded
+	status.FinishedAt = &now
+	status.UpdatedAt = now
+
+	encodedStatus, err := marshalJSON(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE executions SET result = ?, result_conversation_id = ?, status = ?, state = ?, updated_at = ? WHERE id = ?",
+		encodedResult, string(result.ConversationID), encodedStatus, string(status.State), status.UpdatedAt.UnixNano(), string(e.id))
+	if err != nil {
+		return fmt.Errorf("update execution result: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatus returns the lifecycle status for the execution.
+func (e *Execution) GetStatus(ctx context.Context) (agent.ExecutionStatus, error) {
+	return getColumn[agent.ExecutionStatus](ctx, e.db, e.id, "status")
+}
+
+// UpdateStatus stores the lifecycle status for the execution with the next revision.
+// The revision is checked and advanced in one transaction.
+func (e *Execution) UpdateStatus(ctx context.Context, status agent.ExecutionStatus) error {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := getColumn[agent.ExecutionStatus](ctx, tx, e.id, "status")
+	if err != nil {
+		return err
+	}
+
+	if status.Revision != current.Revision {
+		return fmt.Errorf("%w: revision %d, stored revision %d", agent.ErrExecutionStatusConflict, status.Revision, current.Revision)
+	}
+
+	status.Revision++
+	status.UpdatedAt = time.Now()
+
+	encodedStatus, err := marshalJSON(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE executions SET status = ?, state = ?, created_at = ?, updated_at = ? WHERE id = ?",
+		encodedStatus, string(status.State), status.CreatedAt.UnixNano(), status.UpdatedAt.UnixNano(), string(e.id))
+	if err != nil {
+		return fmt.Errorf("update execution status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AppendEvent adds a runtime event to the end of the execution event log.
+func (e *Execution) AppendEvent(ctx context.Context, envelope agent.RuntimeEventEnvelope) error {
+	exists, err := executionExists(ctx, e.db, e.id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return agent.ErrExecutionNotFound
+	}
+
+	return insertEvent(ctx, e.db, e.id, envelope)
+}
+
+// GetEvents returns the runtime events recorded for the execution, skipping the first offset events.
+func (e *Execution) GetEvents(ctx context.Context, offset int) ([]agent.RuntimeEventEnvelope, error) {
+	exists, err := executionExists(ctx, e.db, e.id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, agent.ErrExecutionNotFound
+	}
+
+	rows, err := e.db.QueryContext(ctx, "SELECT kind, payload FROM execution_events WHERE execution_id = ? ORDER BY seq LIMIT -1 OFFSET ?", string(e.id), max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("query execution events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []agent.RuntimeEventEnvelope{}
+	for rows.Next() {
+		var kind string
+		var payload string
+		if err := rows.Scan(&kind, &payload); err != nil {
+			return nil, fmt.Errorf("scan execution event: %w", err)
+		}
+
+		events = append(events, agent.RuntimeEventEnvelope{
+			Kind:    agent.RuntimeEventKind(kind),
+			Payload: json.RawMessage(payload),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query execution events: %w", err)
+	}
+
+	return events, nil
+}
+
+// queryer is implemented by both the database and its transactions.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func executionExists(ctx context.Context, db queryer, id agent.ExecutionID) (bool, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM executions WHERE id = ?)", string(id)).Scan(&exists); err != nil {
+		return false, fmt.Errorf("query execution: %w", err)
+	}
+
+	return exists, nil
+}
+
+// getColumn decodes a JSON column of the execution. A NULL column decodes to the zero value.
+func getColumn[T any](ctx context.Context, db queryer, id agent.ExecutionID, column string) (T, error) {
+	var result T
+
+	var value sql.NullString
+	err := db.QueryRowContext(ctx, "SELECT "+column+" FROM executions WHERE id = ?", string(id)).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, agent.ErrExecutionNotFound
+	}
+	if err != nil {
+		return result, fmt.Errorf("query execution %s: %w", column, err)
+	}
+
+	if !value.Valid {
+		return result, nil
+	}
+
+	if err := json.Unmarshal([]byte(value.String), &result); err != nil {
+		return result, fmt.Errorf("decode execution %s: %w", column, err)
+	}
+
+	return result, nil
+}
+
+func insertEvent(ctx context.Context, db queryer, id agent.ExecutionID, envelope agent.RuntimeEventEnvelope) error {
+	_, err := db.ExecContext(ctx, "INSERT INTO execution_events (execution_id, kind, payload) VALUES (?, ?, ?)",
+		string(id), string(envelope.Kind), string(envelope.Payload))
+	if err != nil {
+		return fmt.Errorf("insert execution event: %w", err)
+	}
+
+	return nil
+}
+
+func marshalJSON(data any) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("encode json: %w", err)
+	}
+
+	return string(b), nil
+}
+
+var (
+	// ErrExecutionExists indicates an imported execution is already stored.
+	ErrExecutionExists = errors.New("execution already exists")
+)
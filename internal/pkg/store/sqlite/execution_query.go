@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+)
+
+// Find returns execution identifiers matching the provided filters, in the query sort order.
+func (r *Repository) Find(ctx context.Context, filters ...agent.ExecutionFilter) ([]agent.ExecutionID, error) {
+	query := agent.NewExecutionQuery(filters...)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []any
+
+	if query.AgentID != nil {
+		conditions = append(conditions, "agent_id = ?")
+		args = append(args, string(*query.AgentID))
+	}
+
+	if query.RuntimeKind != nil {
+		conditions = append(conditions, "runtime_kind = ?")
+		args = append(args, string(*query.RuntimeKind))
+	}
+
+	if len(query.States) > 0 {
+		conditions = append(conditions, "state IN (?"+strings.Repeat(", ?", len(query.States)-1)+")")
+		for _, state := range query.States {
+			args = append(args, string(state))
+		}
+	}
+
+	if query.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.CreatedAfter.UnixNano())
+	}
+
+	if query.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.CreatedBefore.UnixNano())
+	}
+
+	if query.WorkingDirectory != nil {
+		conditions = append(conditions, "working_directory = ?")
+		args = append(args, *query.WorkingDirectory)
+	}
+
+	if query.ConversationID != nil {
+		conditions = append(conditions, "(input_conversation_id = ? OR result_conversation_id = ?)")
+		args = append(args, string(*query.ConversationID), string(*query.ConversationID))
+	}
+
+	for key, value := range query.Labels {
+		if value == "" {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM execution_labels WHERE execution_id = executions.id AND key = ?)")
+			args = append(args, key)
+		} else {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM execution_labels WHERE execution_id = executions.id AND key = ? AND value = ?)")
+			args = append(args, key, value)
+		}
+	}
+
+	if query.Cursor != nil {
+		cursorCreatedAt := query.Cursor.CreatedAt.UnixNano()
+		cursorID := string(query.Cursor.ID)
+
+		switch query.Sort {
+		case agent.ExecutionSortCreatedAsc:
+			conditions = append(conditions, "(created_at > ? OR (created_at = ? AND id > ?))")
+			args = append(args, cursorCreatedAt, cursorCreatedAt, cursorID)
+		case agent.ExecutionSortCreatedDesc:
+			conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id > ?))")
+			args = append(args, cursorCreatedAt, cursorCreatedAt, cursorID)
+		default:
+			conditions = append(conditions, "id > ?")
+			args = append(args, cursorID)
+		}
+	}
+
+	statement := "SELECT id FROM executions"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	switch query.Sort {
+	case agent.ExecutionSortCreatedAsc:
+		statement += " ORDER BY created_at, id"
+	case agent.ExecutionSortCreatedDesc:
+		statement += " ORDER BY created_at DESC, id"
+	default:
+		statement += " ORDER BY id"
+	}
+
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query executions: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []agent.ExecutionID{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan execution: %w", err)
+		}
+		ids = append(ids, agent.ExecutionID(id))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query executions: %w", err)
+	}
+
+	return ids, nil
+}
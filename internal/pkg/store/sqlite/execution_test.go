@@ -0,0 +1,318 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/agent"
+	fsstore "github.com/orbiqd/orbiqd-briefkit/internal/pkg/store/fs"
+	"github.com/orbiqd/orbiqd-briefkit/internal/pkg/utils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sampleAgentConfig = agent.Config{
+	Runtime: agent.ConfigRuntime{
+		Kind: agent.RuntimeKind("codex"),
+		Config: map[string]any{
+			"path": "/bin/agent",
+		},
+	},
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "briefkit.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func sampleInput() agent.ExecutionInput {
+	workingDir := "/app"
+
+	return agent.ExecutionInput{
+		Prompt:           "test prompt",
+		Timeout:          utils.Duration(5 * time.Minute),
+		WorkingDirectory: &workingDir,
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "briefkit.db")
+
+	db, err := Open(context.Background(), path)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = Open(context.Background(), path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var version int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, len(migrations), version)
+}
+
+func TestRepository_CreateGet(t *testing.T) {
+	repo := NewExecutionRepository(openTestDB(t))
+	ctx := context.Background()
+
+	t.Run("creates an execution", func(t *testing.T) {
+		id, err := repo.Create(ctx, sampleInput(), sampleAgentConfig, agent.WithAgentID("codex"), agent.WithLabels(map[string]string{"team": "payments"}))
+		require.NoError(t, err)
+		require.NoError(t, id.Validate())
+
+		exists, err := repo.Exists(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		execution, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+
+		input, err := execution.GetInput(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "test prompt", input.Prompt)
+
+		config, err := execution.GetAgentConfig(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, sampleAgentConfig.Runtime.Kind, config.Runtime.Kind)
+
+		metadata, err := execution.GetMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.AgentID("codex"), metadata.AgentID)
+		assert.Equal(t, map[string]string{"team": "payments"}, metadata.Labels)
+
+		status, err := execution.GetStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, agent.ExecutionCreated, status.State)
+
+		_, err = execution.GetResult(ctx)
+		require.ErrorIs(t, err, agent.ErrExecutionNoResult)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		_, err := repo.Create(ctx, agent.ExecutionInput{}, sampleAgentConfig)
+		require.ErrorIs(t, err, agent.ErrExecutionPromptRequired)
+	})
+
+	t.Run("missing execution", func(t *testing.T) {
+		_, err := repo.Get(ctx, agent.NewExecutionID())
+		require.ErrorIs(t, err, agent.ErrExecutionNotFound)
+
+		_, err = repo.Get(ctx, "not-a-uuid")
+		require.ErrorIs(t, err, agent.ErrExecutionIDInvalid)
+	})
+}
+
+func TestExecution_StatusResultEvents(t *testing.T) {
+	repo := NewExecutionRepository(openTestDB(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, sampleInput(), sampleAgentConfig)
+	require.NoError(t, err)
+
+	execution, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+
+	status, err := execution.GetStatus(ctx)
+	require.NoError(t, err)
+	status.State = agent.ExecutionRunning
+	status.Attempts = 1
+	require.NoError(t, execution.UpdateStatus(ctx, status))
+
+	status, err = execution.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ExecutionRunning, status.State)
+	assert.Equal(t, 1, status.Attempts)
+	assert.Equal(t, int64(1), status.Revision)
+
+	stale := status
+	stale.Revision--
+	require.ErrorIs(t, execution.UpdateStatus(ctx, stale), agent.ErrExecutionStatusConflict)
+
+	envelope, err := agent.NewRuntimeEventEnvelope(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: "Hello"})
+	require.NoError(t, err)
+	require.NoError(t, execution.AppendEvent(ctx, envelope))
+	require.NoError(t, execution.AppendEvent(ctx, envelope))
+
+	events, err := execution.GetEvents(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, agent.RuntimeEventMessage, events[0].Kind)
+	assert.JSONEq(t, string(envelope.Payload), string(events[0].Payload))
+
+	hasResult, err := execution.HasResult(ctx)
+	require.NoError(t, err)
+	assert.False(t, hasResult)
+
+	require.NoError(t, execution.SetResult(ctx, agent.ExecutionResult{Response: "done", ConversationID: "conv-1"}))
+
+	result, err := execution.GetResult(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Response)
+
+	status, err = execution.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ExecutionSucceeded, status.State)
+	assert.NotNil(t, status.FinishedAt)
+	assert.Equal(t, 1, status.Attempts)
+	assert.Equal(t, int64(2), status.Revision)
+}
+
+func TestRepository_Find(t *testing.T) {
+	repo := NewExecutionRepository(openTestDB(t))
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, sampleInput(), sampleAgentConfig, agent.WithAgentID("codex"), agent.WithLabels(map[string]string{"team": "payments"}))
+	require.NoError(t, err)
+
+	second, err := repo.Create(ctx, sampleInput(), sampleAgentConfig, agent.WithAgentID("codex"))
+	require.NoError(t, err)
+
+	third, err := repo.Create(ctx, sampleInput(), agent.Config{Runtime: agent.ConfigRuntime{Kind: "gemini"}}, agent.WithAgentID("gemini"))
+	require.NoError(t, err)
+
+	for i, id := range []agent.ExecutionID{first, second, third} {
+		execution, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		status, err := execution.GetStatus(ctx)
+		require.NoError(t, err)
+		status.CreatedAt = time.Date(2025, 1, 1, 0, i, 0, 0, time.UTC)
+		require.NoError(t, execution.UpdateStatus(ctx, status))
+	}
+
+	execution, err := repo.Get(ctx, second)
+	require.NoError(t, err)
+	require.NoError(t, execution.SetResult(ctx, agent.ExecutionResult{ConversationID: "conv-1"}))
+
+	tests := []struct {
+		name    string
+		filters []agent.ExecutionFilter
+		ids     []agent.ExecutionID
+	}{
+		{name: "agent newest first", filters: []agent.ExecutionFilter{agent.FilterByAgentID("codex"), agent.SortExecutions(agent.ExecutionSortCreatedDesc)}, ids: []agent.ExecutionID{second, first}},
+		{name: "runtime kind", filters: []agent.ExecutionFilter{agent.FilterByRuntimeKind("gemini")}, ids: []agent.ExecutionID{third}},
+		{name: "state and conversation", filters: []agent.ExecutionFilter{agent.FilterByState(agent.ExecutionSucceeded), agent.FilterByConversationID("conv-1")}, ids: []agent.ExecutionID{second}},
+		{name: "created range", filters: []agent.ExecutionFilter{agent.FilterCreatedAfter(time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC)), agent.FilterCreatedBefore(time.Date(2025, 1, 1, 0, 2, 0, 0, time.UTC))}, ids: []agent.ExecutionID{second}},
+		{name: "label and working directory", filters: []agent.ExecutionFilter{agent.FilterByLabel("team", ""), agent.FilterByWorkingDirectory("/app/")}, ids: []agent.ExecutionID{first}},
+		{name: "label value mismatch", filters: []agent.ExecutionFilter{agent.FilterByLabel("team", "search")}, ids: []agent.ExecutionID{}},
+		{name: "page after cursor", filters: []agent.ExecutionFilter{agent.SortExecutions(agent.ExecutionSortCreatedAsc), agent.LimitExecutions(1), agent.ExecutionsAfter(agent.ExecutionCursor{CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ID: first})}, ids: []agent.ExecutionID{second}},
+		{name: "page after cursor newest first", filters: []agent.ExecutionFilter{agent.SortExecutions(agent.ExecutionSortCreatedDesc), agent.ExecutionsAfter(agent.ExecutionCursor{CreatedAt: time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC), ID: second})}, ids: []agent.ExecutionID{first}},
+		{name: "page after deleted cursor", filters: []agent.ExecutionFilter{agent.SortExecutions(agent.ExecutionSortCreatedAsc), agent.ExecutionsAfter(agent.ExecutionCursor{CreatedAt: time.Date(2025, 1, 1, 0, 1, 30, 0, time.UTC), ID: agent.NewExecutionID()})}, ids: []agent.ExecutionID{third}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := repo.Find(ctx, tt.filters...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := repo.Find(ctx, agent.LimitExecutions(-1))
+		require.ErrorIs(t, err, agent.ErrExecutionLimitInvalid)
+	})
+}
+
+func TestRepository_Delete(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewExecutionRepository(db)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, sampleInput(), sampleAgentConfig, agent.WithLabels(map[string]string{"team": "payments"}))
+	require.NoError(t, err)
+
+	execution, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+
+	envelope, err := agent.NewRuntimeEventEnvelope(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: "Hello"})
+	require.NoError(t, err)
+	require.NoError(t, execution.AppendEvent(ctx, envelope))
+
+	// A created execution is about to be picked up by its runner.
+	require.ErrorIs(t, repo.Delete(ctx, id), agent.ErrExecutionActive)
+
+	status, err := execution.GetStatus(ctx)
+	require.NoError(t, err)
+	status.State = agent.ExecutionSucceeded
+	require.NoError(t, execution.UpdateStatus(ctx, status))
+
+	require.NoError(t, repo.Delete(ctx, id))
+
+	exists, err := repo.Exists(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	var rows int
+	require.NoError(t, db.QueryRow("SELECT (SELECT COUNT(*) FROM execution_labels) + (SELECT COUNT(*) FROM execution_events)").Scan(&rows))
+	assert.Zero(t, rows)
+
+	require.ErrorIs(t, repo.Delete(ctx, id), agent.ErrExecutionNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, "not-a-uuid"), agent.ErrExecutionIDInvalid)
+
+	running, err := repo.Create(ctx, sampleInput(), sampleAgentConfig)
+	require.NoError(t, err)
+
+	execution, err = repo.Get(ctx, running)
+	require.NoError(t, err)
+	status, err = execution.GetStatus(ctx)
+	require.NoError(t, err)
+	status.State = agent.ExecutionRunning
+	require.NoError(t, execution.UpdateStatus(ctx, status))
+
+	require.ErrorIs(t, repo.Delete(ctx, running), agent.ErrExecutionActive)
+	require.NoError(t, repo.Delete(ctx, running, agent.ForceDelete()))
+}
+
+func TestRepository_Import(t *testing.T) {
+	ctx := context.Background()
+
+	source, err := fsstore.NewExecutionRepository("/executions", afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	id, err := source.Create(ctx, sampleInput(), sampleAgentConfig, agent.WithAgentID("codex"))
+	require.NoError(t, err)
+
+	sourceExecution, err := source.Get(ctx, id)
+	require.NoError(t, err)
+
+	envelope, err := agent.NewRuntimeEventEnvelope(agent.RuntimeMessageEvent{Timestamp: time.Now(), Text: "Hello"})
+	require.NoError(t, err)
+	require.NoError(t, sourceExecution.AppendEvent(ctx, envelope))
+	require.NoError(t, sourceExecution.SetResult(ctx, agent.ExecutionResult{Response: "done", Structured: json.RawMessage(`{"ok":true}`)}))
+
+	repo := NewExecutionRepository(openTestDB(t))
+	require.NoError(t, repo.Import(ctx, id, sourceExecution))
+	require.ErrorIs(t, repo.Import(ctx, id, sourceExecution), ErrExecutionExists)
+
+	execution, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+
+	status, err := execution.GetStatus(ctx)
+	require.NoError(t, err)
+	sourceStatus, err := sourceExecution.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, sourceStatus.CreatedAt.Equal(status.CreatedAt))
+	assert.Equal(t, agent.ExecutionSucceeded, status.State)
+
+	result, err := execution.GetResult(ctx)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":true}`, string(result.Structured))
+
+	events, err := execution.GetEvents(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	ids, err := repo.Find(ctx, agent.FilterByAgentID("codex"), agent.FilterByState(agent.ExecutionSucceeded))
+	require.NoError(t, err)
+	assert.Equal(t, []agent.ExecutionID{id}, ids)
+}
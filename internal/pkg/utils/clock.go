@@ -0,0 +1,45 @@
+package utils
+
+import "time"
+
+// NextTimeOfDay returns the first moment at or after now whose wall clock in now's location reads hour:minute.
+func NextTimeOfDay(now time.Time, hour int, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if next.Before(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
+
+// ClockHour converts a 12-hour clock value with an "am" or "pm" suffix to a 24-hour value.
+// Values without a suffix are returned unchanged.
+func ClockHour(hour int, meridiem string) int {
+	switch meridiem {
+	case "am", "AM":
+		if hour == 12 {
+			return 0
+		}
+	case "pm", "PM":
+		if hour < 12 {
+			return hour + 12
+		}
+	}
+
+	return hour
+}
+
+// FormatClockTime formats t as a wall clock time in now's location, adding the date when t falls on another day than now.
+func FormatClockTime(t time.Time, now time.Time) string {
+	t = t.In(now.Location())
+
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04")
+	}
+
+	if t.Year() == now.Year() {
+		return t.Format("Jan 2 15:04")
+	}
+
+	return t.Format("Jan 2 2006 15:04")
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextTimeOfDay(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC), NextTimeOfDay(now, 15, 0))
+	assert.Equal(t, time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC), NextTimeOfDay(now, 9, 0))
+}
+
+func TestClockHour(t *testing.T) {
+	assert.Equal(t, 0, ClockHour(12, "am"))
+	assert.Equal(t, 12, ClockHour(12, "pm"))
+	assert.Equal(t, 15, ClockHour(3, "PM"))
+	assert.Equal(t, 9, ClockHour(9, ""))
+}
+
+func TestFormatClockTime(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "15:00", FormatClockTime(time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, "Mar 11 09:00", FormatClockTime(time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, "Jan 1 2026 00:00", FormatClockTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, "Mar 11 00:30", FormatClockTime(time.Date(2025, 3, 10, 23, 30, 0, 0, time.FixedZone("", -3600)), now))
+}
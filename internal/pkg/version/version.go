@@ -0,0 +1,165 @@
+// Package version parses semantic versions and simple version constraints.
+package version
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
+
+// Version is a semantic version without pre-release or build metadata.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Parse parses a version such as "1.2.3" or "v1.2.3". Pre-release and build suffixes are ignored.
+// Returns ErrVersionInvalid when the value does not start with a version.
+func Parse(value string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return Version{}, fmt.Errorf("%w: %q", ErrVersionInvalid, value)
+	}
+
+	var parts [3]int
+	for i := range parts {
+		number, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrVersionInvalid, value)
+		}
+		parts[i] = number
+	}
+
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, nil
+}
+
+// Compare returns -1, 0 or 1 when the version is lower than, equal to or greater than other.
+func (version Version) Compare(other Version) int {
+	for _, pair := range [][2]int{
+		{version.Major, other.Major},
+		{version.Minor, other.Minor},
+		{version.Patch, other.Patch},
+	} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (version Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+type operator string
+
+const (
+	operatorEqual          operator = "="
+	operatorGreater        operator = ">"
+	operatorGreaterOrEqual operator = ">="
+	operatorLess           operator = "<"
+	operatorLessOrEqual    operator = "<="
+)
+
+// operators is ordered so that two-character operators are matched first.
+var operators = []operator{operatorGreaterOrEqual, operatorLessOrEqual, operatorGreater, operatorLess, operatorEqual}
+
+type term struct {
+	operator operator
+	version  Version
+}
+
+// Constraint is a set of comparisons a version must all satisfy, for example ">=1.0.0, <2.0.0".
+type Constraint struct {
+	raw   string
+	terms []term
+}
+
+// ParseConstraint parses comparisons separated by commas or spaces.
+// Supported operators are =, >, >=, < and <=; a bare version means an exact match.
+// Returns ErrConstraintInvalid when the constraint is empty or malformed.
+func ParseConstraint(value string) (Constraint, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return Constraint{}, fmt.Errorf("%w: empty", ErrConstraintInvalid)
+	}
+
+	constraint := Constraint{raw: strings.TrimSpace(value)}
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		op := operatorEqual
+		for _, candidate := range operators {
+			if strings.HasPrefix(field, string(candidate)) {
+				op = candidate
+				field = strings.TrimPrefix(field, string(candidate))
+				break
+			}
+		}
+
+		// Allow a space between the operator and the version, as in ">= 1.0.0".
+		if field == "" && i+1 < len(fields) {
+			i++
+			field = fields[i]
+		}
+
+		parsed, err := Parse(field)
+		if err != nil || parsed.String() != strings.TrimPrefix(field, "v") {
+			return Constraint{}, fmt.Errorf("%w: %q", ErrConstraintInvalid, value)
+		}
+
+		constraint.terms = append(constraint.terms, term{operator: op, version: parsed})
+	}
+
+	return constraint, nil
+}
+
+// Check reports whether the version satisfies every comparison of the constraint.
+func (constraint Constraint) Check(version Version) bool {
+	for _, term := range constraint.terms {
+		comparison := version.Compare(term.version)
+
+		var satisfied bool
+		switch term.operator {
+		case operatorEqual:
+			satisfied = comparison == 0
+		case operatorGreater:
+			satisfied = comparison > 0
+		case operatorGreaterOrEqual:
+			satisfied = comparison >= 0
+		case operatorLess:
+			satisfied = comparison < 0
+		case operatorLessOrEqual:
+			satisfied = comparison <= 0
+		}
+
+		if !satisfied {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (constraint Constraint) String() string {
+	return constraint.raw
+}
+
+var (
+	// ErrVersionInvalid indicates the value is not a semantic version.
+	ErrVersionInvalid = errors.New("version invalid")
+
+	// ErrConstraintInvalid indicates the version constraint is empty or malformed.
+	ErrConstraintInvalid = errors.New("version constraint invalid")
+)
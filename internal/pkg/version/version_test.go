@@ -0,0 +1,58 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	parsed, err := Parse("v2.0.14-beta.1")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 2, Minor: 0, Patch: 14}, parsed)
+	assert.Equal(t, "2.0.14", parsed.String())
+
+	_, err = Parse("2.0")
+	require.ErrorIs(t, err, ErrVersionInvalid)
+}
+
+func TestVersionCompare(t *testing.T) {
+	assert.Equal(t, 0, Version{1, 2, 3}.Compare(Version{1, 2, 3}))
+	assert.Equal(t, -1, Version{1, 2, 3}.Compare(Version{1, 10, 0}))
+	assert.Equal(t, 1, Version{2, 0, 0}.Compare(Version{1, 99, 99}))
+}
+
+func TestParseConstraint(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		for _, value := range []string{"", " , ", ">=1.0", "~1.0.0", ">=1.0.0 <abc"} {
+			_, err := ParseConstraint(value)
+			require.ErrorIs(t, err, ErrConstraintInvalid, value)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		constraint, err := ParseConstraint(">=1.0.0, <2.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, ">=1.0.0, <2.0.0", constraint.String())
+
+		assert.False(t, constraint.Check(Version{0, 9, 9}))
+		assert.True(t, constraint.Check(Version{1, 0, 0}))
+		assert.True(t, constraint.Check(Version{1, 99, 0}))
+		assert.False(t, constraint.Check(Version{2, 0, 0}))
+	})
+
+	t.Run("spaced operator", func(t *testing.T) {
+		constraint, err := ParseConstraint(">= 0.44.0 <= 0.50.1")
+		require.NoError(t, err)
+		assert.True(t, constraint.Check(Version{0, 50, 1}))
+		assert.False(t, constraint.Check(Version{0, 50, 2}))
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		constraint, err := ParseConstraint("2.0.14")
+		require.NoError(t, err)
+		assert.True(t, constraint.Check(Version{2, 0, 14}))
+		assert.False(t, constraint.Check(Version{2, 0, 15}))
+	})
+}